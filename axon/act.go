@@ -6,12 +6,19 @@ package axon
 
 import (
 	"github.com/emer/axon/chans"
-	"github.com/emer/emergent/erand"
 	"github.com/emer/etable/minmax"
 	"github.com/goki/gosl/slbool"
+	"github.com/goki/gosl/slrand"
+	"github.com/goki/ki/kit"
 	"github.com/goki/mat32"
 )
 
+//go:generate stringer -type=ClampModes
+//go:generate stringer -type=ClampPhases
+
+var KiT_ClampModes = kit.Enums.AddEnum(ClampModesN, kit.NotBitFlag, nil)
+var KiT_ClampPhases = kit.Enums.AddEnum(ClampPhasesN, kit.NotBitFlag, nil)
+
 ///////////////////////////////////////////////////////////////////////
 //  act.go contains the activation params and functions for axon
 
@@ -198,11 +205,13 @@ func (ai *ActInitParams) Defaults() {
 
 //gosl: end act
 
-// GeBase returns the baseline Ge value: Ge + rand(GeVar) > 0
-func (ai *ActInitParams) GetGeBase(rnd erand.Rand) float32 {
+// GeBase returns the baseline Ge value: Ge + rand(GeVar) > 0,
+// using the RandFunGeBase random stream for given neuron index,
+// with given counter (the network RandCtr).
+func (ai *ActInitParams) GetGeBase(ctr slrand.Counter, ni uint32) float32 {
 	ge := ai.GeBase
 	if ai.GeVar > 0 {
-		ge += float32(float64(ai.GeVar) * rnd.NormFloat64(-1))
+		ge += ai.GeVar * GetRandomNormal(ni, ctr, RandFunGeBase)
 		if ge < 0 {
 			ge = 0
 		}
@@ -210,11 +219,13 @@ func (ai *ActInitParams) GetGeBase(rnd erand.Rand) float32 {
 	return ge
 }
 
-// GiBase returns the baseline Gi value: Gi + rand(GiVar) > 0
-func (ai *ActInitParams) GetGiBase(rnd erand.Rand) float32 {
+// GiBase returns the baseline Gi value: Gi + rand(GiVar) > 0,
+// using the RandFunGiBase random stream for given neuron index,
+// with given counter (the network RandCtr).
+func (ai *ActInitParams) GetGiBase(ctr slrand.Counter, ni uint32) float32 {
 	gi := ai.GiBase
 	if ai.GiVar > 0 {
-		gi += float32(float64(ai.GiVar) * rnd.NormFloat64(-1))
+		gi += ai.GiVar * GetRandomNormal(ni, ctr, RandFunGiBase)
 		if gi < 0 {
 			gi = 0
 		}
@@ -451,9 +462,186 @@ func (an *SpikeNoiseParams) PGi(ctx *Context, p *float32, ni uint32) float32 {
 	return 0
 }
 
+// OUNoiseParams parameterizes Ornstein-Uhlenbeck (OU) conductance noise,
+// simulating the aggregate effect of background synaptic bombardment as a
+// continuous, temporally correlated process (as in the point-conductance
+// model of Destexhe et al., 2001), with a zero mean (use Init.GeBase,
+// GiBase for a mean background conductance) and stationary standard
+// deviation Ge, Gi.  A proportion Corr of the driving noise is shared
+// across all neurons in the same pool (sub-pool for 4D layers), so the
+// noise is correlated with coefficient Corr between neurons in a pool,
+// simulating shared inputs.  The noise is independent across data parallel
+// items, and deterministic via the counter-based random number streams.
+type OUNoiseParams struct {
+
+	// add Ornstein-Uhlenbeck conductance noise, in GeNoiseOU and GiNoiseOU
+	On slbool.Bool `desc:"add Ornstein-Uhlenbeck conductance noise, in GeNoiseOU and GiNoiseOU"`
+
+	// [def: 3] [viewif: On] [min: 1] time constant in cycles (msec) of the excitatory noise -- the autocorrelation of the noise decays exponentially with this time constant
+	GeTau float32 `viewif:"On" def:"3" min:"1" desc:"time constant in cycles (msec) of the excitatory noise -- the autocorrelation of the noise decays exponentially with this time constant"`
+
+	// [def: 10] [viewif: On] [min: 1] time constant in cycles (msec) of the inhibitory noise -- the autocorrelation of the noise decays exponentially with this time constant
+	GiTau float32 `viewif:"On" def:"10" min:"1" desc:"time constant in cycles (msec) of the inhibitory noise -- the autocorrelation of the noise decays exponentially with this time constant"`
+
+	// [viewif: On] [min: 0] standard deviation (sigma) of the excitatory noise conductance -- the total Ge is kept >= 0
+	Ge float32 `viewif:"On" min:"0" desc:"standard deviation (sigma) of the excitatory noise conductance -- the total Ge is kept >= 0"`
+
+	// [viewif: On] [min: 0] standard deviation (sigma) of the inhibitory noise conductance -- the total Gi is kept >= 0
+	Gi float32 `viewif:"On" min:"0" desc:"standard deviation (sigma) of the inhibitory noise conductance -- the total Gi is kept >= 0"`
+
+	// [viewif: On] [min: 0] [max: 1] correlation coefficient of the noise between neurons in the same pool -- proportion of the noise variance driven by a source shared across the pool
+	Corr float32 `viewif:"On" min:"0" max:"1" desc:"correlation coefficient of the noise between neurons in the same pool -- proportion of the noise variance driven by a source shared across the pool"`
+
+	// [view: -] Exp(-1/GeTau) decay factor per cycle
+	GeDecay float32 `view:"-" json:"-" xml:"-" desc:"Exp(-1/GeTau) decay factor per cycle"`
+
+	// [view: -] Exp(-1/GiTau) decay factor per cycle
+	GiDecay float32 `view:"-" json:"-" xml:"-" desc:"Exp(-1/GiTau) decay factor per cycle"`
+
+	// [view: -] Ge * Sqrt(1 - GeDecay^2) -- noise added per cycle to maintain Ge stationary sigma
+	GeStep float32 `view:"-" json:"-" xml:"-" desc:"Ge * Sqrt(1 - GeDecay^2) -- noise added per cycle to maintain Ge stationary sigma"`
+
+	// [view: -] Gi * Sqrt(1 - GiDecay^2) -- noise added per cycle to maintain Gi stationary sigma
+	GiStep float32 `view:"-" json:"-" xml:"-" desc:"Gi * Sqrt(1 - GiDecay^2) -- noise added per cycle to maintain Gi stationary sigma"`
+
+	// [view: -] Sqrt(1 - Corr) weight on private noise
+	PrivW float32 `view:"-" json:"-" xml:"-" desc:"Sqrt(1 - Corr) weight on private noise"`
+
+	// [view: -] Sqrt(Corr) weight on pool-shared noise
+	PoolW float32 `view:"-" json:"-" xml:"-" desc:"Sqrt(Corr) weight on pool-shared noise"`
+}
+
+func (on *OUNoiseParams) Update() {
+	on.GeDecay = mat32.Exp(-1.0 / on.GeTau)
+	on.GiDecay = mat32.Exp(-1.0 / on.GiTau)
+	on.GeStep = on.Ge * mat32.Sqrt(1-on.GeDecay*on.GeDecay)
+	on.GiStep = on.Gi * mat32.Sqrt(1-on.GiDecay*on.GiDecay)
+	on.PrivW = mat32.Sqrt(1 - on.Corr)
+	on.PoolW = mat32.Sqrt(on.Corr)
+}
+
+func (on *OUNoiseParams) Defaults() {
+	on.GeTau = 3
+	on.GiTau = 10
+	on.Ge = 0.01
+	on.Gi = 0.01
+	on.Update()
+}
+
+// GeStepFmRnd returns the new excitatory OU noise value given current value,
+// and private and pool-shared unit normal random deviates
+func (on *OUNoiseParams) GeStepFmRnd(ge, priv, pool float32) float32 {
+	return on.GeDecay*ge + on.GeStep*(on.PrivW*priv+on.PoolW*pool)
+}
+
+// GiStepFmRnd returns the new inhibitory OU noise value given current value,
+// and private and pool-shared unit normal random deviates
+func (on *OUNoiseParams) GiStepFmRnd(gi, priv, pool float32) float32 {
+	return on.GiDecay*gi + on.GiStep*(on.PrivW*priv+on.PoolW*pool)
+}
+
 //////////////////////////////////////////////////////////////////////////////////////
 //  ClampParams
 
+// ClampModes are ways that external inputs drive neurons under
+// a ClampSchedParams schedule.
+type ClampModes int32
+
+const (
+	// ClampExt hard-clamps the neuron Ge to the Ext value times Clamp.Ge,
+	// overwriting any synaptic input, as in a standard Input layer.
+	ClampExt ClampModes = iota
+
+	// ClampGe adds the Ext value times Clamp.Ge as an excitatory
+	// conductance on top of synaptic input, as with Clamp.Add.
+	ClampGe
+
+	ClampModesN
+)
+
+// ClampPhases specify the phases of the theta cycle in which
+// external inputs are applied under a ClampSchedParams schedule.
+type ClampPhases int32
+
+const (
+	// ClampBothPhases applies inputs in both the minus and plus phases.
+	ClampBothPhases ClampPhases = iota
+
+	// ClampMinusOnly applies inputs only in the minus phase.
+	ClampMinusOnly
+
+	// ClampPlusOnly applies inputs only in the plus phase.
+	ClampPlusOnly
+
+	ClampPhasesN
+)
+
+// ClampSchedParams specify a schedule for the timing of external inputs
+// within a theta cycle (trial), in terms of Context.Cycle, which is reset
+// at NewState.  This allows stimuli to appear at specific cycles without
+// custom looper events: inputs are applied once per trial via ApplyExt
+// (and ApplyExts for the GPU), and the schedule is evaluated every cycle
+// in GeFmSyn, on the CPU and GPU.
+type ClampSchedParams struct {
+
+	// use the clamp schedule -- if false, external inputs are applied for the entire trial according to the standard Clamp parameters
+	On slbool.Bool `desc:"use the clamp schedule -- if false, external inputs are applied for the entire trial according to the standard Clamp parameters"`
+
+	// [viewif: On] whether inputs hard-clamp Ge (Ext) or add to synaptic Ge (Ge) -- overrides Clamp.Add when On
+	Mode ClampModes `viewif:"On" desc:"whether inputs hard-clamp Ge (Ext) or add to synaptic Ge (Ge) -- overrides Clamp.Add when On"`
+
+	// [viewif: On] phases of the theta cycle in which inputs are applied
+	Phase ClampPhases `viewif:"On" desc:"phases of the theta cycle in which inputs are applied"`
+
+	// [viewif: On] [min: 0] cycle within the trial (Context.Cycle) at which inputs start being applied
+	Onset int32 `viewif:"On" min:"0" desc:"cycle within the trial (Context.Cycle) at which inputs start being applied"`
+
+	// [viewif: On] cycle within the trial (Context.Cycle) at which inputs stop being applied -- 0 or less = end of trial
+	Offset int32 `viewif:"On" desc:"cycle within the trial (Context.Cycle) at which inputs stop being applied -- 0 or less = end of trial"`
+
+	// [viewif: On] [min: 0] number of cycles over which the input strength linearly ramps up after Onset, and down before Offset -- 0 = step on and off
+	Ramp int32 `viewif:"On" min:"0" desc:"number of cycles over which the input strength linearly ramps up after Onset, and down before Offset -- 0 = step on and off"`
+
+	pad, pad1 int32
+}
+
+func (cs *ClampSchedParams) Update() {
+}
+
+func (cs *ClampSchedParams) Defaults() {
+	cs.Mode = ClampExt
+	cs.Phase = ClampBothPhases
+	cs.Onset = 0
+	cs.Offset = 0
+	cs.Ramp = 0
+}
+
+// Factor returns the strength of external input, between 0 and 1,
+// for the current cycle and phase in given Context.
+func (cs *ClampSchedParams) Factor(ctx *Context) float32 {
+	if cs.Phase == ClampMinusOnly && ctx.PlusPhase.IsTrue() {
+		return 0
+	}
+	if cs.Phase == ClampPlusOnly && ctx.PlusPhase.IsFalse() {
+		return 0
+	}
+	cyc := ctx.Cycle
+	if cyc < cs.Onset || (cs.Offset > 0 && cyc >= cs.Offset) {
+		return 0
+	}
+	if cs.Ramp <= 0 {
+		return 1
+	}
+	fact := float32(1)
+	if cyc-cs.Onset < cs.Ramp {
+		fact = float32(cyc-cs.Onset+1) / float32(cs.Ramp)
+	}
+	if cs.Offset > 0 && cs.Offset-cyc < cs.Ramp {
+		fact = mat32.Min(fact, float32(cs.Offset-cyc)/float32(cs.Ramp))
+	}
+	return fact
+}
+
 // ClampParams specify how external inputs drive excitatory conductances
 // (like a current clamp) -- either adds or overwrites existing conductances.
 // Noise is added in either case.
@@ -475,14 +663,31 @@ type ClampParams struct {
 	ErrThr float32 `def:"0.5" desc:"threshold on neuron Act activity to count as active for computing error relative to target in PctErr method"`
 
 	pad, pad1, pad2 float32
+
+	// [view: inline] schedule for the timing of external inputs within the trial: onset / offset cycle, ramp, Ge vs Ext mode, and minus / plus phase
+	Sched ClampSchedParams `view:"inline" desc:"schedule for the timing of external inputs within the trial: onset / offset cycle, ramp, Ge vs Ext mode, and minus / plus phase"`
 }
 
 func (cp *ClampParams) Update() {
+	cp.Sched.Update()
 }
 
 func (cp *ClampParams) Defaults() {
 	cp.Ge = 0.8
 	cp.ErrThr = 0.5
+	cp.Sched.Defaults()
+}
+
+// SchedGe sets the effective Clamp Ge value for the current cycle and
+// whether it is added to synaptic Ge (else hard clamped),
+// taking into account the Sched clamp schedule if On.
+func (cp *ClampParams) SchedGe(ctx *Context, ge *float32, add *bool) {
+	*ge = cp.Ge
+	*add = cp.Add.IsTrue()
+	if cp.Sched.On.IsTrue() {
+		*ge *= cp.Sched.Factor(ctx)
+		*add = (cp.Sched.Mode == ClampGe)
+	}
 }
 
 //////////////////////////////////////////////////////////////////////////////////////
@@ -697,6 +902,9 @@ type ActParams struct {
 
 	// [view: inline] provides encoding population codes, used to represent a single continuous (scalar) value, across a population of units / neurons (1 dimensional)
 	PopCode PopCodeParams `view:"inline" desc:"provides encoding population codes, used to represent a single continuous (scalar) value, across a population of units / neurons (1 dimensional)"`
+
+	// [view: inline] Ornstein-Uhlenbeck conductance noise, optionally correlated within pools
+	OUNoise OUNoiseParams `view:"inline" desc:"Ornstein-Uhlenbeck conductance noise, optionally correlated within pools"`
 }
 
 func (ac *ActParams) Defaults() {
@@ -709,6 +917,7 @@ func (ac *ActParams) Defaults() {
 	ac.Erev.SetAll(1.0, 0.3, 0.1, 0.1) // E, L, I, K: K = hyperpolarized -90mv
 	ac.Clamp.Defaults()
 	ac.Noise.Defaults()
+	ac.OUNoise.Defaults()
 	ac.VmRange.Set(0.1, 1.0)
 	ac.Mahp.Defaults()
 	ac.Mahp.Gbar = 0.02
@@ -744,6 +953,7 @@ func (ac *ActParams) Update() {
 	ac.Dt.Update()
 	ac.Clamp.Update()
 	ac.Noise.Update()
+	ac.OUNoise.Update()
 	ac.Mahp.Update()
 	ac.Sahp.Update()
 	ac.KNa.Update()
@@ -864,6 +1074,7 @@ func (ac *ActParams) DecayState(ctx *Context, ni, di uint32, decay, glong, ahp f
 	SetNrnV(ctx, ni, di, GMaintRaw, 0)
 	SetNrnV(ctx, ni, di, SSGi, 0)
 	SetNrnV(ctx, ni, di, SSGiDend, 0)
+	SetNrnV(ctx, ni, di, GiTopo, 0)
 	SetNrnV(ctx, ni, di, GeExt, 0)
 
 	AddNrnV(ctx, ni, di, CtxtGeOrig, -glong*NrnV(ctx, ni, di, CtxtGeOrig))
@@ -899,6 +1110,8 @@ func (ac *ActParams) InitActs(ctx *Context, ni, di uint32) {
 	SetNrnV(ctx, ni, di, GeNoise, 0)
 	SetNrnV(ctx, ni, di, GiNoiseP, 1)
 	SetNrnV(ctx, ni, di, GiNoise, 0)
+	SetNrnV(ctx, ni, di, GeNoiseOU, 0)
+	SetNrnV(ctx, ni, di, GiNoiseOU, 0)
 
 	SetNrnV(ctx, ni, di, GiSyn, 0)
 	SetNrnV(ctx, ni, di, GeInt, 0)
@@ -942,6 +1155,7 @@ func (ac *ActParams) InitActs(ctx *Context, ni, di uint32) {
 
 	SetNrnV(ctx, ni, di, SSGi, 0)
 	SetNrnV(ctx, ni, di, SSGiDend, 0)
+	SetNrnV(ctx, ni, di, GiTopo, 0)
 
 	SetNrnV(ctx, ni, di, Burst, 0)
 	SetNrnV(ctx, ni, di, BurstPrv, 0)
@@ -950,6 +1164,9 @@ func (ac *ActParams) InitActs(ctx *Context, ni, di uint32) {
 	SetNrnV(ctx, ni, di, CtxtGeRaw, 0)
 	SetNrnV(ctx, ni, di, CtxtGeOrig, 0)
 
+	SetNrnV(ctx, ni, di, SRPrv, 0)
+	SetNrnV(ctx, ni, di, SRErr, 0)
+
 	ac.InitLongActs(ctx, ni, di)
 }
 
@@ -1051,14 +1268,21 @@ func (ac *ActParams) GSkCaFmCa(ctx *Context, ni, di uint32) {
 // geExt is extra conductance to add to the final Ge value
 func (ac *ActParams) GeFmSyn(ctx *Context, ni, di uint32, geSyn, geExt float32) {
 	SetNrnV(ctx, ni, di, GeExt, 0)
-	if ac.Clamp.Add.IsTrue() && NrnHasFlag(ctx, ni, di, NeuronHasExt) {
-		SetNrnV(ctx, ni, di, GeExt, NrnV(ctx, ni, di, Ext)*ac.Clamp.Ge)
+	clampGe := float32(0)
+	clampAdd := false
+	ac.Clamp.SchedGe(ctx, &clampGe, &clampAdd)
+	hasExt := NrnHasFlag(ctx, ni, di, NeuronHasExt)
+	if ac.Clamp.Sched.On.IsTrue() && clampGe == 0 { // off in schedule = no ext
+		hasExt = false
+	}
+	if clampAdd && hasExt {
+		SetNrnV(ctx, ni, di, GeExt, NrnV(ctx, ni, di, Ext)*clampGe)
 		geSyn += NrnV(ctx, ni, di, GeExt)
 	}
 	geSyn = ac.AttnMod.ModVal(geSyn, NrnV(ctx, ni, di, Attn))
 
-	if ac.Clamp.Add.IsFalse() && NrnHasFlag(ctx, ni, di, NeuronHasExt) { // todo: this flag check is not working
-		geSyn = NrnV(ctx, ni, di, Ext) * ac.Clamp.Ge
+	if !clampAdd && hasExt { // todo: this flag check is not working
+		geSyn = NrnV(ctx, ni, di, Ext) * clampGe
 		SetNrnV(ctx, ni, di, GeExt, geSyn)
 		geExt = 0 // no extra in this case
 	}
@@ -1082,6 +1306,27 @@ func (ac *ActParams) AddGeNoise(ctx *Context, ni, di uint32) {
 	AddNrnV(ctx, ni, di, Ge, NrnV(ctx, ni, di, GeNoise))
 }
 
+// OUNoiseFmRnd updates the GeNoiseOU and GiNoiseOU Ornstein-Uhlenbeck noise
+// conductances if OUNoise is On, for a neuron in given global pool index pi
+// (used for the pool-shared noise), not including the data parallel
+// factor (i.e., PoolSt / MaxData + sub-pool), and adds GeNoiseOU into Ge.
+// GiNoiseOU is added into Gi in GiInteg.
+func (ac *ActParams) OUNoiseFmRnd(ctx *Context, ni, di, pi uint32) {
+	if ac.OUNoise.On.IsFalse() {
+		return
+	}
+	nkey := di*ctx.NetIdxs.NNeurons + ni
+	pkey := di*ctx.NetIdxs.NPools + pi
+	ge := ac.OUNoise.GeStepFmRnd(NrnV(ctx, ni, di, GeNoiseOU), GetRandomNormal(nkey, ctx.RandCtr, RandFunOUGe), GetRandomNormal(pkey, ctx.RandCtr, RandFunOUGePool))
+	gi := ac.OUNoise.GiStepFmRnd(NrnV(ctx, ni, di, GiNoiseOU), GetRandomNormal(nkey, ctx.RandCtr, RandFunOUGi), GetRandomNormal(pkey, ctx.RandCtr, RandFunOUGiPool))
+	SetNrnV(ctx, ni, di, GeNoiseOU, ge)
+	SetNrnV(ctx, ni, di, GiNoiseOU, gi)
+	AddNrnV(ctx, ni, di, Ge, ge)
+	if NrnV(ctx, ni, di, Ge) < 0 {
+		SetNrnV(ctx, ni, di, Ge, 0)
+	}
+}
+
 // AddGiNoise updates nrn.GiNoise if active
 func (ac *ActParams) AddGiNoise(ctx *Context, ni, di uint32) {
 	if ac.Noise.On.IsFalse() || ac.Noise.Gi == 0 {
@@ -7,7 +7,6 @@ package axon
 import (
 	"log"
 
-	"github.com/emer/emergent/erand"
 	"github.com/goki/gosl/slbool"
 	"github.com/goki/ki/ints"
 	"github.com/goki/ki/kit"
@@ -194,20 +193,20 @@ func (sc *SynComParams) WtFailP(swt float32) float32 {
 
 //gosl: end act_prjn
 
-// WtFail returns true if synapse should fail, as function of SWt value (optionally)
-func (sc *SynComParams) WtFail(ctx *Context, swt float32) bool {
+// WtFail returns true if synapse should fail, as function of SWt value (optionally),
+// using the RandFunSynFail random stream for given synapse index.
+func (sc *SynComParams) WtFail(ctx *Context, syni uint32, swt float32) bool {
 	fp := sc.WtFailP(swt)
 	if fp == 0 {
 		return false
 	}
-	return erand.BoolP32(fp, -1)
-	// return slrand.BoolP(ctx.RandCtr, si) // todo: needs a synapse index
+	return GetRandomNumber(syni, ctx.RandCtr, RandFunSynFail) < fp
 }
 
 // Fail updates failure status of given weight, given SWt value
 func (sc *SynComParams) Fail(ctx *Context, syni uint32, swt float32) {
 	if sc.PFail > 0 {
-		if sc.WtFail(ctx, swt) {
+		if sc.WtFail(ctx, syni, swt) {
 			SetSynV(ctx, syni, Wt, 0)
 		}
 	}
@@ -0,0 +1,122 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"os"
+	"testing"
+
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/prjn"
+	"github.com/emer/etable/etensor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClampSchedFactor(t *testing.T) {
+	ctx := NewContext()
+	cs := ClampSchedParams{}
+	cs.Defaults()
+	cs.Onset = 10
+	cs.Offset = 50
+	cs.Ramp = 5
+	facts := map[int32]float32{0: 0, 9: 0, 10: 0.2, 12: 0.6, 14: 1, 45: 1, 46: 0.8, 49: 0.2, 50: 0, 100: 0}
+	for cyc, fact := range facts {
+		ctx.Cycle = cyc
+		assert.InDelta(t, fact, cs.Factor(ctx), 1.0e-6, "cycle: %d", cyc)
+	}
+
+	cs.Offset = 0
+	cs.Ramp = 0
+	ctx.Cycle = 150
+	assert.Equal(t, float32(1), cs.Factor(ctx))
+	cs.Phase = ClampMinusOnly
+	ctx.PlusPhase.SetBool(true)
+	assert.Equal(t, float32(0), cs.Factor(ctx))
+	cs.Phase = ClampPlusOnly
+	assert.Equal(t, float32(1), cs.Factor(ctx))
+	ctx.PlusPhase.SetBool(false)
+	assert.Equal(t, float32(0), cs.Factor(ctx))
+}
+
+// clampSchedGe runs one trial with the Input layer on given schedule,
+// returning Input neuron 0 Ge on each cycle, on the GPU if gpu is true.
+func clampSchedGe(t *testing.T, mode ClampModes, phase ClampPhases, gpu bool) []float32 {
+	net, ctx := newTestNetLayers(t, 1, func(net *Network) {
+		inp := net.AddLayer2D("Input", 2, 2, InputLayer)
+		hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+		net.ConnectLayers(inp, hid, prjn.NewFull(), ForwardPrjn)
+	}, func(net *Network) {
+		cs := &net.AxonLayerByName("Input").Params.Acts.Clamp.Sched
+		cs.On.SetBool(true)
+		cs.Mode = mode
+		cs.Phase = phase
+		cs.Onset = 20
+		cs.Offset = 180
+	})
+	inp := net.AxonLayerByName("Input")
+	if gpu {
+		net.ConfigGPUnoGUI(ctx)
+		defer net.GPU.Destroy()
+		net.GPU.CycleByCycle = true
+	}
+
+	pat := etensor.NewFloat32([]int{2, 2}, nil, nil)
+	pat.SetZeros()
+	pat.Values[0] = 1
+	ctx.NewState(etime.Train)
+	net.NewState(ctx)
+	net.InitExt(ctx)
+	inp.ApplyExt(ctx, 0, pat)
+	net.ApplyExts(ctx)
+	var ges []float32
+	for cyc := 0; cyc < 200; cyc++ {
+		net.Cycle(ctx)
+		ges = append(ges, NrnV(ctx, inp.NeurStIdx, 0, Ge))
+		ctx.CycleInc()
+		if cyc == 149 {
+			net.MinusPhase(ctx)
+			ctx.NewPhase(true)
+			net.PlusPhaseStart(ctx)
+		}
+	}
+	net.PlusPhase(ctx)
+	return ges
+}
+
+func TestClampSched(t *testing.T) {
+	// in ClampGe mode, Ge also includes small NMDA etc conductances
+	for _, mode := range []ClampModes{ClampExt, ClampGe} {
+		ges := clampSchedGe(t, mode, ClampBothPhases, false)
+		assert.Equal(t, float32(0), ges[19], "mode: %s", mode)
+		assert.InDelta(t, 1.5, ges[20], 0.02, "mode: %s", mode)
+		assert.InDelta(t, 1.5, ges[100], 0.02, "mode: %s", mode)
+		assert.InDelta(t, 1.5, ges[179], 0.02, "mode: %s", mode)
+		assert.Less(t, ges[180], float32(0.01), "mode: %s", mode)
+	}
+
+	ges := clampSchedGe(t, ClampExt, ClampPlusOnly, false)
+	assert.Equal(t, float32(0), ges[100])
+	assert.InDelta(t, 1.5, ges[160], 0.02)
+	ges = clampSchedGe(t, ClampExt, ClampMinusOnly, false)
+	assert.InDelta(t, 1.5, ges[100], 0.02)
+	assert.Less(t, ges[160], float32(0.01))
+}
+
+// TestGPUClampSched checks that the GPU applies the clamp schedule
+// the same as the CPU.
+func TestGPUClampSched(t *testing.T) {
+	if os.Getenv("TEST_GPU") != "true" {
+		t.Skip("Set TEST_GPU env var to run GPU tests")
+	}
+	for _, mode := range []ClampModes{ClampExt, ClampGe} {
+		for _, phase := range []ClampPhases{ClampBothPhases, ClampMinusOnly, ClampPlusOnly} {
+			cpu := clampSchedGe(t, mode, phase, false)
+			gpu := clampSchedGe(t, mode, phase, true)
+			for cyc := range cpu {
+				assert.InDelta(t, cpu[cyc], gpu[cyc], 1.0e-4, "mode: %s phase: %s cycle: %d", mode, phase, cyc)
+			}
+		}
+	}
+}
@@ -720,12 +720,14 @@ void AddGlbUSposV(in Context ctx, uint di, GlobalVars gvar, uint posIdx, float v
 
 //gosl: start context
 
-// GlobalsReset resets all global values to 0, for all NData
+// GlobalsReset resets all global values to 0, for all NData,
+// except LearnWt which is reset to 1.
 func GlobalsReset(ctx *Context) {
 	for di := uint32(0); di < ctx.NetIdxs.MaxData; di++ {
 		for vg := GvRew; vg < GvUSneg; vg++ {
 			SetGlbV(ctx, di, vg, 0)
 		}
+		SetGlbV(ctx, di, GvLearnWt, 1)
 		for vn := GvUSneg; vn <= GvUSnegRaw; vn++ {
 			for ui := uint32(0); ui < ctx.NetIdxs.PVLVNNegUSs; ui++ {
 				SetGlbUSneg(ctx, di, vn, ui, 0)
@@ -750,6 +752,13 @@ func GlobalSetRew(ctx *Context, di uint32, rew float32, hasRew bool) {
 	}
 }
 
+// GlobalSetLearnWt is a convenience function for setting the learning
+// weight for given data index: 0 = no learning, otherwise multiplies
+// the weight changes from this data index.
+func GlobalSetLearnWt(ctx *Context, di uint32, wt float32) {
+	SetGlbV(ctx, di, GvLearnWt, wt)
+}
+
 // PVLVUSStimVal returns stimulus value for US at given index
 // and valence.  If US > 0.01, a full 1 US activation is returned.
 func PVLVUSStimVal(ctx *Context, di uint32, usIdx uint32, valence ValenceTypes) float32 {
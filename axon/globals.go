@@ -47,10 +47,10 @@ const (
 	// ACh is acetylcholine -- activated by salient events, particularly at the onset of a reward / punishment outcome (US), or onset of a conditioned stimulus (CS).  Driven by BLA -> PPtg that detects changes in BLA activity, via LDTLayer type
 	GvACh
 
-	// NE is norepinepherine -- not yet in use
+	// NE is norepinepherine -- arousal signal reflecting urgency, surprise (absolute value of DA) and salience, released by the LC -- locus coeruleus, via LCLayer type.  Modulates gain, learning rate and inhibition via NeuroModParams.
 	GvNE
 
-	// Ser is serotonin -- not yet in use
+	// Ser is serotonin -- patience signal reflecting expected future reward (PVposEst) and aversive outcomes (PVneg), released by the DRN -- dorsal raphe nucleus, via DRNLayer type.  Modulates gain, learning rate and inhibition via NeuroModParams.
 	GvSer
 
 	// AChRaw is raw ACh value used in updating global ACh value by LDTLayer
@@ -194,6 +194,21 @@ const (
 	// VtaDA is overall dopamine value reflecting all of the different inputs
 	GvVtaDA
 
+	/////////////////////////////////////////
+	// LC and DRN raw neuromodulator values
+
+	// NERaw is raw NE value used in updating global NE value by LCLayer
+	GvNERaw
+
+	// SerRaw is raw Ser value used in updating global Ser value by DRNLayer
+	GvSerRaw
+
+	/////////////////////////////////////////
+	// Learning
+
+	// LearnWt is the learning weight for this data parallel index, multiplying its contribution to synaptic weight changes (DiDWt -> DWt) and target activity changes (DTrgAvg): 0 = no learning (e.g., for probe items mixed into a training batch), otherwise a scalar weight (e.g., for importance-weighted samples).  Defaults to 1, set in GlobalsReset -- see GlobalSetLearnWt.
+	GvLearnWt
+
 	/////////////////////////////////////////
 	// USneg is negative valence US
 	//   allocated for Nitems
@@ -55,20 +55,23 @@ func _() {
 	_ = x[GvCeMpos-39]
 	_ = x[GvCeMneg-40]
 	_ = x[GvVtaDA-41]
-	_ = x[GvUSneg-42]
-	_ = x[GvUSnegRaw-43]
-	_ = x[GvDrives-44]
-	_ = x[GvUSpos-45]
-	_ = x[GvVSPatch-46]
-	_ = x[GvVSPatchPrev-47]
-	_ = x[GvOFCposUSPTMaint-48]
-	_ = x[GvVSMatrixPoolGated-49]
-	_ = x[GlobalVarsN-50]
+	_ = x[GvNERaw-42]
+	_ = x[GvSerRaw-43]
+	_ = x[GvLearnWt-44]
+	_ = x[GvUSneg-45]
+	_ = x[GvUSnegRaw-46]
+	_ = x[GvDrives-47]
+	_ = x[GvUSpos-48]
+	_ = x[GvVSPatch-49]
+	_ = x[GvVSPatchPrev-50]
+	_ = x[GvOFCposUSPTMaint-51]
+	_ = x[GvVSMatrixPoolGated-52]
+	_ = x[GlobalVarsN-53]
 }
 
-const _GlobalVars_name = "GvRewGvHasRewGvRewPredGvPrevPredGvHadRewGvDAGvAChGvNEGvSerGvAChRawGvNotMaintGvVSMatrixJustGatedGvVSMatrixHasGatedGvCuriosityPoolGatedGvTimeGvEffortGvUrgencyRawGvUrgencyGvHasPosUSGvHadPosUSGvNegUSOutcomeGvHadNegUSOutcomeGvPVposSumGvPVposGvPVnegSumGvPVnegGvPVposEstGvPVposEstSumGvPVposEstDiscGvGiveUpDiffGvGiveUpProbGvGiveUpGvGaveUpGvVSPatchPosGvVSPatchPosPrevGvVSPatchPosSumGvLHbDipGvLHbBurstGvLHbPVDAGvCeMposGvCeMnegGvVtaDAGvUSnegGvUSnegRawGvDrivesGvUSposGvVSPatchGvVSPatchPrevGvOFCposUSPTMaintGvVSMatrixPoolGatedGlobalVarsN"
+const _GlobalVars_name = "GvRewGvHasRewGvRewPredGvPrevPredGvHadRewGvDAGvAChGvNEGvSerGvAChRawGvNotMaintGvVSMatrixJustGatedGvVSMatrixHasGatedGvCuriosityPoolGatedGvTimeGvEffortGvUrgencyRawGvUrgencyGvHasPosUSGvHadPosUSGvNegUSOutcomeGvHadNegUSOutcomeGvPVposSumGvPVposGvPVnegSumGvPVnegGvPVposEstGvPVposEstSumGvPVposEstDiscGvGiveUpDiffGvGiveUpProbGvGiveUpGvGaveUpGvVSPatchPosGvVSPatchPosPrevGvVSPatchPosSumGvLHbDipGvLHbBurstGvLHbPVDAGvCeMposGvCeMnegGvVtaDAGvNERawGvSerRawGvLearnWtGvUSnegGvUSnegRawGvDrivesGvUSposGvVSPatchGvVSPatchPrevGvOFCposUSPTMaintGvVSMatrixPoolGatedGlobalVarsN"
 
-var _GlobalVars_index = [...]uint16{0, 5, 13, 22, 32, 40, 44, 49, 53, 58, 66, 76, 95, 113, 133, 139, 147, 159, 168, 178, 188, 202, 219, 229, 236, 246, 253, 263, 276, 290, 302, 314, 322, 330, 342, 358, 373, 381, 391, 400, 408, 416, 423, 430, 440, 448, 455, 464, 477, 494, 513, 524}
+var _GlobalVars_index = [...]uint16{0, 5, 13, 22, 32, 40, 44, 49, 53, 58, 66, 76, 95, 113, 133, 139, 147, 159, 168, 178, 188, 202, 219, 229, 236, 246, 253, 263, 276, 290, 302, 314, 322, 330, 342, 358, 373, 381, 391, 400, 408, 416, 423, 430, 438, 447, 454, 464, 472, 479, 488, 501, 518, 537, 548}
 
 func (i GlobalVars) String() string {
 	if i < 0 || i >= GlobalVars(len(_GlobalVars_index)-1) {
@@ -95,8 +98,8 @@ var _GlobalVars_descMap = map[GlobalVars]string{
 	4:  `HadRew is HasRew state from the previous trial -- copied from HasRew in NewState -- used for updating Effort, Urgency at start of new trial`,
 	5:  `DA is dopamine -- represents reward prediction error, signaled as phasic increases or decreases in activity relative to a tonic baseline, which is represented by a value of 0. Released by the VTA -- ventral tegmental area, or SNc -- substantia nigra pars compacta.`,
 	6:  `ACh is acetylcholine -- activated by salient events, particularly at the onset of a reward / punishment outcome (US), or onset of a conditioned stimulus (CS). Driven by BLA -&gt; PPtg that detects changes in BLA activity, via LDTLayer type`,
-	7:  `NE is norepinepherine -- not yet in use`,
-	8:  `Ser is serotonin -- not yet in use`,
+	7:  `NE is norepinepherine -- arousal signal reflecting urgency, surprise (absolute value of DA) and salience, released by the LC -- locus coeruleus, via LCLayer type. Modulates gain, learning rate and inhibition via NeuroModParams.`,
+	8:  `Ser is serotonin -- patience signal reflecting expected future reward (PVposEst) and aversive outcomes (PVneg), released by the DRN -- dorsal raphe nucleus, via DRNLayer type. Modulates gain, learning rate and inhibition via NeuroModParams.`,
 	9:  `AChRaw is raw ACh value used in updating global ACh value by LDTLayer`,
 	10: `NotMaint is activity of the PTNotMaintLayer -- drives top-down inhibition of LDT layer / ACh activity.`,
 	11: `VSMatrixJustGated is VSMatrix just gated (to engage goal maintenance in PFC areas), set at end of plus phase -- this excludes any gating happening at time of US`,
@@ -130,15 +133,18 @@ var _GlobalVars_descMap = map[GlobalVars]string{
 	39: `CeMpos is positive valence central nucleus of the amygdala (CeM) LV (learned value) activity, reflecting |BLAPosAcqD1 - BLAPosExtD2|_+ positively rectified. CeM sets Raw directly. Note that a positive US onset even with no active Drive will be reflected here, enabling learning about unexpected outcomes`,
 	40: `CeMneg is negative valence central nucleus of the amygdala (CeM) LV (learned value) activity, reflecting |BLANegAcqD2 - BLANegExtD1|_+ positively rectified. CeM sets Raw directly`,
 	41: `VtaDA is overall dopamine value reflecting all of the different inputs`,
-	42: `USneg are negative valence US outcomes -- normalized version of raw, NNegUSs of them`,
-	43: `USnegRaw are raw, linearly incremented negative valence US outcomes, this value is also integrated together with all US vals for PVneg`,
-	44: `Drives is current drive state -- updated with optional homeostatic exponential return to baseline values`,
-	45: `USpos is current positive-valence drive-satisfying input(s) (unconditioned stimuli = US)`,
-	46: `VSPatch is current reward predicting VSPatch (PosD1) values`,
-	47: `VSPatch is previous reward predicting VSPatch (PosD1) values`,
-	48: `OFCposUSPTMaint is activity level of given OFCposUSPT maintenance pool used in anticipating potential USpos outcome value`,
-	49: `VSMatrixPoolGated indicates whether given VSMatrix pool gated this is reset after last goal accomplished -- records gating since then.`,
-	50: ``,
+	42: `NERaw is raw NE value used in updating global NE value by LCLayer`,
+	43: `SerRaw is raw Ser value used in updating global Ser value by DRNLayer`,
+	44: `LearnWt is the learning weight for this data parallel index, multiplying its contribution to synaptic weight changes (DiDWt -&gt; DWt) and target activity changes (DTrgAvg): 0 = no learning (e.g., for probe items mixed into a training batch), otherwise a scalar weight (e.g., for importance-weighted samples). Defaults to 1, set in GlobalsReset -- see GlobalSetLearnWt.`,
+	45: `USneg are negative valence US outcomes -- normalized version of raw, NNegUSs of them`,
+	46: `USnegRaw are raw, linearly incremented negative valence US outcomes, this value is also integrated together with all US vals for PVneg`,
+	47: `Drives is current drive state -- updated with optional homeostatic exponential return to baseline values`,
+	48: `USpos is current positive-valence drive-satisfying input(s) (unconditioned stimuli = US)`,
+	49: `VSPatch is current reward predicting VSPatch (PosD1) values`,
+	50: `VSPatch is previous reward predicting VSPatch (PosD1) values`,
+	51: `OFCposUSPTMaint is activity level of given OFCposUSPT maintenance pool used in anticipating potential USpos outcome value`,
+	52: `VSMatrixPoolGated indicates whether given VSMatrix pool gated this is reset after last goal accomplished -- records gating since then.`,
+	53: ``,
 }
 
 func (i GlobalVars) Desc() string {
@@ -58,6 +58,10 @@ void BetweenGi2(in Context ctx, in LayerParams ly, uint di, inout Pool lpl) {
 
 void BetweenGi(in Context ctx, in LayerParams ly, uint li, uint di) {
 	BetweenGi2(ctx, ly, di, Pools[ly.Idxs.PoolIdx(0, di)]);
+	if (ly.AttnSrc.LayIdx >= 0) {
+		LayerParams sly = Layers[ly.AttnSrc.LayIdx];
+		ly.AttnFmSrc(ctx, sly, di);
+	}
 }
 
 [numthreads(64, 1, 1)]
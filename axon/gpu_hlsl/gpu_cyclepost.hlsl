@@ -78,6 +78,14 @@ void CyclePost2(inout Context ctx, in LayerParams ly, uint li, uint di, inout La
 		ly.CyclePostVTALayer(ctx, di);
 		break;
 	}
+	case LCLayer: {
+		ly.CyclePostLCLayer(ctx, di, LDTSrcLayAct(ly.LC.SrcLayIdx, di));
+		break;
+	}
+	case DRNLayer: {
+		ly.CyclePostDRNLayer(ctx, di, LDTSrcLayAct(ly.DRN.SrcLayIdx, di));
+		break;
+	}
 	case RWDaLayer: {
 		ly.CyclePostRWDaLayer(ctx, di, vals, LayVals[ctx.NetIdxs.ValsIdx(ly.RWDa.RWPredLayIdx, di)]);
 		break;
@@ -126,6 +134,8 @@ void main(uint3 idx : SV_DispatchThreadID) {
 	int ldti = -1;
 	int vspi = -1;
 	int vtai = -1;
+	int lci = -1;
+	int drni = -1;
 	int rwdi = -1;
 	int tdpi = -1;
 	int tdii = -1;
@@ -152,6 +162,12 @@ void main(uint3 idx : SV_DispatchThreadID) {
 		case VTALayer:
 			vtai = li;
 			break;
+		case LCLayer:
+			lci = li;
+			break;
+		case DRNLayer:
+			drni = li;
+			break;
 		case RWDaLayer:
 			rwdi = li;
 			break;
@@ -197,5 +213,12 @@ void main(uint3 idx : SV_DispatchThreadID) {
 	if (vtai >= 0) {                       
 		CyclePost(Ctx[0], Layers[vtai], vtai, di);
 	}
+	// note: LC depends on DA from vtai
+	if (lci >= 0) {
+		CyclePost(Ctx[0], Layers[lci], lci, di);
+	}
+	if (drni >= 0) {
+		CyclePost(Ctx[0], Layers[drni], drni, di);
+	}
 }
 
@@ -79,6 +79,7 @@ void GatherSpikes2(in Context ctx, LayerParams ly, uint ni, uint di) {
 	uint lni = ni - ly.Idxs.NeurSt; // layer-based
 
 	ly.GatherSpikesInit(ctx, ni, di);
+	ly.TopoGiNeuron(ctx, ni, di); // GiTopo, if Inhib.Topo.On
 	
 	for (uint pi = 0; pi < ly.Idxs.RecvN; pi++) {
 		GatherSpikesPrjn(ctx, Prjns[RecvPrjnIdxs[ly.Idxs.RecvSt + pi]], ly, ni, di, lni);
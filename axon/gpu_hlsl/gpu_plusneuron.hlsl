@@ -23,13 +23,16 @@
 
 #include "context.hlsl"
 #include "layerparams.hlsl"
+#include "prjnparams.hlsl"
 
 // note: binding is var, set
 
 // Set 0: uniform layer params -- could not have prjns also be uniform..
 [[vk::binding(0, 0)]] StructuredBuffer<LayerParams> Layers; // [Layer]
+[[vk::binding(1, 0)]] StructuredBuffer<PrjnParams> Prjns; // [Layer][SendPrjns]
 
 // Set 1: effectively uniform indexes and prjn params as structured buffers in storage
+[[vk::binding(2, 1)]] StructuredBuffer<StartN> SendCon; // [Layer][SendPrjns][SendNeurons]
 
 // Set 2: main network structs and vals -- all are writable
 [[vk::binding(0, 2)]] StructuredBuffer<Context> Ctx; // [0]
@@ -37,8 +40,29 @@
 [[vk::binding(4, 2)]] RWStructuredBuffer<LayerVals> LayVals; // [Layer][Data]
 
 
+void TDPredTracePrjn(in Context ctx, in PrjnParams pj, uint ni, uint lni, uint di) {
+	if (!pj.DoTDPredTrace()) {
+		return;
+	}
+	float sact = NrnV(ctx, ni, di, SpkPrv);
+	uint cni = pj.Idxs.SendConSt + lni;
+	uint synst = pj.Idxs.SynapseSt + SendCon[cni].Start;
+	uint synn = SendCon[cni].N;
+	for (uint ci = 0; ci < synn; ci++) {
+		pj.TDPredTraceSyn(ctx, synst + ci, di, sact);
+	}
+}
+
 void PlusPhaseNeuron2(in Context ctx, in LayerParams ly, uint ni, uint di, in Pool pl) {
 	ly.PlusPhaseNeuron(ctx, ni, di, pl, Pools[ly.Idxs.PoolIdx(0, di)], LayVals[ly.Idxs.ValsIdx(di)]);
+	if (ly.LayType == SRPredLayer) {
+		LayerParams sly = Layers[ly.SRPred.StateLayIdx];
+		ly.PlusPhaseSRPredNeuron(ctx, sly, ni, di);
+	}
+	uint lni = ni - ly.Idxs.NeurSt;
+	for (uint pi = 0; pi < ly.Idxs.SendN; pi++) {
+		TDPredTracePrjn(ctx, Prjns[ly.Idxs.SendSt + pi], ni, lni, di);
+	}
 }
 
 void PlusPhaseNeuron(in Context ctx, uint ni, uint di) {
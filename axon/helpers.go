@@ -6,6 +6,7 @@ package axon
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/emer/emergent/ecmd"
 	"github.com/emer/empi/mpi"
@@ -73,3 +74,49 @@ func SaveWeightsIfConfigSet(net *Network, cfgWts bool, ctrString, runName string
 	}
 	return ""
 }
+
+// RFImageFileName returns default receptive field image file name for
+// given projection, named by its receiving and sending layers,
+// using the same information as WeightsFileName.
+func RFImageFileName(net *Network, prjn, ctrString, runName string) string {
+	return net.Name() + "_" + runName + "_" + ctrString + "_" + strings.Replace(prjn, ":", "_", -1) + ".png"
+}
+
+// SaveRFImages saves PNG images of the receptive field weights (Wt)
+// of all receiving units for each of given projections, specified as
+// "Recv:Send" layer names, with file names from RFImageFileName.
+// Weights are normalized within each tile, and scaled up by 4 pixels
+// per synapse.  See Prjn.SaveRFImage for more control.
+// only for 0 rank MPI if running mpi
+// Returns the names of the files saved.
+func SaveRFImages(net *Network, prjns []string, ctrString, runName string) []string {
+	if mpi.WorldRank() > 0 {
+		return nil
+	}
+	var fnms []string
+	for _, pnm := range prjns {
+		lnms := strings.Split(pnm, ":")
+		if len(lnms) != 2 {
+			fmt.Printf("SaveRFImages: projection must be specified as Recv:Send: %s\n", pnm)
+			continue
+		}
+		ly := net.AxonLayerByName(lnms[0])
+		if ly == nil {
+			fmt.Printf("SaveRFImages: layer not found: %s\n", lnms[0])
+			continue
+		}
+		pj, err := ly.SendNameTry(lnms[1])
+		if err != nil {
+			fmt.Printf("SaveRFImages: %s\n", err)
+			continue
+		}
+		fnm := RFImageFileName(net, pnm, ctrString, runName)
+		fmt.Printf("Saving RF Image to: %s\n", fnm)
+		if err := pj.(AxonPrjn).AsAxon().SaveRFImage(fnm, "Wt", true, 4); err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fnms = append(fnms, fnm)
+	}
+	return fnms
+}
@@ -78,6 +78,9 @@ type HipConfig struct {
 
 	// [def: 0.1] threshold for binarizing EC5 clamp values -- any value above this is clamped to 1, else 0 -- helps produce a cleaner learning signal.  Set to 0 to not perform any binarization.
 	EC5ClampThr float32 `def:"0.1" desc:"threshold for binarizing EC5 clamp values -- any value above this is clamped to 1, else 0 -- helps produce a cleaner learning signal.  Set to 0 to not perform any binarization."`
+
+	// [view: inline] parameters for the offline Replay mode, used if the looper has a Replay.Mode stack
+	Replay HipReplayConfig `view:"inline" desc:"parameters for the offline Replay mode, used if the looper has a Replay.Mode stack"`
 }
 
 func (hip *HipConfig) Defaults() {
@@ -109,6 +112,8 @@ func (hip *HipConfig) Defaults() {
 	hip.EC5ClampSrc = "EC3"
 	hip.EC5ClampTest = true
 	hip.EC5ClampThr = 0.1
+
+	hip.Replay.Defaults()
 }
 
 // AddHip adds a new Hippocampal network for episodic memory.
@@ -188,6 +193,8 @@ func (net *Network) AddHip(ctx *Context, hip *HipConfig, space float32) (ec2, ec
 // see hip.go for an instance of implementation of this function.
 // ec5ClampFrom specifies the layer to clamp EC5 plus phase values from:
 // EC3 is the biological source, but can use Input layer for simple testing net.
+// If the looper has a hip.Replay.Mode stack (see AddHipReplayStack), then it is
+// configured for offline replay, using hip.Replay parameters.
 func (net *Network) ConfigLoopsHip(ctx *Context, man *looper.Manager, hip *HipConfig, pretrain *bool) {
 	var tmpVals []float32
 
@@ -206,6 +213,9 @@ func (net *Network) ConfigLoopsHip(ctx *Context, man *looper.Manager, hip *HipCo
 	dgPjScale := ca3FmDg.Params.PrjnScale.Rel
 	ca1FmCa3Abs := ca1FmCa3.Params.PrjnScale.Abs
 
+	replay := &hipReplay{}
+	replay.Init(net, hip)
+
 	// configure events -- note that events are shared between Train, Test
 	// so only need to do it once on Train
 	mode := etime.Train
@@ -231,11 +241,18 @@ func (net *Network) ConfigLoopsHip(ctx *Context, man *looper.Manager, hip *HipCo
 
 		ca3FmDg.Params.PrjnScale.Rel = dgPjScale * (1 - hip.MossyDelta) // turn off DG input to CA3 in first quarter
 
+		if man.Mode == hip.Replay.Mode {
+			replay.MinusPhase(ctx, ca1FmEc3, ca1FmCa3, ca3FmDg)
+		}
+
 		net.InitGScale(ctx) // update computed scaling factors
 		net.GPU.SyncParamsToGPU()
 	})
 	beta1, _ := cyc.EventByName("Beta1")
 	beta1.OnEvent.Add("Hip:Beta1", func() {
+		if man.Mode == hip.Replay.Mode {
+			return
+		}
 		ca1FmEc3.Params.PrjnScale.Rel = hip.ThetaLow
 		ca1FmCa3.Params.PrjnScale.Rel = hip.ThetaHigh
 		if man.Mode == etime.Test {
@@ -248,6 +265,13 @@ func (net *Network) ConfigLoopsHip(ctx *Context, man *looper.Manager, hip *HipCo
 
 	// note: critical for this to come before std start
 	plus.OnEvent.InsertBefore("PlusPhase:Start", "HipPlusPhase:Start", func() {
+		if man.Mode == hip.Replay.Mode {
+			replay.PlusPhase(ctx)
+			net.InitGScale(ctx) // update computed scaling factors
+			net.GPU.SyncParamsToGPU()
+			net.ApplyExts(ctx) // essential for GPU
+			return
+		}
 		ca3FmDg.Params.PrjnScale.Rel = dgPjScale // restore at the beginning of plus phase for CA3 EDL
 		ca1FmEc3.Params.PrjnScale.Rel = hip.ThetaHigh
 		ca1FmCa3.Params.PrjnScale.Rel = hip.ThetaLow
@@ -274,4 +298,23 @@ func (net *Network) ConfigLoopsHip(ctx *Context, man *looper.Manager, hip *HipCo
 		net.InitGScale(ctx) // update computed scaling factors
 		net.GPU.SyncParamsToGPU()
 	})
+
+	rstack, hasReplay := man.Stacks[hip.Replay.Mode]
+	if !hasReplay {
+		return
+	}
+	repc := rstack.Loops[etime.Epoch]
+	repc.OnStart.Add("HipReplay:Start", func() {
+		replay.Start(ctx)
+	})
+	repc.OnEnd.Add("HipReplay:End", func() {
+		replay.End(ctx)
+	})
+	rtrl := rstack.Loops[etime.Trial]
+	rtrl.OnStart.Add("HipReplay:NewTrial", func() {
+		replay.NewTrial(ctx)
+	})
+	rtrl.OnEnd.Add("HipReplay:UpdateWeights", func() {
+		replay.UpdateWeights(ctx)
+	})
 }
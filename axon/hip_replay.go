@@ -0,0 +1,191 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/looper"
+	"github.com/emer/etable/norm"
+	"github.com/goki/gosl/slbool"
+)
+
+// HipReplayConfig has parameters for the offline (sleep) Replay mode of
+// the hippocampus, in which CA3 is spontaneously reactivated by noise,
+// with no external input, and completes previously-learned patterns
+// through its recurrent collaterals.  The replayed CA1 -> EC5 activity
+// then drives learning in projections from EC5 to cortical layers,
+// for studying systems consolidation.
+type HipReplayConfig struct {
+
+	// [def: Analyze] evaluation mode used for the offline Replay looper stack added by AddHipReplayStack -- etime does not have a dedicated replay mode, so Analyze is used by default -- change this prior to configuring the looper if the sim uses Analyze for other purposes
+	Mode etime.Modes `def:"Analyze" desc:"evaluation mode used for the offline Replay looper stack added by AddHipReplayStack -- etime does not have a dedicated replay mode, so Analyze is used by default -- change this prior to configuring the looper if the sim uses Analyze for other purposes"`
+
+	// [def: 10] [min: 1] number of replay events (trials) per replay epoch
+	NEvents int `def:"10" min:"1" desc:"number of replay events (trials) per replay epoch"`
+
+	// [def: 100] mean frequency of the excitatory noise spikes driving spontaneous CA3 reactivation -- see Acts.Noise.GeHz
+	CA3NoiseGeHz float32 `def:"100" desc:"mean frequency of the excitatory noise spikes driving spontaneous CA3 reactivation -- see Acts.Noise.GeHz"`
+
+	// [def: 0.2] [min: 0] excitatory conductance per noise spike driving spontaneous CA3 reactivation -- see Acts.Noise.Ge
+	CA3NoiseGe float32 `def:"0.2" min:"0" desc:"excitatory conductance per noise spike driving spontaneous CA3 reactivation -- see Acts.Noise.Ge"`
+
+	// [def: 0] proportion of normal EC2 -> CA3 perforant path input during replay -- 0 makes reactivation purely noise driven
+	EC2ToCA3 float32 `def:"0" desc:"proportion of normal EC2 -> CA3 perforant path input during replay -- 0 makes reactivation purely noise driven"`
+}
+
+func (rp *HipReplayConfig) Defaults() {
+	rp.Mode = etime.Analyze
+	rp.NEvents = 10
+	rp.CA3NoiseGeHz = 100
+	rp.CA3NoiseGe = 0.2
+	rp.EC2ToCA3 = 0
+}
+
+// AddHipReplayStack adds an offline Replay looper stack in hip.Replay.Mode,
+// with one Epoch of hip.Replay.NEvents trials incremented by nData,
+// and given number of cycles per trial (200 typical).
+// Must be called before LooperStdPhases and ConfigLoopsHip,
+// so that the standard phase events and replay functions are
+// configured for this stack.  Sims should not apply inputs
+// from an environment in this mode.  Call ResetAndRun(hip.Replay.Mode)
+// to run a replay epoch, e.g., after each training epoch.
+func AddHipReplayStack(man *looper.Manager, hip *HipConfig, nData, nCycles int) *looper.Stack {
+	return man.AddStack(hip.Replay.Mode).AddTime(etime.Epoch, 1).AddTimeIncr(etime.Trial, hip.Replay.NEvents, nData).AddTime(etime.Cycle, nCycles)
+}
+
+// hipReplay manages the state for the offline Replay mode,
+// configured by ConfigLoopsHip.
+type hipReplay struct {
+	net *Network
+	hip *HipConfig
+	ca3 *Layer
+	ec5 *Layer
+
+	// projections within the hippocampus, which do not learn during replay
+	hipPrjns []*Prjn
+	hipLearn []slbool.Bool
+
+	// projections from EC5 to cortex, which learn from the replayed activity
+	ctxPrjns []*Prjn
+	ctxRel   []float32
+
+	ca3FmEc2    *Prjn
+	ca3FmEc2Rel float32
+	ca3Noise    SpikeNoiseParams
+
+	tmpVals []float32
+}
+
+func (hr *hipReplay) Init(net *Network, hip *HipConfig) {
+	hr.net = net
+	hr.hip = hip
+	hr.ca3 = net.AxonLayerByName("CA3")
+	hr.ec5 = net.AxonLayerByName("EC5")
+	hr.ca3FmEc2 = hr.ca3.SendName("EC2")
+	hipLays := map[string]bool{}
+	for _, lnm := range []string{"EC2", "EC3", "DG", "CA3", "CA1", "EC5"} {
+		hipLays[lnm] = true
+		ly := net.AxonLayerByName(lnm)
+		for _, pj := range ly.RcvPrjns {
+			if pj.IsOff() {
+				continue
+			}
+			hr.hipPrjns = append(hr.hipPrjns, pj)
+		}
+	}
+	for _, pj := range hr.ec5.SndPrjns {
+		if pj.IsOff() || hipLays[pj.Recv.Name()] {
+			continue
+		}
+		hr.ctxPrjns = append(hr.ctxPrjns, pj)
+	}
+	hr.hipLearn = make([]slbool.Bool, len(hr.hipPrjns))
+	hr.ctxRel = make([]float32, len(hr.ctxPrjns))
+}
+
+// Start saves the online parameters and configures the
+// network for replay, at the start of the replay epoch.
+func (hr *hipReplay) Start(ctx *Context) {
+	for i, pj := range hr.hipPrjns {
+		hr.hipLearn[i] = pj.Params.Learn.Learn
+	}
+	for i, pj := range hr.ctxPrjns {
+		hr.ctxRel[i] = pj.Params.PrjnScale.Rel
+	}
+	hr.ca3FmEc2Rel = hr.ca3FmEc2.Params.PrjnScale.Rel
+	hr.ca3Noise = hr.ca3.Params.Acts.Noise
+
+	rp := &hr.hip.Replay
+	nz := &hr.ca3.Params.Acts.Noise
+	nz.On.SetBool(true)
+	nz.GeHz = rp.CA3NoiseGeHz
+	nz.Ge = rp.CA3NoiseGe
+	nz.Update()
+	hr.net.GPU.SyncParamsToGPU()
+}
+
+// NewTrial clears any external inputs at the start of each replay trial,
+// and turns off ctx.Testing (set for all non-Train modes by NewState)
+// so that synaptic Ca is integrated for learning.
+func (hr *hipReplay) NewTrial(ctx *Context) {
+	ctx.Testing.SetBool(false)
+	hr.net.InitExt(ctx)
+	hr.net.ApplyExts(ctx)
+}
+
+// MinusPhase sets replay learning and projection scaling for the minus phase,
+// called after the standard hippocampal MinusPhase settings.
+// CA1 is driven by CA3 as in the retrieval phase of theta, and EC5 -> cortex
+// projections are at ThetaLow strength.
+func (hr *hipReplay) MinusPhase(ctx *Context, ca1FmEc3, ca1FmCa3, ca3FmDg *Prjn) {
+	hip := hr.hip
+	for _, pj := range hr.hipPrjns {
+		pj.Params.Learn.Learn.SetBool(false)
+	}
+	ca3FmDg.Params.PrjnScale.Rel = 0
+	hr.ca3FmEc2.Params.PrjnScale.Rel = hr.ca3FmEc2Rel * hip.Replay.EC2ToCA3
+	ca1FmEc3.Params.PrjnScale.Rel = hip.ThetaLow
+	ca1FmCa3.Params.PrjnScale.Rel = hip.ThetaHigh
+	for i, pj := range hr.ctxPrjns {
+		pj.Params.PrjnScale.Rel = hr.ctxRel[i] * hip.ThetaLow
+	}
+}
+
+// PlusPhase clamps EC5 to its replayed minus phase activity
+// and sets EC5 -> cortex projections to ThetaHigh strength,
+// so that cortex learns the replayed pattern.
+func (hr *hipReplay) PlusPhase(ctx *Context) {
+	hip := hr.hip
+	for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
+		hr.ec5.UnitVals(&hr.tmpVals, "ActM", int(di))
+		if hip.EC5ClampThr > 0 {
+			norm.Binarize32(hr.tmpVals, hip.EC5ClampThr, 1, 0)
+		}
+		hr.ec5.ApplyExt1D32(ctx, di, hr.tmpVals)
+	}
+	for i, pj := range hr.ctxPrjns {
+		pj.Params.PrjnScale.Rel = hr.ctxRel[i] * hip.ThetaHigh
+	}
+}
+
+// UpdateWeights applies learning at the end of each replay trial.
+func (hr *hipReplay) UpdateWeights(ctx *Context) {
+	hr.net.DWt(ctx)
+	hr.net.WtFmDWt(ctx)
+}
+
+// End restores the online parameters at the end of the replay epoch.
+func (hr *hipReplay) End(ctx *Context) {
+	for i, pj := range hr.hipPrjns {
+		pj.Params.Learn.Learn = hr.hipLearn[i]
+	}
+	for i, pj := range hr.ctxPrjns {
+		pj.Params.PrjnScale.Rel = hr.ctxRel[i]
+	}
+	hr.ca3FmEc2.Params.PrjnScale.Rel = hr.ca3FmEc2Rel
+	hr.ca3.Params.Acts.Noise = hr.ca3Noise
+	hr.net.InitGScale(ctx)
+	hr.net.GPU.SyncParamsToGPU()
+}
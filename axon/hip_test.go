@@ -0,0 +1,81 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/looper"
+	"github.com/emer/emergent/netview"
+	"github.com/emer/emergent/prjn"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHipReplay runs an offline replay epoch in a small hippocampus
+// with a cortical layer receiving from EC5, and checks that
+// CA3 is spontaneously reactivated, only EC5 -> cortex learns,
+// and online parameters are restored afterward.
+func TestHipReplay(t *testing.T) {
+	ctx := NewContext()
+	net := NewNetwork("HipReplay")
+	hip := &HipConfig{}
+	hip.Defaults()
+	hip.EC2Size.Set(10, 10)
+	hip.EC3NPool.Set(2, 2)
+	hip.EC3NNrn.Set(4, 4)
+	hip.CA1NNrn.Set(5, 5)
+	hip.CA3Size.Set(10, 10)
+	hip.Replay.NEvents = 2
+
+	in := net.AddLayer4D("Input", 2, 2, 4, 4, InputLayer)
+	ec2, ec3, _, ca3, _, ec5 := net.AddHip(ctx, hip, 2)
+	net.ConnectLayers(in, ec2, prjn.NewFull(), ForwardPrjn)
+	net.ConnectLayers(in, ec3, prjn.NewOneToOne(), ForwardPrjn)
+	ctxly := net.AddLayer2D("Cortex", 6, 6, SuperLayer)
+	ctxPj := net.ConnectLayers(ec5, ctxly, prjn.NewFull(), ForwardPrjn)
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	net.InitWts(ctx)
+
+	man := looper.NewManager()
+	man.AddStack(etime.Train).AddTime(etime.Epoch, 1).AddTime(etime.Trial, 1).AddTime(etime.Cycle, 200)
+	AddHipReplayStack(man, hip, 1, 200)
+	LooperStdPhases(man, ctx, net, 150, 199)
+	LooperSimCycleAndLearn(man, net, ctx, &netview.ViewUpdt{})
+	pretrain := false
+	net.ConfigLoopsHip(ctx, man, hip, &pretrain)
+
+	ca3Act := float32(0)
+	man.GetLoop(hip.Replay.Mode, etime.Trial).OnEnd.Prepend("CA3Act", func() {
+		ca3Act += ca3.Pool(0, 0).AvgMax.Act.Minus.Avg
+	})
+
+	ca3Ca3 := ca3.SendName("CA3")
+	hipWt := ca3Ca3.SynVal("Wt", 0, 1)
+	var ctxWt, ctxWtPost []float32
+	ctxPj.SynVals(&ctxWt, "Wt")
+	ca3FmEc2Rel := ca3.SendName("EC2").Params.PrjnScale.Rel
+	ctxRel := ctxPj.Params.PrjnScale.Rel
+
+	man.ResetAndRun(hip.Replay.Mode)
+
+	assert.Greater(t, ca3Act, float32(0))
+	assert.Equal(t, hipWt, ca3Ca3.SynVal("Wt", 0, 1))
+	ctxPj.SynVals(&ctxWtPost, "Wt")
+	changed := false
+	for i, wt := range ctxWtPost {
+		if wt != ctxWt[i] {
+			changed = true
+			break
+		}
+	}
+	assert.True(t, changed)
+
+	assert.False(t, ca3.Params.Acts.Noise.On.IsTrue())
+	assert.Equal(t, ca3FmEc2Rel, ca3.SendName("EC2").Params.PrjnScale.Rel)
+	assert.Equal(t, ctxRel, ctxPj.Params.PrjnScale.Rel)
+	assert.True(t, ca3Ca3.Params.Learn.Learn.IsTrue())
+}
@@ -81,7 +81,10 @@ func (aa *ActAvgParams) Adapt(gimult *float32, act float32) bool {
 //  TopoInhibParams
 
 // TopoInhibParams provides for topographic gaussian inhibition integrating over neighborhood.
-// TODO: not currently being used
+// For 2D layers, the neighborhood is over neurons, using the Ge and Act of each neuron,
+// and for 4D layers it is over pools, using the average Ge and Act across the
+// neurons in each pool, which is then applied to all the neurons in the pool.
+// The resulting GiTopo value is added to the pool-level FS-FFFB inhibition.
 type TopoInhibParams struct {
 
 	// use topographic inhibition
@@ -93,8 +96,8 @@ type TopoInhibParams struct {
 	// [viewif: On] normalized gaussian sigma as proportion of Width, for gaussian weighting
 	Sigma float32 `viewif:"On" desc:"normalized gaussian sigma as proportion of Width, for gaussian weighting"`
 
-	// [viewif: On] half-width of topographic inhibition within layer
-	Wrap slbool.Bool `viewif:"On" desc:"half-width of topographic inhibition within layer"`
+	// [viewif: On] wrap the neighborhood around the edges of the layer -- otherwise it is truncated at the edges
+	Wrap slbool.Bool `viewif:"On" desc:"wrap the neighborhood around the edges of the layer -- otherwise it is truncated at the edges"`
 
 	// [viewif: On] overall inhibition multiplier for topographic inhibition (generally <= 1)
 	Gi float32 `viewif:"On" desc:"overall inhibition multiplier for topographic inhibition (generally <= 1)"`
@@ -126,9 +129,43 @@ func (ti *TopoInhibParams) Defaults() {
 }
 
 func (ti *TopoInhibParams) Update() {
-	ssq := ti.Sigma * float32(ti.Width)
-	ssq *= ssq
-	ti.WidthWt = mat32.FastExp(-0.5 * float32(ti.Width) / ssq)
+	ti.WidthWt = ti.GaussWt(ti.Width, 0)
+}
+
+// GaussWt returns the gaussian weight for given y, x offsets from the center
+// of the neighborhood
+func (ti *TopoInhibParams) GaussWt(dy, dx int32) float32 {
+	sig := ti.Sigma * float32(ti.Width)
+	return mat32.FastExp(-0.5 * float32(dy*dy+dx*dx) / (sig * sig))
+}
+
+// Range sets the start and end (inclusive) offsets of the neighborhood
+// for a dimension of size n: -Width..Width, except that when wrapping,
+// the range is limited to n so that each position is only counted once.
+func (ti *TopoInhibParams) Range(n int32, st, ed *int32) {
+	*st = -ti.Width
+	*ed = ti.Width
+	if ti.Wrap.IsTrue() && 2*ti.Width+1 > n {
+		*st = -(n - 1) / 2
+		*ed = n / 2
+	}
+}
+
+// WrapIdx returns the coordinate for given neighborhood coordinate
+// within dimension of size n, wrapping around if Wrap, else returning
+// -1 if it is out of range.
+func (ti *TopoInhibParams) WrapIdx(i, n int32) int32 {
+	if i >= 0 && i < n {
+		return i
+	}
+	if ti.Wrap.IsFalse() {
+		return -1
+	}
+	i = i % n
+	if i < 0 {
+		i += n
+	}
+	return i
 }
 
 func (ti *TopoInhibParams) GiFmGeAct(ge, act, ff0 float32) float32 {
@@ -154,20 +191,23 @@ type InhibParams struct {
 
 	// [view: inline] inhibition within sub-pools of units, for layers with 4D shape -- almost always need this if the layer has pools.
 	Pool fsfffb.GiParams `view:"inline" desc:"inhibition within sub-pools of units, for layers with 4D shape -- almost always need this if the layer has pools."`
+
+	// [view: inline] topographic gaussian inhibition over the neighborhood of neurons (2D) or pools (4D), which is added to the Layer and Pool inhibition
+	Topo TopoInhibParams `view:"inline" desc:"topographic gaussian inhibition over the neighborhood of neurons (2D) or pools (4D), which is added to the Layer and Pool inhibition"`
 }
 
 func (ip *InhibParams) Update() {
 	ip.ActAvg.Update()
 	ip.Layer.Update()
 	ip.Pool.Update()
-	// ip.Topo.Update()
+	ip.Topo.Update()
 }
 
 func (ip *InhibParams) Defaults() {
 	ip.ActAvg.Defaults()
 	ip.Layer.Defaults()
 	ip.Pool.Defaults()
-	// ip.Topo.Defaults()
+	ip.Topo.Defaults()
 	ip.Layer.Gi = 1.1
 	ip.Pool.Gi = 1.1
 }
@@ -0,0 +1,102 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopoInhibParams(t *testing.T) {
+	ti := &TopoInhibParams{}
+	ti.Defaults()
+	assert.InDelta(t, 0.6065, ti.WidthWt, 0.01) // exp(-0.5) for Sigma = 1
+	assert.Equal(t, float32(1), ti.GaussWt(0, 0))
+	assert.Equal(t, ti.GaussWt(1, 2), ti.GaussWt(-2, 1))
+
+	assert.Equal(t, int32(3), ti.WrapIdx(3, 5))
+	assert.Equal(t, int32(4), ti.WrapIdx(-1, 5))
+	assert.Equal(t, int32(1), ti.WrapIdx(6, 5))
+	assert.Equal(t, int32(2), ti.WrapIdx(-8, 5))
+	ti.Wrap.SetBool(false)
+	assert.Equal(t, int32(-1), ti.WrapIdx(-1, 5))
+	assert.Equal(t, int32(-1), ti.WrapIdx(5, 5))
+
+	st, ed := int32(0), int32(0)
+	ti.Range(1, &st, &ed)
+	assert.Equal(t, []int32{-4, 4}, []int32{st, ed})
+	ti.Wrap.SetBool(true)
+	ti.Range(1, &st, &ed)
+	assert.Equal(t, []int32{0, 0}, []int32{st, ed})
+	ti.Range(4, &st, &ed)
+	assert.Equal(t, []int32{-1, 2}, []int32{st, ed})
+	ti.Range(9, &st, &ed)
+	assert.Equal(t, []int32{-4, 4}, []int32{st, ed})
+}
+
+func TestTopoInhib(t *testing.T) {
+	ctx := NewContext()
+	net := NewNetwork("Topo")
+	lay2 := net.AddLayer2D("Lay2D", 1, 8, SuperLayer)
+	lay4 := net.AddLayer4D("Lay4D", 3, 3, 2, 2, SuperLayer)
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	for _, ly := range []*Layer{lay2, lay4} {
+		ly.Params.Inhib.Topo.On.SetBool(true)
+		ly.Params.Inhib.Topo.Width = 2
+		ly.Params.Inhib.Topo.FF0 = 0
+		ly.Params.Inhib.Topo.Wrap.SetBool(false)
+		ly.Params.Inhib.Topo.Update()
+	}
+	net.InitWts(ctx)
+	net.NewState(ctx)
+
+	topoGi := func(ly *Layer) {
+		for lni := uint32(0); lni < ly.NNeurons; lni++ {
+			ly.Params.TopoGiNeuron(ctx, ly.NeurStIdx+lni, 0)
+		}
+	}
+
+	// 2D: neighborhood over neurons
+	SetNrnV(ctx, lay2.NeurStIdx+1, 0, Ge, 1)
+	topoGi(lay2)
+	gi := func(ly *Layer, lni int) float32 {
+		return NrnV(ctx, ly.NeurStIdx+uint32(lni), 0, GiTopo)
+	}
+	tp := &lay2.Params.Inhib.Topo
+	assert.InDelta(t, tp.Gi, gi(lay2, 1), 1.0e-6)
+	assert.InDelta(t, tp.Gi*tp.GaussWt(1, 0), gi(lay2, 0), 1.0e-6)
+	assert.InDelta(t, tp.Gi*tp.GaussWt(2, 0), gi(lay2, 3), 1.0e-6)
+	assert.Equal(t, float32(0), gi(lay2, 4))
+	assert.Equal(t, float32(0), gi(lay2, 7))
+
+	tp.Wrap.SetBool(true)
+	topoGi(lay2)
+	assert.InDelta(t, tp.Gi*tp.GaussWt(2, 0), gi(lay2, 7), 1.0e-6)
+
+	// 4D: neighborhood over pools, using pool average Ge
+	SetNrnV(ctx, lay4.NeurStIdx, 0, Ge, 1) // pool (0, 0)
+	lay4.Params.Inhib.Topo.Width = 1
+	topoGi(lay4)
+	tp = &lay4.Params.Inhib.Topo
+	for lni := 0; lni < 4; lni++ {
+		assert.InDelta(t, tp.Gi*0.25, gi(lay4, lni), 1.0e-6)
+	}
+	assert.InDelta(t, tp.Gi*0.25*tp.GaussWt(1, 1), gi(lay4, 4*4+3), 1.0e-6) // pool (1, 1)
+	assert.Equal(t, float32(0), gi(lay4, 8*4))                              // pool (2, 2)
+
+	// GiTopo is added to Gi in the cycle update
+	net.NewState(ctx)
+	SetNrnV(ctx, lay2.NeurStIdx+1, 0, Ge, 1)
+	net.Cycle(ctx)
+	assert.Greater(t, gi(lay2, 1), float32(0))
+	assert.GreaterOrEqual(t, NrnV(ctx, lay2.NeurStIdx+1, 0, Gi), gi(lay2, 1))
+
+	lay2.Params.Inhib.Topo.On.SetBool(false)
+	net.NewState(ctx)
+	net.Cycle(ctx)
+	assert.Equal(t, float32(0), gi(lay2, 1))
+}
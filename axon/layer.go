@@ -7,10 +7,8 @@ package axon
 import (
 	"fmt"
 	"log"
-	"math/rand"
 	"strings"
 
-	"github.com/emer/emergent/erand"
 	"github.com/emer/etable/etensor"
 	"github.com/goki/ki/ints"
 	"github.com/goki/ki/ki"
@@ -85,9 +83,14 @@ func (ly *Layer) Defaults() {
 		ly.Params.TDPredDefaults()
 	case TDIntegLayer, TDDaLayer:
 		ly.Params.TDDefaults()
+	case SRPredLayer:
+		ly.Params.TDDefaults()
+		ly.Params.SRPredDefaults()
 
 	case LDTLayer:
 		ly.LDTDefaults()
+	case LCLayer, DRNLayer:
+		ly.LCDRNDefaults()
 	case BLALayer:
 		ly.BLADefaults()
 	case CeMLayer:
@@ -150,6 +153,7 @@ func (ly *Layer) PostBuild() {
 	ly.Params.LayInhib.Idx2 = ly.BuildConfigFindLayer("LayInhib2Name", false) // optional
 	ly.Params.LayInhib.Idx3 = ly.BuildConfigFindLayer("LayInhib3Name", false) // optional
 	ly.Params.LayInhib.Idx4 = ly.BuildConfigFindLayer("LayInhib4Name", false) // optional
+	ly.Params.AttnSrc.LayIdx = ly.BuildConfigFindLayer("AttnLayName", false)  // optional
 
 	switch ly.LayerType() {
 	case PulvinarLayer:
@@ -157,12 +161,16 @@ func (ly *Layer) PostBuild() {
 
 	case LDTLayer:
 		ly.LDTPostBuild()
+	case LCLayer, DRNLayer:
+		ly.LCDRNPostBuild()
 	case RWDaLayer:
 		ly.RWDaPostBuild()
 	case TDIntegLayer:
 		ly.TDIntegPostBuild()
 	case TDDaLayer:
 		ly.TDDaPostBuild()
+	case SRPredLayer:
+		ly.SRPredPostBuild()
 
 	case BLALayer:
 		fallthrough
@@ -272,6 +280,18 @@ func (ly *Layer) InitActAvg(ctx *Context) {
 	}
 }
 
+// TrgAvgOrder returns a random order of the TrgAvg values for given number
+// of neurons starting at given layer-relative neuron index, for the
+// TrgAvgAct.Permute option, using the RandFunTrgAvgPerm random stream
+// keyed by network neuron index, with the network RandCtr counter.
+func (ly *Layer) TrgAvgOrder(st, n uint32) []int {
+	keys := make([]uint32, n)
+	for i := range keys {
+		keys[i] = ly.NeurStIdx + st + uint32(i)
+	}
+	return RandKeyOrder(keys, ly.Network.RandCtr, RandFunTrgAvgPerm)
+}
+
 // InitActAvgLayer initializes the running-average activation values
 // that drive learning and the longer time averaging values.
 // version with just overall layer-level inhibition.
@@ -290,7 +310,7 @@ func (ly *Layer) InitActAvgLayer(ctx *Context) {
 		porder[i] = i
 	}
 	if ly.Params.Learn.TrgAvgAct.Permute.IsTrue() {
-		erand.PermuteInts(porder, &ly.Network.Rand)
+		porder = ly.TrgAvgOrder(0, nn)
 	}
 	for lni := uint32(0); lni < nn; lni++ {
 		ni := ly.NeurStIdx + lni
@@ -304,8 +324,8 @@ func (ly *Layer) InitActAvgLayer(ctx *Context) {
 		SetNrnAvgV(ctx, ni, ActAvg, ly.Params.Inhib.ActAvg.Nominal*trg)
 		SetNrnAvgV(ctx, ni, AvgDif, 0)
 		SetNrnAvgV(ctx, ni, DTrgAvg, 0)
-		SetNrnAvgV(ctx, ni, GeBase, ly.Params.Acts.Init.GetGeBase(&ly.Network.Rand))
-		SetNrnAvgV(ctx, ni, GiBase, ly.Params.Acts.Init.GetGiBase(&ly.Network.Rand))
+		SetNrnAvgV(ctx, ni, GeBase, ly.Params.Acts.Init.GetGeBase(ly.Network.RandCtr, ni))
+		SetNrnAvgV(ctx, ni, GiBase, ly.Params.Acts.Init.GetGiBase(ly.Network.RandCtr, ni))
 		if gibinit > 0 {
 			gib := gibinit * (tmax - trg)
 			SetNrnAvgV(ctx, ni, GiBase, gib)
@@ -334,10 +354,10 @@ func (ly *Layer) InitActAvgPools(ctx *Context) {
 		porder[i] = i
 	}
 	for pi := uint32(1); pi < np; pi++ {
+		pl := ly.Pool(pi, 0) // only using for idxs
 		if ly.Params.Learn.TrgAvgAct.Permute.IsTrue() {
-			erand.PermuteInts(porder, &ly.Network.Rand)
+			porder = ly.TrgAvgOrder(pl.StIdx, pl.EdIdx-pl.StIdx)
 		}
-		pl := ly.Pool(pi, 0) // only using for idxs
 		for lni := pl.StIdx; lni < pl.EdIdx; lni++ {
 			ni := ly.NeurStIdx + lni
 			if NrnIsOff(ctx, ni) {
@@ -350,8 +370,8 @@ func (ly *Layer) InitActAvgPools(ctx *Context) {
 			SetNrnAvgV(ctx, ni, ActAvg, ly.Params.Inhib.ActAvg.Nominal*trg)
 			SetNrnAvgV(ctx, ni, AvgDif, 0)
 			SetNrnAvgV(ctx, ni, DTrgAvg, 0)
-			SetNrnAvgV(ctx, ni, GeBase, ly.Params.Acts.Init.GetGeBase(&ly.Network.Rand))
-			SetNrnAvgV(ctx, ni, GiBase, ly.Params.Acts.Init.GetGiBase(&ly.Network.Rand))
+			SetNrnAvgV(ctx, ni, GeBase, ly.Params.Acts.Init.GetGeBase(ly.Network.RandCtr, ni))
+			SetNrnAvgV(ctx, ni, GiBase, ly.Params.Acts.Init.GetGiBase(ly.Network.RandCtr, ni))
 			if gibinit > 0 {
 				gib := gibinit * (tmax - trg)
 				SetNrnAvgV(ctx, ni, GiBase, gib)
@@ -851,7 +871,8 @@ func (ly *Layer) TestVals(ctrKey string, vals map[string]float32) {
 //////////////////////////////////////////////////////////////////////////////////////
 //  Lesion
 
-// UnLesionNeurons unlesions (clears the Off flag) for all neurons in the layer
+// UnLesionNeurons unlesions (clears the Off flag) for all neurons in the layer,
+// and removes the NeuronLesion records for this layer from the network Lesions log.
 func (ly *Layer) UnLesionNeurons() {
 	ctx := &ly.Network.Ctx
 	nn := ly.NNeurons
@@ -861,35 +882,34 @@ func (ly *Layer) UnLesionNeurons() {
 			NrnClearFlag(ctx, ni, di, NeuronOff)
 		}
 	}
+	lg := &ly.Network.Lesions
+	for i := len(lg.Lesions) - 1; i >= 0; i-- {
+		ls := lg.Lesions[i]
+		if ls.Type == NeuronLesion && ls.Layer == ly.Nm {
+			lg.Remove(ls)
+		}
+	}
+	if ly.Network.GPU.On {
+		ly.Network.GPU.SyncNeuronsToGPU()
+	}
 }
 
-// LesionNeurons lesions (sets the Off flag) for given proportion (0-1) of neurons in layer
+// LesionNeurons lesions (sets the Off flag) for given proportion (0-1) of neurons in layer,
+// after first unlesioning all neurons in the layer, selecting neurons at random
+// as a function of the network random seed (see LesionNeuronsProp).
 // returns number of neurons lesioned.  Emits error if prop > 1 as indication that percent
 // might have been passed
 func (ly *Layer) LesionNeurons(prop float32) int {
-	ctx := &ly.Network.Ctx
 	ly.UnLesionNeurons()
 	if prop > 1 {
 		log.Printf("LesionNeurons got a proportion > 1 -- must be 0-1 as *proportion* (not percent) of neurons to lesion: %v\n", prop)
 		return 0
 	}
-	nn := ly.NNeurons
-	if nn == 0 {
+	ls := ly.LesionNeuronsProp(prop)
+	if ls == nil {
 		return 0
 	}
-	p := rand.Perm(int(nn))
-	nl := int(prop * float32(nn))
-	for lni := uint32(0); lni < nn; lni++ {
-		nip := uint32(p[lni])
-		ni := ly.NeurStIdx + nip
-		if NrnIsOff(ctx, ni) {
-			continue
-		}
-		for di := uint32(0); di < ly.MaxData; di++ {
-			NrnSetFlag(ctx, ni, di, NeuronOff)
-		}
-	}
-	return nl
+	return len(ls.Idxs)
 }
 
 //////////////////////////////////////////////////////////////////////////////////////
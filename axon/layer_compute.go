@@ -24,11 +24,13 @@ import (
 //  Cycle
 
 // GatherSpikes integrates G*Raw and G*Syn values for given recv neuron
-// while integrating the Recv Prjn-level GSyn integrated values.
+// while integrating the Recv Prjn-level GSyn integrated values,
+// and computes topographic inhibition (Inhib.Topo) for the neuron.
 func (ly *Layer) GatherSpikes(ctx *Context, ni uint32) {
 	lni := ni - ly.NeurStIdx
 	for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
 		ly.Params.GatherSpikesInit(ctx, ni, di)
+		ly.Params.TopoGiNeuron(ctx, ni, di)
 		for _, pj := range ly.RcvPrjns {
 			if pj.IsOff() {
 				continue
@@ -93,10 +95,12 @@ func (ly *Layer) GiFmSpikes(ctx *Context) {
 }
 
 // PoolGiFmSpikes computes inhibition Gi from Spikes within sub-pools.
-// and also between different layers based on LayInhib* indexes
+// and also between different layers based on LayInhib* indexes,
+// and the Attn attentional modulation from the AttnSrc layer.
 // must happen after LayPoolGiFmSpikes has been called.
 func (ly *Layer) PoolGiFmSpikes(ctx *Context) {
 	ly.BetweenLayerGi(ctx)
+	ly.AttnFmSrc(ctx)
 	np := ly.NPools
 	if np == 1 {
 		return
@@ -140,6 +144,18 @@ func (ly *Layer) BetweenLayerGiMax(net *Network, di uint32, maxGi float32, layId
 	return maxGi
 }
 
+// AttnFmSrc sets the Attn attentional modulation values from the
+// attention source layer, if AttnSrc.LayIdx is set (BuildConfig AttnLayName)
+func (ly *Layer) AttnFmSrc(ctx *Context) {
+	if ly.Params.AttnSrc.LayIdx < 0 {
+		return
+	}
+	sly := ly.Network.Layers[ly.Params.AttnSrc.LayIdx]
+	for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
+		ly.Params.AttnFmSrc(ctx, sly.Params, di)
+	}
+}
+
 func (ly *Layer) PulvinarDriver(ctx *Context, lni, di uint32) (drvGe, nonDrivePct float32) {
 	dly := ly.Network.Layers[int(ly.Params.Pulv.DriveLayIdx)]
 	drvMax := dly.Pool(0, di).AvgMax.CaSpkP.Cycle.Max
@@ -240,7 +256,8 @@ func (ly *Layer) SynCa(ctx *Context, ni uint32) {
 }
 
 // LDTSrcLayAct returns the overall activity level for given source layer
-// for purposes of computing ACh salience value.
+// for purposes of computing ACh salience value (also used for
+// LC NE and DRN 5-HT source layers).
 // Typically the input is a superior colliculus (SC) layer that rapidly
 // accommodates after the onset of a stimulus.
 // using lpl.AvgMax.CaSpkP.Cycle.Max for layer activity measure.
@@ -284,6 +301,10 @@ func (ly *Layer) CyclePost(ctx *Context) {
 			ly.Params.CyclePostLDTLayer(ctx, di, vals, srcLay1Act, srcLay2Act, srcLay3Act, srcLay4Act)
 		case VTALayer:
 			ly.Params.CyclePostVTALayer(ctx, di)
+		case LCLayer:
+			ly.Params.CyclePostLCLayer(ctx, di, ly.LDTSrcLayAct(net, ly.Params.LC.SrcLayIdx, di))
+		case DRNLayer:
+			ly.Params.CyclePostDRNLayer(ctx, di, ly.LDTSrcLayAct(net, ly.Params.DRN.SrcLayIdx, di))
 		case RWDaLayer:
 			pvals := net.LayerVals(uint32(ly.Params.RWDa.RWPredLayIdx), di)
 			ly.Params.CyclePostRWDaLayer(ctx, di, vals, pvals)
@@ -524,6 +545,10 @@ func (ly *Layer) PlusPhase(ctx *Context) {
 			ly.Params.PlusPhasePool(ctx, pl)
 		}
 	}
+	var sly *LayerParams
+	if ly.LayerType() == SRPredLayer {
+		sly = ly.Network.Layers[ly.Params.SRPred.StateLayIdx].Params
+	}
 	nn := ly.NNeurons
 	for lni := uint32(0); lni < nn; lni++ {
 		ni := ly.NeurStIdx + lni
@@ -534,7 +559,16 @@ func (ly *Layer) PlusPhase(ctx *Context) {
 			lpl := ly.Pool(0, di)
 			pl := ly.SubPool(ctx, ni, di)
 			ly.Params.PlusPhaseNeuron(ctx, ni, di, pl, lpl, ly.LayerVals(di))
+			if sly != nil {
+				ly.Params.PlusPhaseSRPredNeuron(ctx, sly, ni, di)
+			}
+		}
+	}
+	for _, pj := range ly.SndPrjns {
+		if pj.IsOff() || !pj.Params.DoTDPredTrace() {
+			continue
 		}
+		pj.TDPredTrace(ctx)
 	}
 }
 
@@ -570,7 +604,8 @@ func (ly *Layer) PlusPhasePost(ctx *Context) {
 	}
 }
 
-// PlusPhaseActAvg updates ActAvg and DTrgAvg at the plus phase
+// PlusPhaseActAvg updates ActAvg and DTrgAvg at the plus phase,
+// with DTrgAvg weighted by the LearnWt global for each data index.
 // Note: could be done on GPU but not worth it at this point..
 func (ly *Layer) PlusPhaseActAvg(ctx *Context) {
 	nn := ly.NNeurons
@@ -582,7 +617,7 @@ func (ly *Layer) PlusPhaseActAvg(ctx *Context) {
 		dTrgSum := float32(0)
 		avgSum := float32(0)
 		for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
-			dTrgSum += ly.Params.LearnTrgAvgErrLRate() * (NrnV(ctx, ni, di, CaSpkP) - NrnV(ctx, ni, di, CaSpkD))
+			dTrgSum += GlbV(ctx, di, GvLearnWt) * ly.Params.LearnTrgAvgErrLRate() * (NrnV(ctx, ni, di, CaSpkP) - NrnV(ctx, ni, di, CaSpkD))
 			avgSum += ly.Params.Acts.Dt.LongAvgDt * (NrnV(ctx, ni, di, ActM) - NrnAvgV(ctx, ni, ActAvg))
 		}
 		AddNrnAvgV(ctx, ni, DTrgAvg, dTrgSum)
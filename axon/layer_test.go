@@ -223,3 +223,65 @@ func TestLayerBase_IsOff(t *testing.T) {
 	assert.True(t, inToHid.IsOff())
 	assert.True(t, in2ToHid.IsOff())
 }
+
+func TestLayerAttnFmSrc(t *testing.T) {
+	net := NewNetwork("AttnTest")
+	lip := net.AddLayer4D("LIP", 2, 2, 1, 1, InputLayer)
+	v2 := net.AddLayer4D("V2", 4, 4, 2, 2, SuperLayer)
+	hid := net.AddLayer2D("Hidden", 2, 4, SuperLayer)
+	sml := net.AddLayer2D("Small", 1, 2, SuperLayer)
+	v2.SetBuildConfig("AttnLayName", "LIP")
+	hid.SetBuildConfig("AttnLayName", "LIP")
+	sml.SetBuildConfig("AttnLayName", "LIP")
+
+	ctx := NewContext()
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	net.InitWts(ctx)
+	net.NewState(ctx)
+	assert.Equal(t, int32(lip.Index()), v2.Params.AttnSrc.LayIdx)
+	assert.Equal(t, int32(-1), lip.Params.AttnSrc.LayIdx)
+	assert.Contains(t, v2.Params.AllParams(), "AttnSrc")
+
+	setLIP := func(vals ...float32) {
+		for i, v := range vals {
+			SetNrnV(ctx, lip.NeurStIdx+uint32(i), 0, CaSpkP, v)
+		}
+	}
+	attn := func(ly *Layer, lni int) float32 {
+		return NrnV(ctx, ly.NeurStIdx+uint32(lni), 0, Attn)
+	}
+	setLIP(0, 0.8, 0.4, 0)
+	v2.AttnFmSrc(ctx)
+	hid.AttnFmSrc(ctx)
+	// V2 4x4 pools map onto LIP 2x2 pools
+	for lni := 0; lni < 4; lni++ {
+		assert.Equal(t, float32(0), attn(v2, lni))        // pool (0, 0) -> LIP (0, 0)
+		assert.Equal(t, float32(1), attn(v2, 2*4+lni))    // pool (0, 2) -> LIP (0, 1)
+		assert.Equal(t, float32(0.5), attn(v2, 13*4+lni)) // pool (3, 1) -> LIP (1, 0)
+	}
+	// Hidden 2x4 units map onto LIP 2x2 pools
+	assert.Equal(t, float32(1), attn(hid, 3))   // (0, 3) -> LIP (0, 1)
+	assert.Equal(t, float32(0.5), attn(hid, 5)) // (1, 1) -> LIP (1, 0)
+	assert.Equal(t, float32(0), attn(hid, 7))   // (1, 3) -> LIP (1, 1)
+
+	// normalized by the max across the whole source layer, even when
+	// the receiving layer only samples part of it: Small 1x2 -> LIP row 1
+	setLIP(0.8, 0, 0.2, 0.4)
+	sml.AttnFmSrc(ctx)
+	assert.Equal(t, float32(0.25), attn(sml, 0))
+	assert.Equal(t, float32(0.5), attn(sml, 1))
+
+	// below threshold: no attentional signal
+	setLIP(0.05, 0.05, 0, 0)
+	v2.AttnFmSrc(ctx)
+	assert.Equal(t, float32(1), attn(v2, 0))
+	assert.Equal(t, float32(1), attn(v2, 15*4))
+
+	// computed from the prior cycle activity as part of the Cycle update
+	setLIP(0, 0.8, 0.4, 0)
+	net.Cycle(ctx)
+	assert.Equal(t, float32(1), attn(v2, 2*4))
+	assert.Equal(t, float32(0.5), attn(v2, 13*4))
+	assert.Equal(t, float32(1), attn(lip, 0))
+}
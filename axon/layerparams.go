@@ -107,6 +107,32 @@ type LayerInhibIdxs struct {
 	Idx4 int32 `inactive:"+" desc:"idx of Layer to geta layer-level inhibition from -- set during Build from BuildConfig LayInhib4Name if present -- -1 if not used"`
 }
 
+// AttnSrcParams determine how the Attn attentional modulation of this layer
+// (see ActParams.AttnMod) is computed from the activity of a designated
+// attention source layer (e.g., LIP, TRN), which provides a pool-wise or
+// topographic attention map that is updated every cycle.
+// The topographic grid of this layer (pools for 4D, units for 2D) is mapped
+// proportionally onto that of the source layer, and Attn is the source
+// CaSpkP at the corresponding position (pool average for 4D source layers),
+// normalized by the max across the source layer.
+type AttnSrcParams struct {
+
+	// index of attention source layer -- set during Build from BuildConfig AttnLayName if present -- -1 if not used
+	LayIdx int32 `inactive:"+" desc:"index of attention source layer -- set during Build from BuildConfig AttnLayName if present -- -1 if not used"`
+
+	// [def: 0.1] threshold on the max CaSpkP across the source layer: below this level, there is no attentional signal and Attn = 1 for all neurons
+	Thr float32 `def:"0.1" desc:"threshold on the max CaSpkP across the source layer: below this level, there is no attentional signal and Attn = 1 for all neurons"`
+
+	pad, pad1 float32
+}
+
+func (as *AttnSrcParams) Defaults() {
+	as.Thr = 0.1
+}
+
+func (as *AttnSrcParams) Update() {
+}
+
 // note: the following must appear above LayerParams for GPU usage which is order sensitive
 
 // SetNeuronExtPosNeg sets neuron Ext value based on neuron index
@@ -147,6 +173,9 @@ type LayerParams struct {
 	// [view: inline] indexes of layers that contribute between-layer inhibition to this layer -- set these indexes via BuildConfig LayInhibXName (X = 1, 2...)
 	LayInhib LayerInhibIdxs `view:"inline" desc:"indexes of layers that contribute between-layer inhibition to this layer -- set these indexes via BuildConfig LayInhibXName (X = 1, 2...)"`
 
+	// [view: inline] attentional modulation of this layer from an attention source layer, which sets the Attn value used in Acts.AttnMod -- set the source layer via BuildConfig AttnLayName
+	AttnSrc AttnSrcParams `view:"inline" desc:"attentional modulation of this layer from an attention source layer, which sets the Attn value used in Acts.AttnMod -- set the source layer via BuildConfig AttnLayName"`
+
 	// [view: add-fields] Learning parameters and methods that operate at the neuron level
 	Learn LearnNeurParams `view:"add-fields" desc:"Learning parameters and methods that operate at the neuron level"`
 
@@ -174,6 +203,12 @@ type LayerParams struct {
 	// [view: inline] [viewif: LayType=VTALayer] parameterizes computing overall VTA DA based on LHb PVDA (primary value -- at US time, computed at start of each trial and stored in LHbPVDA global value) and Amygdala (CeM) CS / learned value (LV) activations, which update every cycle.
 	VTA VTAParams `viewif:"LayType=VTALayer" view:"inline" desc:"parameterizes computing overall VTA DA based on LHb PVDA (primary value -- at US time, computed at start of each trial and stored in LHbPVDA global value) and Amygdala (CeM) CS / learned value (LV) activations, which update every cycle."`
 
+	// [view: inline] [viewif: LayType=LCLayer] parameterizes locus coeruleus NE arousal neuromodulatory signal, driven by urgency, DA surprise, and an optional source layer
+	LC LCParams `viewif:"LayType=LCLayer" view:"inline" desc:"parameterizes locus coeruleus NE arousal neuromodulatory signal, driven by urgency, DA surprise, and an optional source layer"`
+
+	// [view: inline] [viewif: LayType=DRNLayer] parameterizes dorsal raphe nucleus 5-HT serotonin neuromodulatory signal, driven by expected future reward (patience), aversive outcomes, and an optional source layer
+	DRN DRNParams `viewif:"LayType=DRNLayer" view:"inline" desc:"parameterizes dorsal raphe nucleus 5-HT serotonin neuromodulatory signal, driven by expected future reward (patience), aversive outcomes, and an optional source layer"`
+
 	// [view: inline] [viewif: LayType=RWPredLayer] parameterizes reward prediction for a simple Rescorla-Wagner learning dynamic (i.e., PV learning in the PVLV framework).
 	RWPred RWPredParams `viewif:"LayType=RWPredLayer" view:"inline" desc:"parameterizes reward prediction for a simple Rescorla-Wagner learning dynamic (i.e., PV learning in the PVLV framework)."`
 
@@ -186,6 +221,9 @@ type LayerParams struct {
 	// [view: inline] [viewif: LayType=TDDaLayer] parameterizes dopamine (DA) signal as the temporal difference (TD) between the TDIntegLayer activations in the minus and plus phase.
 	TDDa TDDaParams `viewif:"LayType=TDDaLayer" view:"inline" desc:"parameterizes dopamine (DA) signal as the temporal difference (TD) between the TDIntegLayer activations in the minus and plus phase."`
 
+	// [view: inline] [viewif: LayType=SRPredLayer] parameterizes successor representation (SR) learning of discounted future state occupancy over a state layer.
+	SRPred SRPredParams `viewif:"LayType=SRPredLayer" view:"inline" desc:"parameterizes successor representation (SR) learning of discounted future state occupancy over a state layer."`
+
 	// recv and send projection array access info
 	Idxs LayerIdxs `desc:"recv and send projection array access info"`
 }
@@ -194,6 +232,7 @@ func (ly *LayerParams) Update() {
 	ly.Acts.Update()
 	ly.Inhib.Update()
 	ly.Learn.Update()
+	ly.AttnSrc.Update()
 
 	ly.Bursts.Update()
 	ly.CT.Update()
@@ -205,11 +244,14 @@ func (ly *LayerParams) Update() {
 	ly.VSPatch.Update()
 	ly.LDT.Update()
 	ly.VTA.Update()
+	ly.LC.Update()
+	ly.DRN.Update()
 
 	ly.RWPred.Update()
 	ly.RWDa.Update()
 	ly.TDInteg.Update()
 	ly.TDDa.Update()
+	ly.SRPred.Update()
 }
 
 func (ly *LayerParams) Defaults() {
@@ -219,6 +261,7 @@ func (ly *LayerParams) Defaults() {
 	ly.Inhib.Layer.On.SetBool(true)
 	ly.Inhib.Layer.Gi = 1.0
 	ly.Inhib.Pool.Gi = 1.0
+	ly.AttnSrc.Defaults()
 
 	ly.Bursts.Defaults()
 	ly.CT.Defaults()
@@ -230,11 +273,14 @@ func (ly *LayerParams) Defaults() {
 	ly.VSPatch.Defaults()
 	ly.LDT.Defaults()
 	ly.VTA.Defaults()
+	ly.LC.Defaults()
+	ly.DRN.Defaults()
 
 	ly.RWPred.Defaults()
 	ly.RWDa.Defaults()
 	ly.TDInteg.Defaults()
 	ly.TDDa.Defaults()
+	ly.SRPred.Defaults()
 }
 
 // AllParams returns a listing of all parameters in the Layer
@@ -249,6 +295,10 @@ func (ly *LayerParams) AllParams() string {
 	str += "Inhib: {\n " + JsonToParams(b)
 	b, _ = json.MarshalIndent(&ly.Learn, "", " ")
 	str += "Learn: {\n " + JsonToParams(b)
+	if ly.AttnSrc.LayIdx >= 0 {
+		b, _ = json.MarshalIndent(&ly.AttnSrc, "", " ")
+		str += "AttnSrc: {\n " + JsonToParams(b)
+	}
 
 	switch ly.LayType {
 	case SuperLayer:
@@ -277,6 +327,12 @@ func (ly *LayerParams) AllParams() string {
 	case VTALayer:
 		b, _ = json.MarshalIndent(&ly.VTA, "", " ")
 		str += "VTA: {\n " + JsonToParams(b)
+	case LCLayer:
+		b, _ = json.MarshalIndent(&ly.LC, "", " ")
+		str += "LC: {\n " + JsonToParams(b)
+	case DRNLayer:
+		b, _ = json.MarshalIndent(&ly.DRN, "", " ")
+		str += "DRN: {\n " + JsonToParams(b)
 
 	case RWPredLayer:
 		b, _ = json.MarshalIndent(&ly.RWPred, "", " ")
@@ -290,6 +346,9 @@ func (ly *LayerParams) AllParams() string {
 	case TDDaLayer:
 		b, _ = json.MarshalIndent(&ly.TDDa, "", " ")
 		str += "TDDa:    {\n " + JsonToParams(b)
+	case SRPredLayer:
+		b, _ = json.MarshalIndent(&ly.SRPred, "", " ")
+		str += "SRPred:  {\n " + JsonToParams(b)
 	}
 	return str
 }
@@ -419,6 +478,166 @@ func (ly *LayerParams) SubPoolGiFmSpikes(ctx *Context, di uint32, pl *Pool, lpl
 	}
 }
 
+// TopoGeAct returns the Ge and Act values for topographic inhibition at
+// given y, x position in the layer's topographic grid: the neuron at that
+// position for 2D layers, or the average across the neurons in the pool
+// at that position for 4D layers.
+func (ly *LayerParams) TopoGeAct(ctx *Context, di uint32, y, x int32, ge, act *float32) {
+	if ly.Idxs.ShpPlY*ly.Idxs.ShpPlX == 1 {
+		ni := ly.Idxs.NeurSt + uint32(y*ly.Idxs.ShpUnX+x)
+		*ge = NrnV(ctx, ni, di, Ge)
+		*act = NrnV(ctx, ni, di, Act)
+		return
+	}
+	nu := uint32(ly.Idxs.ShpUnY * ly.Idxs.ShpUnX)
+	nst := ly.Idxs.NeurSt + uint32(y*ly.Idxs.ShpPlX+x)*nu
+	sge := float32(0)
+	sact := float32(0)
+	for lni := uint32(0); lni < nu; lni++ {
+		sge += NrnV(ctx, nst+lni, di, Ge)
+		sact += NrnV(ctx, nst+lni, di, Act)
+	}
+	*ge = sge / float32(nu)
+	*act = sact / float32(nu)
+}
+
+// TopoGiPos returns the topographic inhibition at given y, x position
+// in the topographic grid of shape ny, nx, integrating the
+// gaussian-weighted Ge and Act over the neighborhood (see TopoGeAct).
+func (ly *LayerParams) TopoGiPos(ctx *Context, di uint32, y, x, ny, nx int32) float32 {
+	sty := int32(0)
+	edy := int32(0)
+	stx := int32(0)
+	edx := int32(0)
+	ly.Inhib.Topo.Range(ny, &sty, &edy)
+	ly.Inhib.Topo.Range(nx, &stx, &edx)
+	gsum := float32(0)
+	asum := float32(0)
+	wsum := float32(0)
+	for dy := sty; dy <= edy; dy++ {
+		yy := ly.Inhib.Topo.WrapIdx(y+dy, ny)
+		if yy < 0 {
+			continue
+		}
+		for dx := stx; dx <= edx; dx++ {
+			xx := ly.Inhib.Topo.WrapIdx(x+dx, nx)
+			if xx < 0 {
+				continue
+			}
+			wt := ly.Inhib.Topo.GaussWt(dy, dx)
+			ge := float32(0)
+			act := float32(0)
+			ly.TopoGeAct(ctx, di, yy, xx, &ge, &act)
+			gsum += wt * ge
+			asum += wt * act
+			wsum += wt
+		}
+	}
+	return ly.Inhib.Topo.GiFmGeAct(gsum, asum, wsum*ly.Inhib.Topo.FF0)
+}
+
+// TopoGiNeuron computes topographic inhibition into GiTopo for given neuron,
+// if Inhib.Topo.On.  For 4D layers, the neighborhood is over pools, so all
+// neurons in a pool get the same value.  This is called per neuron in
+// GatherSpikes, using the Ge and Act values from the prior cycle, which
+// are not updated until CycleNeuron, so it is safe to run in parallel.
+func (ly *LayerParams) TopoGiNeuron(ctx *Context, ni, di uint32) {
+	if ly.Inhib.Topo.On.IsFalse() {
+		return
+	}
+	lni := int32(ni - ly.Idxs.NeurSt)
+	if ly.Idxs.ShpPlY*ly.Idxs.ShpPlX == 1 {
+		SetNrnV(ctx, ni, di, GiTopo, ly.TopoGiPos(ctx, di, lni/ly.Idxs.ShpUnX, lni%ly.Idxs.ShpUnX, ly.Idxs.ShpUnY, ly.Idxs.ShpUnX))
+		return
+	}
+	pi := lni / (ly.Idxs.ShpUnY * ly.Idxs.ShpUnX)
+	SetNrnV(ctx, ni, di, GiTopo, ly.TopoGiPos(ctx, di, pi/ly.Idxs.ShpPlX, pi%ly.Idxs.ShpPlX, ly.Idxs.ShpPlY, ly.Idxs.ShpPlX))
+}
+
+// AttnSrcAct returns the CaSpkP activity of this layer at given y, x position
+// in the layer's topographic grid: the neuron at that position for 2D layers,
+// or the average across the neurons in the pool at that position for 4D layers.
+func (ly *LayerParams) AttnSrcAct(ctx *Context, di uint32, y, x int32) float32 {
+	if ly.Idxs.ShpPlY*ly.Idxs.ShpPlX == 1 {
+		return NrnV(ctx, ly.Idxs.NeurSt+uint32(y*ly.Idxs.ShpUnX+x), di, CaSpkP)
+	}
+	nu := uint32(ly.Idxs.ShpUnY * ly.Idxs.ShpUnX)
+	nst := ly.Idxs.NeurSt + uint32(y*ly.Idxs.ShpPlX+x)*nu
+	sum := float32(0)
+	for lni := uint32(0); lni < nu; lni++ {
+		sum += NrnV(ctx, nst+lni, di, CaSpkP)
+	}
+	return sum / float32(nu)
+}
+
+// AttnSrcPos returns the attention source activity (see AttnSrcAct) for
+// given y, x position in a topographic grid of shape ny, nx, which is mapped
+// proportionally onto the topographic grid of this (source) layer.
+func (ly *LayerParams) AttnSrcPos(ctx *Context, di uint32, y, x, ny, nx int32) float32 {
+	sny := ly.Idxs.ShpUnY
+	snx := ly.Idxs.ShpUnX
+	if ly.Idxs.ShpPlY*ly.Idxs.ShpPlX > 1 {
+		sny = ly.Idxs.ShpPlY
+		snx = ly.Idxs.ShpPlX
+	}
+	sy := ((2*y + 1) * sny) / (2 * ny)
+	sx := ((2*x + 1) * snx) / (2 * nx)
+	return ly.AttnSrcAct(ctx, di, sy, sx)
+}
+
+// AttnSrcMax returns the max attention source activity (see AttnSrcAct)
+// across all positions in the topographic grid of this (source) layer,
+// including any that are not sampled by the receiving layer.
+func (ly *LayerParams) AttnSrcMax(ctx *Context, di uint32) float32 {
+	sny := ly.Idxs.ShpUnY
+	snx := ly.Idxs.ShpUnX
+	if ly.Idxs.ShpPlY*ly.Idxs.ShpPlX > 1 {
+		sny = ly.Idxs.ShpPlY
+		snx = ly.Idxs.ShpPlX
+	}
+	smax := float32(0)
+	for y := int32(0); y < sny; y++ {
+		for x := int32(0); x < snx; x++ {
+			act := ly.AttnSrcAct(ctx, di, y, x)
+			if act > smax {
+				smax = act
+			}
+		}
+	}
+	return smax
+}
+
+// AttnFmSrc sets the Attn value for all neurons in this layer, for given
+// data index, from the activity of given attention source layer sly,
+// as determined by AttnSrc params: the source activity at the corresponding
+// position, normalized by the max across the source layer (AttnSrcMax).
+// For 4D layers, all neurons in a pool get the same value.
+// This is called in the between-layer inhibition step (PoolGiFmSpikes),
+// using the CaSpkP values from the prior cycle.
+func (ly *LayerParams) AttnFmSrc(ctx *Context, sly *LayerParams, di uint32) {
+	ny := ly.Idxs.ShpUnY
+	nx := ly.Idxs.ShpUnX
+	nu := uint32(1)
+	if ly.Idxs.ShpPlY*ly.Idxs.ShpPlX > 1 {
+		ny = ly.Idxs.ShpPlY
+		nx = ly.Idxs.ShpPlX
+		nu = uint32(ly.Idxs.ShpUnY * ly.Idxs.ShpUnX)
+	}
+	smax := sly.AttnSrcMax(ctx, di)
+	for y := int32(0); y < ny; y++ {
+		for x := int32(0); x < nx; x++ {
+			attn := float32(1)
+			if smax >= ly.AttnSrc.Thr && smax > 0 {
+				attn = sly.AttnSrcPos(ctx, di, y, x, ny, nx) / smax
+			}
+			nst := ly.Idxs.NeurSt + uint32(y*nx+x)*nu
+			for lni := uint32(0); lni < nu; lni++ {
+				SetNrnV(ctx, nst+lni, di, Attn, attn)
+			}
+		}
+	}
+}
+
 //////////////////////////////////////////////////////////////////////////////////////
 //  CycleNeuron methods
 
@@ -543,6 +762,14 @@ func (ly *LayerParams) SpecialPreGs(ctx *Context, ni, di uint32, pl *Pool, vals
 		geRaw := ly.RWDa.GeFmDA(GlbV(ctx, di, GvVtaDA))
 		SetNrnV(ctx, ni, di, GeRaw, geRaw)
 		SetNrnV(ctx, ni, di, GeSyn, ly.Acts.Dt.GeSynFmRawSteady(geRaw))
+	case LCLayer:
+		geRaw := 0.4 * GlbV(ctx, di, GvNE)
+		SetNrnV(ctx, ni, di, GeRaw, geRaw)
+		SetNrnV(ctx, ni, di, GeSyn, ly.Acts.Dt.GeSynFmRawSteady(geRaw))
+	case DRNLayer:
+		geRaw := 0.4 * GlbV(ctx, di, GvSer)
+		SetNrnV(ctx, ni, di, GeRaw, geRaw)
+		SetNrnV(ctx, ni, di, GeSyn, ly.Acts.Dt.GeSynFmRawSteady(geRaw))
 
 	case RewLayer:
 		NrnSetFlag(ctx, ni, di, NeuronHasExt)
@@ -618,15 +845,25 @@ func (ly *LayerParams) GFmRawSyn(ctx *Context, ni, di uint32) {
 	ly.Acts.GvgccFmVm(ctx, ni, di)
 	ege := NrnV(ctx, ni, di, Gnmda) + NrnV(ctx, ni, di, GnmdaMaint) + NrnV(ctx, ni, di, Gvgcc) + extraSyn
 	ly.Acts.GeFmSyn(ctx, ni, di, geSyn, ege) // sets nrn.GeExt too
+	ly.Acts.OUNoiseFmRnd(ctx, ni, di, ly.Idxs.PoolSt/ly.Idxs.MaxData+NrnI(ctx, ni, NrnSubPool))
 	ly.Acts.GkFmVm(ctx, ni, di)
 	ly.Acts.GSkCaFmCa(ctx, ni, di)
 	SetNrnV(ctx, ni, di, GiSyn, ly.Acts.GiFmSyn(ctx, ni, di, NrnV(ctx, ni, di, GiSyn)))
 }
 
 // GiInteg adds Gi values from all sources including SubPool computed inhib
-// and updates GABAB as well
+// and topographic inhibition, and updates GABAB as well
 func (ly *LayerParams) GiInteg(ctx *Context, ni, di uint32, pl *Pool, vals *LayerVals) {
-	gi := vals.ActAvg.GiMult*pl.Inhib.Gi + NrnV(ctx, ni, di, GiSyn) + NrnV(ctx, ni, di, GiNoise) + ly.Learn.NeuroMod.GiFmACh(GlbV(ctx, di, GvACh))
+	gi := vals.ActAvg.GiMult*pl.Inhib.Gi + NrnV(ctx, ni, di, GiSyn) + NrnV(ctx, ni, di, GiNoise) + ly.Learn.NeuroMod.GiFmACh(GlbV(ctx, di, GvACh)) + ly.Learn.NeuroMod.GiFmSer(GlbV(ctx, di, GvSer))
+	if ly.Inhib.Topo.On.IsTrue() {
+		gi += NrnV(ctx, ni, di, GiTopo)
+	}
+	if ly.Acts.OUNoise.On.IsTrue() {
+		gi += NrnV(ctx, ni, di, GiNoiseOU)
+		if gi < 0 {
+			gi = 0
+		}
+	}
 	SetNrnV(ctx, ni, di, Gi, gi)
 	SetNrnV(ctx, ni, di, SSGi, pl.Inhib.SSGi)
 	SetNrnV(ctx, ni, di, SSGiDend, 0)
@@ -653,6 +890,9 @@ func (ly *LayerParams) GNeuroMod(ctx *Context, ni, di uint32, vals *LayerVals) {
 	ggain := ly.Learn.NeuroMod.GGain(GlbV(ctx, di, GvDA))
 	MulNrnV(ctx, ni, di, Ge, ggain)
 	MulNrnV(ctx, ni, di, Gi, ggain)
+	if ly.Learn.NeuroMod.NEGain > 0 {
+		MulNrnV(ctx, ni, di, Ge, ly.Learn.NeuroMod.GeGainFmNE(GlbV(ctx, di, GvNE)))
+	}
 }
 
 ////////////////////////
@@ -768,6 +1008,10 @@ func (ly *LayerParams) PostSpikeSpecial(ctx *Context, ni, di uint32, pl *Pool, l
 		SetNrnV(ctx, ni, di, Act, GlbV(ctx, di, GvAChRaw)) // I set this in CyclePost
 	case VTALayer:
 		SetNrnV(ctx, ni, di, Act, GlbV(ctx, di, GvVtaDA)) // I set this in CyclePost
+	case LCLayer:
+		SetNrnV(ctx, ni, di, Act, GlbV(ctx, di, GvNERaw)) // I set this in CyclePost
+	case DRNLayer:
+		SetNrnV(ctx, ni, di, Act, GlbV(ctx, di, GvSerRaw)) // I set this in CyclePost
 
 	case RewLayer:
 		SetNrnV(ctx, ni, di, Act, GlbV(ctx, di, GvRew))
@@ -791,6 +1035,8 @@ func (ly *LayerParams) PostSpikeSpecial(ctx *Context, ni, di uint32, pl *Pool, l
 		SetNrnV(ctx, ni, di, Act, GlbV(ctx, di, GvRewPred))
 	case TDDaLayer:
 		SetNrnV(ctx, ni, di, Act, GlbV(ctx, di, GvDA)) // I set this in CyclePost
+	case SRPredLayer:
+		SetNrnV(ctx, ni, di, Act, NrnV(ctx, ni, di, Ge)) // linear
 	}
 }
 
@@ -833,6 +1079,24 @@ func (ly *LayerParams) CyclePostLDTLayer(ctx *Context, di uint32, vals *LayerVal
 	}
 }
 
+func (ly *LayerParams) CyclePostLCLayer(ctx *Context, di uint32, srcLayAct float32) {
+	ne := ly.LC.NE(ctx, di, srcLayAct)
+
+	SetGlbV(ctx, di, GvNERaw, ne)
+	if ne > GlbV(ctx, di, GvNE) { // instant up
+		SetGlbV(ctx, di, GvNE, ne)
+	} else {
+		AddGlbV(ctx, di, GvNE, ly.LC.Dt*(ne-GlbV(ctx, di, GvNE)))
+	}
+}
+
+func (ly *LayerParams) CyclePostDRNLayer(ctx *Context, di uint32, srcLayAct float32) {
+	ser := ly.DRN.Ser(ctx, di, srcLayAct)
+
+	SetGlbV(ctx, di, GvSerRaw, ser)
+	AddGlbV(ctx, di, GvSer, ly.DRN.Dt*(ser-GlbV(ctx, di, GvSer)))
+}
+
 func (ly *LayerParams) CyclePostRWDaLayer(ctx *Context, di uint32, vals *LayerVals, pvals *LayerVals) {
 	pred := pvals.Special.V1 - pvals.Special.V2
 	SetGlbV(ctx, di, GvRewPred, pred) // record
@@ -986,7 +1250,7 @@ func (ly *LayerParams) PlusPhaseNeuron(ctx *Context, ni, di uint32, pl *Pool, lp
 	nrnCaSpkP := NrnV(ctx, ni, di, CaSpkP)
 	nrnCaSpkD := NrnV(ctx, ni, di, CaSpkD)
 	mlr := ly.Learn.RLRate.RLRateSigDeriv(nrnCaSpkD, lpl.AvgMax.CaSpkD.Cycle.Max)
-	modlr := ly.Learn.NeuroMod.LRMod(GlbV(ctx, di, GvDA), GlbV(ctx, di, GvACh))
+	modlr := ly.Learn.NeuroMod.LRMod(GlbV(ctx, di, GvDA), GlbV(ctx, di, GvACh), GlbV(ctx, di, GvNE), GlbV(ctx, di, GvSer))
 	dlr := float32(1)
 	switch ly.LayType {
 	case BLALayer:
@@ -1011,4 +1275,19 @@ func (ly *LayerParams) PlusPhaseNeuron(ctx *Context, ni, di uint32, pl *Pool, lp
 	SetNrnV(ctx, ni, di, SahpCa, ly.Acts.Sahp.CaInt(NrnV(ctx, ni, di, SahpCa), nrnCaSpkD))
 }
 
+// PlusPhaseSRPredNeuron computes the successor representation TD error
+// for SRPredLayer neurons at end of the plus phase, after PlusPhaseNeuron,
+// given the activity (ActInt) of the corresponding neuron in state layer sly,
+// and saves the current prediction for use on the next trial.
+func (ly *LayerParams) PlusPhaseSRPredNeuron(ctx *Context, sly *LayerParams, ni, di uint32) {
+	lni := ni - ly.Idxs.NeurSt
+	stateAct := float32(0)
+	if lni < sly.Idxs.NeurN {
+		stateAct = NrnV(ctx, sly.Idxs.NeurSt+lni, di, ActInt)
+	}
+	pred := NrnV(ctx, ni, di, ActP)
+	SetNrnV(ctx, ni, di, SRErr, ly.SRPred.Err(stateAct, pred, NrnV(ctx, ni, di, SRPrv)))
+	SetNrnV(ctx, ni, di, SRPrv, pred)
+}
+
 //gosl: end layerparams
@@ -197,6 +197,23 @@ const (
 	// vial Global state values to all layers.
 	VTALayer
 
+	// LCLayer represents the locus coeruleus, which releases
+	// norepinephrine (NE) as a global arousal signal.  It computes
+	// NE from Global Urgency, the magnitude of DA (surprise), and the
+	// activity of an optional source layer (BuildConfig SrcLayName),
+	// and its activity reflects the raw NE level, which is broadcast
+	// via Global state values to all layers (see NeuroModParams).
+	LCLayer
+
+	// DRNLayer represents the dorsal raphe nucleus, which releases
+	// serotonin (5-HT) as a global patience / aversive signal.
+	// It computes 5-HT from Global PVposEst (expected future reward),
+	// PVneg (aversive outcomes incl. effort), and the activity of an
+	// optional source layer (BuildConfig SrcLayName), and its activity
+	// reflects the raw 5-HT level, which is broadcast via Global state
+	// values to all layers (see NeuroModParams).
+	DRNLayer
+
 	/////////////
 	// RL
 
@@ -237,13 +254,26 @@ const (
 	// These are retrieved from Special LayerVals.
 	TDDaLayer
 
+	// SRPredLayer learns a successor representation (SR) of the discounted
+	// future occupancy of each unit in a state layer (set via BuildConfig
+	// SRStateLayName), with one unit per state unit.
+	// Activity is a linear function of excitatory conductance from SRPrjn
+	// projections from the state layer, representing SR(t) for the current state.
+	// At the end of the plus phase it computes a TD-like error for each unit:
+	// state(t) + Discount * SR(t) - SR(t-1), which drives SRPrjn learning.
+	// Value is read out by a TDPredLayer receiving a TDPredPrjn from this layer,
+	// whose weights come to reflect the reward associated with each state,
+	// so that values can be quickly revalued when rewards change.
+	// See AddSRLayers.
+	SRPredLayer
+
 	LayerTypesN
 )
 
 // IsExtLayerType returns true if the layer type deals with external input:
-// Input, Target, Compare
+// Input, Target, Compare, and Matrix (for BGActionSelector exploration noise)
 func IsExtLayerType(lt LayerTypes) bool {
-	if lt == InputLayer || lt == TargetLayer || lt == CompareLayer || lt == RewLayer {
+	if lt == InputLayer || lt == TargetLayer || lt == CompareLayer || lt == RewLayer || lt == MatrixLayer {
 		return true
 	}
 	return false
@@ -252,9 +282,9 @@ func IsExtLayerType(lt LayerTypes) bool {
 //gosl: end layertypes
 
 // IsExt returns true if the layer type deals with external input:
-// Input, Target, Compare
+// Input, Target, Compare, and Matrix (for BGActionSelector exploration noise)
 func (lt LayerTypes) IsExt() bool {
-	if lt == InputLayer || lt == TargetLayer || lt == CompareLayer || lt == RewLayer {
+	if lt == InputLayer || lt == TargetLayer || lt == CompareLayer || lt == RewLayer || lt == MatrixLayer {
 		return true
 	}
 	return false
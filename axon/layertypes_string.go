@@ -38,18 +38,21 @@ func _() {
 	_ = x[PVLayer-22]
 	_ = x[LDTLayer-23]
 	_ = x[VTALayer-24]
-	_ = x[RewLayer-25]
-	_ = x[RWPredLayer-26]
-	_ = x[RWDaLayer-27]
-	_ = x[TDPredLayer-28]
-	_ = x[TDIntegLayer-29]
-	_ = x[TDDaLayer-30]
-	_ = x[LayerTypesN-31]
+	_ = x[LCLayer-25]
+	_ = x[DRNLayer-26]
+	_ = x[RewLayer-27]
+	_ = x[RWPredLayer-28]
+	_ = x[RWDaLayer-29]
+	_ = x[TDPredLayer-30]
+	_ = x[TDIntegLayer-31]
+	_ = x[TDDaLayer-32]
+	_ = x[SRPredLayer-33]
+	_ = x[LayerTypesN-34]
 }
 
-const _LayerTypes_name = "SuperLayerInputLayerTargetLayerCompareLayerCTLayerPulvinarLayerTRNLayerPTMaintLayerPTPredLayerPTNotMaintLayerMatrixLayerSTNLayerGPLayerBGThalLayerVSGatedLayerBLALayerCeMLayerVSPatchLayerLHbLayerDrivesLayerUrgencyLayerUSLayerPVLayerLDTLayerVTALayerRewLayerRWPredLayerRWDaLayerTDPredLayerTDIntegLayerTDDaLayerLayerTypesN"
+const _LayerTypes_name = "SuperLayerInputLayerTargetLayerCompareLayerCTLayerPulvinarLayerTRNLayerPTMaintLayerPTPredLayerPTNotMaintLayerMatrixLayerSTNLayerGPLayerBGThalLayerVSGatedLayerBLALayerCeMLayerVSPatchLayerLHbLayerDrivesLayerUrgencyLayerUSLayerPVLayerLDTLayerVTALayerLCLayerDRNLayerRewLayerRWPredLayerRWDaLayerTDPredLayerTDIntegLayerTDDaLayerSRPredLayerLayerTypesN"
 
-var _LayerTypes_index = [...]uint16{0, 10, 20, 31, 43, 50, 63, 71, 83, 94, 109, 120, 128, 135, 146, 158, 166, 174, 186, 194, 205, 217, 224, 231, 239, 247, 255, 266, 275, 286, 298, 307, 318}
+var _LayerTypes_index = [...]uint16{0, 10, 20, 31, 43, 50, 63, 71, 83, 94, 109, 120, 128, 135, 146, 158, 166, 174, 186, 194, 205, 217, 224, 231, 239, 247, 254, 262, 270, 281, 290, 301, 313, 322, 333, 344}
 
 func (i LayerTypes) String() string {
 	if i < 0 || i >= LayerTypes(len(_LayerTypes_index)-1) {
@@ -94,13 +97,16 @@ var _LayerTypes_descMap = map[LayerTypes]string{
 	22: `PVLayer represents a PV primary value layer (PVpos or PVneg) representing the total primary value as a function of US inputs, drives, and effort. It tracks the Global VTA.PVpos, PVneg values for visualization and predictive learning purposes.`,
 	23: `LDTLayer represents the laterodorsal tegmentum layer, which is the primary limbic ACh (acetylcholine) driver to other ACh: BG cholinergic interneurons (CIN) and nucleus basalis ACh areas. The phasic ACh release signals reward salient inputs from CS, US and US omssion, and it drives widespread disinhibition of BG gating and VTA DA firing. It receives excitation from superior colliculus which computes a temporal derivative (stimulus specific adaptation, SSA) of sensory inputs, and inhibitory input from OFC, ACC driving suppression of distracting inputs during goal-engaged states.`,
 	24: `VTALayer represents the ventral tegmental area, which releases dopamine. It computes final DA value from PVLV-computed LHb PVDA (primary value DA), updated at start of each trial from updated US, Effort, etc state, and cycle-by-cycle LV learned value state reflecting CS inputs, in the Amygdala (CeM). Its activity reflects this DA level, which is effectively broadcast vial Global state values to all layers.`,
-	25: `RewLayer represents positive or negative reward values across 2 units, showing spiking rates for each, and Act always represents signed value.`,
-	26: `RWPredLayer computes reward prediction for a simple Rescorla-Wagner learning dynamic (i.e., PV learning in the PVLV framework). Activity is computed as linear function of excitatory conductance (which can be negative -- there are no constraints). Use with RWPrjn which does simple delta-rule learning on minus-plus.`,
-	27: `RWDaLayer computes a dopamine (DA) signal based on a simple Rescorla-Wagner learning dynamic (i.e., PV learning in the PVLV framework). It computes difference between r(t) and RWPred values. r(t) is accessed directly from a Rew layer -- if no external input then no DA is computed -- critical for effective use of RW only for PV cases. RWPred prediction is also accessed directly from Rew layer to avoid any issues.`,
-	28: `TDPredLayer is the temporal differences reward prediction layer. It represents estimated value V(t) in the minus phase, and computes estimated V(t+1) based on its learned weights in plus phase, using the TDPredPrjn projection type for DA modulated learning.`,
-	29: `TDIntegLayer is the temporal differences reward integration layer. It represents estimated value V(t) from prior time step in the minus phase, and estimated discount * V(t+1) + r(t) in the plus phase. It gets Rew, PrevPred from Context.NeuroMod, and Special LayerVals from TDPredLayer.`,
-	30: `TDDaLayer computes a dopamine (DA) signal as the temporal difference (TD) between the TDIntegLayer activations in the minus and plus phase. These are retrieved from Special LayerVals.`,
-	31: ``,
+	25: `LCLayer represents the locus coeruleus, which releases norepinephrine (NE) as a global arousal signal. It computes NE from Global Urgency, the magnitude of DA (surprise), and the activity of an optional source layer (BuildConfig SrcLayName), and its activity reflects the raw NE level, which is broadcast via Global state values to all layers (see NeuroModParams).`,
+	26: `DRNLayer represents the dorsal raphe nucleus, which releases serotonin (5-HT) as a global patience / aversive signal. It computes 5-HT from Global PVposEst (expected future reward), PVneg (aversive outcomes incl. effort), and the activity of an optional source layer (BuildConfig SrcLayName), and its activity reflects the raw 5-HT level, which is broadcast via Global state values to all layers (see NeuroModParams).`,
+	27: `RewLayer represents positive or negative reward values across 2 units, showing spiking rates for each, and Act always represents signed value.`,
+	28: `RWPredLayer computes reward prediction for a simple Rescorla-Wagner learning dynamic (i.e., PV learning in the PVLV framework). Activity is computed as linear function of excitatory conductance (which can be negative -- there are no constraints). Use with RWPrjn which does simple delta-rule learning on minus-plus.`,
+	29: `RWDaLayer computes a dopamine (DA) signal based on a simple Rescorla-Wagner learning dynamic (i.e., PV learning in the PVLV framework). It computes difference between r(t) and RWPred values. r(t) is accessed directly from a Rew layer -- if no external input then no DA is computed -- critical for effective use of RW only for PV cases. RWPred prediction is also accessed directly from Rew layer to avoid any issues.`,
+	30: `TDPredLayer is the temporal differences reward prediction layer. It represents estimated value V(t) in the minus phase, and computes estimated V(t+1) based on its learned weights in plus phase, using the TDPredPrjn projection type for DA modulated learning.`,
+	31: `TDIntegLayer is the temporal differences reward integration layer. It represents estimated value V(t) from prior time step in the minus phase, and estimated discount * V(t+1) + r(t) in the plus phase. It gets Rew, PrevPred from Context.NeuroMod, and Special LayerVals from TDPredLayer.`,
+	32: `TDDaLayer computes a dopamine (DA) signal as the temporal difference (TD) between the TDIntegLayer activations in the minus and plus phase. These are retrieved from Special LayerVals.`,
+	33: `SRPredLayer learns a successor representation (SR) of the discounted future occupancy of each unit in a state layer (set via BuildConfig SRStateLayName), with one unit per state unit. Activity is a linear function of excitatory conductance from SRPrjn projections from the state layer, representing SR(t) for the current state. At the end of the plus phase it computes a TD-like error for each unit: state(t) + Discount * SR(t) - SR(t-1), which drives SRPrjn learning. Value is read out by a TDPredLayer receiving a TDPredPrjn from this layer, whose weights come to reflect the reward associated with each state, so that values can be quickly revalued when rewards change. See AddSRLayers.`,
+	34: ``,
 }
 
 func (i LayerTypes) Desc() string {
@@ -7,9 +7,9 @@ package axon
 import (
 	"github.com/emer/axon/chans"
 	"github.com/emer/axon/kinase"
-	"github.com/emer/emergent/erand"
 	"github.com/emer/etable/minmax"
 	"github.com/goki/gosl/slbool"
+	"github.com/goki/gosl/slrand"
 	"github.com/goki/mat32"
 )
 
@@ -471,9 +471,11 @@ func (sp *SWtAdaptParams) Update() {
 
 //gosl: end learn
 
-// RndVar returns the random variance in weight value (zero mean) based on Var param
-func (sp *SWtInitParams) RndVar(rnd erand.Rand) float32 {
-	return sp.Var * 2.0 * (rnd.Float32(-1) - 0.5)
+// RndVar returns the random variance in weight value (zero mean) based on Var param,
+// using the RandFunSWtInit random stream for given synapse index,
+// with given counter (the network RandCtr).
+func (sp *SWtInitParams) RndVar(ctr slrand.Counter, syni uint32) float32 {
+	return sp.Var * 2.0 * (GetRandomNumber(syni, ctr, RandFunSWtInit) - 0.5)
 }
 
 // // RndVar returns the random variance (zero mean) based on DreamVar param
@@ -615,8 +617,8 @@ func DecaySynCa(ctx *Context, syni, di uint32, decay float32) {
 // InitWtsSyn initializes weight values based on WtInit randomness parameters
 // for an individual synapse.
 // It also updates the linear weight value based on the sigmoidal weight value.
-func (sp *SWtParams) InitWtsSyn(ctx *Context, syni uint32, rnd erand.Rand, mean, spct float32) {
-	wtv := sp.Init.RndVar(rnd)
+func (sp *SWtParams) InitWtsSyn(ctx *Context, syni uint32, ctr slrand.Counter, mean, spct float32) {
+	wtv := sp.Init.RndVar(ctr, syni)
 	wt := mean + wtv
 	SetSynV(ctx, syni, Wt, wt)
 	SetSynV(ctx, syni, SWt, sp.ClipSWt(mean+spct*wtv))
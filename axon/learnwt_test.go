@@ -0,0 +1,134 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"os"
+	"testing"
+
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/prjn"
+	"github.com/emer/etable/etensor"
+	"github.com/stretchr/testify/assert"
+)
+
+// learnWtTrial runs one training trial with 2 data items having given
+// learning weights, on the GPU if gpu is true, checking DWt and returning
+// the Hidden DTrgAvg values and the Output projection DWt values.
+func learnWtTrial(t *testing.T, wts []float32, gpu bool) ([]float32, []float32) {
+	net, ctx := newTestNetLayers(t, 2, func(net *Network) {
+		inp := net.AddLayer2D("Input", 2, 2, InputLayer)
+		hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+		out := net.AddLayer2D("Output", 2, 2, TargetLayer)
+		net.ConnectLayers(inp, hid, prjn.NewFull(), ForwardPrjn)
+		net.BidirConnectLayers(hid, out, prjn.NewFull())
+	}, nil)
+	inp := net.AxonLayerByName("Input")
+	hid := net.AxonLayerByName("Hidden")
+	out := net.AxonLayerByName("Output")
+	for di := uint32(0); di < 2; di++ {
+		assert.Equal(t, float32(1), GlbV(ctx, di, GvLearnWt))
+		GlobalSetLearnWt(ctx, di, wts[di])
+	}
+	if gpu {
+		net.ConfigGPUnoGUI(ctx)
+		defer net.GPU.Destroy()
+	}
+
+	ctx.NewState(etime.Train)
+	net.NewState(ctx)
+	net.InitExt(ctx)
+	for di := uint32(0); di < 2; di++ {
+		pat := etensor.NewFloat32([]int{2, 2}, nil, nil)
+		pat.SetZeros()
+		pat.Values[di] = 1
+		inp.ApplyExt(ctx, di, pat)
+		pat.Values[3] = 1
+		out.ApplyExt(ctx, di, pat)
+	}
+	net.ApplyExts(ctx)
+	for cyc := 0; cyc < 200; cyc++ {
+		net.Cycle(ctx)
+		ctx.CycleInc()
+		if cyc == 149 {
+			net.MinusPhase(ctx)
+			ctx.NewPhase(true)
+			net.PlusPhaseStart(ctx)
+		}
+	}
+	net.PlusPhase(ctx)
+	net.DWt(ctx)
+	net.GPU.SyncSynapsesFmGPU()
+	net.GPU.SyncSynCaFmGPU()
+
+	pj := out.RcvPrjns[0]
+	var dwts []float32
+	for syi := uint32(0); syi < pj.NSyns; syi++ {
+		syni := pj.SynStIdx + syi
+		dwt := wts[0]*SynCaV(ctx, syni, 0, DiDWt) + wts[1]*SynCaV(ctx, syni, 1, DiDWt)
+		assert.InDelta(t, dwt, SynV(ctx, syni, DWt), 1.0e-7)
+		dwts = append(dwts, SynV(ctx, syni, DWt))
+	}
+	var dtrgs []float32
+	for lni := uint32(0); lni < hid.NNeurons; lni++ {
+		dtrgs = append(dtrgs, NrnAvgV(ctx, hid.NeurStIdx+lni, DTrgAvg))
+	}
+	return dtrgs, dwts
+}
+
+func TestLearnWt(t *testing.T) {
+	dtrg, _ := learnWtTrial(t, []float32{1, 1}, false)
+	nonzero := false
+	for _, d := range dtrg {
+		if d != 0 {
+			nonzero = true
+		}
+	}
+	assert.True(t, nonzero)
+	learnWtTrial(t, []float32{1, 0}, false)
+	learnWtTrial(t, []float32{0.5, 2}, false)
+
+	dtrg, _ = learnWtTrial(t, []float32{0, 0}, false)
+	for _, d := range dtrg {
+		assert.Equal(t, float32(0), d)
+	}
+}
+
+// TestLearnWtGlobalIdx checks that GvLearnWt (and the other added scalar
+// globals) do not move any of the existing global variables: they come
+// after GvVtaDA, and the USneg, USpos globals are accessed through the
+// NetIdxs offsets, so CPU and GPU global layouts stay the same.
+func TestLearnWtGlobalIdx(t *testing.T) {
+	assert.Equal(t, GlobalVars(41), GvVtaDA)
+	assert.Equal(t, GvVtaDA+3, GvLearnWt)
+	assert.Equal(t, GvLearnWt+1, GvUSneg)
+
+	ctx := NewContext()
+	ctx.NetIdxs.MaxData = 2
+	ctx.NetIdxs.PVLVNNegUSs = 1
+	ctx.NetIdxs.PVLVNPosUSs = 2
+	ctx.SetGlobalStrides()
+	assert.Equal(t, ctx.GlobalIdx(0, GvUSneg), ctx.NetIdxs.GvUSnegOff)
+	assert.Less(t, ctx.GlobalIdx(1, GvLearnWt), ctx.NetIdxs.GvUSnegOff)
+	assert.Equal(t, ctx.NetIdxs.GvUSposOff, ctx.GlobalUSposIdx(0, GvDrives, 0))
+}
+
+// TestGPULearnWt checks that the GPU DWt weights each data index by
+// LearnWt the same as the CPU.
+func TestGPULearnWt(t *testing.T) {
+	if os.Getenv("TEST_GPU") != "true" {
+		t.Skip("Set TEST_GPU env var to run GPU tests")
+	}
+	for _, wts := range [][]float32{{1, 1}, {1, 0}, {0.5, 2}} {
+		cpuTrg, cpuDWt := learnWtTrial(t, wts, false)
+		gpuTrg, gpuDWt := learnWtTrial(t, wts, true)
+		for i := range cpuDWt {
+			assert.InDelta(t, cpuDWt[i], gpuDWt[i], 1.0e-4, "wts: %v syn: %d", wts, i)
+		}
+		for i := range cpuTrg {
+			assert.InDelta(t, cpuTrg[i], gpuTrg[i], 1.0e-4, "wts: %v neuron: %d", wts, i)
+		}
+	}
+}
@@ -0,0 +1,562 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/kit"
+)
+
+//go:generate stringer -type=LesionTypes
+
+var KiT_LesionTypes = kit.Enums.AddEnum(LesionTypesN, kit.NotBitFlag, nil)
+
+func (ev LesionTypes) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *LesionTypes) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// LesionTypes are the different types of lesions recorded in the LesionLog
+type LesionTypes int32
+
+const (
+	// NeuronLesion turns off (sets the NeuronOff flag) a set of neurons in a layer
+	NeuronLesion LesionTypes = iota
+
+	// SynapseLesion zeroes the weights of a set of synapses in a projection,
+	// optionally rescaling the projection to compensate
+	SynapseLesion
+
+	// GradedLesion is graded damage to all of the neurons in a layer:
+	// the excitatory conductance Gbar.E is multiplied by a factor,
+	// and the noise in the excitatory and inhibitory conductances is set
+	GradedLesion
+
+	LesionTypesN
+)
+
+// Lesion is a record of one lesion applied to the network, with the specific
+// neurons or synapses lesioned, so that it can be reversed (see UnLesion)
+// and reproduced exactly (see Network.ApplyLesions).
+type Lesion struct {
+
+	// type of lesion
+	Type LesionTypes `desc:"type of lesion"`
+
+	// name of the layer, or receiving layer for SynapseLesion
+	Layer string `desc:"name of the layer, or receiving layer for SynapseLesion"`
+
+	// name of the projection for SynapseLesion
+	Prjn string `desc:"name of the projection for SynapseLesion"`
+
+	// how the lesioned neurons or synapses were selected, e.g., Prop: 0.2
+	Desc string `desc:"how the lesioned neurons or synapses were selected, e.g., Prop: 0.2"`
+
+	// layer-relative neuron indexes for NeuronLesion (only those that were not already lesioned), or projection-relative synapse indexes for SynapseLesion
+	Idxs []int `desc:"layer-relative neuron indexes for NeuronLesion (only those that were not already lesioned), or projection-relative synapse indexes for SynapseLesion"`
+
+	// for SynapseLesion, multiplier applied to the projection PrjnScale.Abs to compensate for the lesioned synapses -- 1 = no rescaling
+	ScaleMult float32 `desc:"for SynapseLesion, multiplier applied to the projection PrjnScale.Abs to compensate for the lesioned synapses -- 1 = no rescaling"`
+
+	// for GradedLesion, multiplier on the layer Acts.Gbar.E excitatory conductance
+	GbarMult float32 `desc:"for GradedLesion, multiplier on the layer Acts.Gbar.E excitatory conductance"`
+
+	// for GradedLesion, amount of noise that Acts.Noise.Ge and .Gi are set to, which is turned on if > 0
+	Noise float32 `desc:"for GradedLesion, amount of noise that Acts.Noise.Ge and .Gi are set to, which is turned on if > 0"`
+
+	// [view: -] for SynapseLesion, original Wt, LWt, SWt values of each lesioned synapse, for restoring -- saved so that lesions reapplied to weights saved from a lesioned network can still be reversed
+	OrigWts []float32 `view:"-" desc:"for SynapseLesion, original Wt, LWt, SWt values of each lesioned synapse, for restoring -- saved so that lesions reapplied to weights saved from a lesioned network can still be reversed"`
+
+	// [view: -] for GradedLesion, original Gbar.E value
+	OrigGbarE float32 `view:"-" json:"-" desc:"for GradedLesion, original Gbar.E value"`
+
+	// [view: -] for GradedLesion, original noise params
+	OrigNoise SpikeNoiseParams `view:"-" json:"-" desc:"for GradedLesion, original noise params"`
+}
+
+func (ls *Lesion) String() string {
+	str := fmt.Sprintf("%s: %s", ls.Type, ls.Layer)
+	if ls.Prjn != "" {
+		str += " " + ls.Prjn
+	}
+	if ls.Desc != "" {
+		str += " " + ls.Desc
+	}
+	if ls.Type == GradedLesion {
+		return str + fmt.Sprintf(" GbarMult: %g Noise: %g", ls.GbarMult, ls.Noise)
+	}
+	return str + fmt.Sprintf(" N: %d", len(ls.Idxs))
+}
+
+// LesionLog is a record of all the lesions applied to the network,
+// in order, which can be saved and reapplied to reproduce them exactly.
+type LesionLog struct {
+
+	// lesions in the order applied
+	Lesions []*Lesion `desc:"lesions in the order applied"`
+}
+
+// Add adds given lesion to the log
+func (lg *LesionLog) Add(ls *Lesion) {
+	lg.Lesions = append(lg.Lesions, ls)
+}
+
+// Remove removes given lesion from the log, returning false if not found
+func (lg *LesionLog) Remove(ls *Lesion) bool {
+	for i, l := range lg.Lesions {
+		if l == ls {
+			lg.Lesions = append(lg.Lesions[:i], lg.Lesions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// SaveJSON saves the lesion log to a JSON-formatted file
+func (lg *LesionLog) SaveJSON(filename string) error {
+	b, err := json.MarshalIndent(lg, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0644)
+}
+
+// OpenJSON opens the lesion log from a JSON-formatted file
+func (lg *LesionLog) OpenJSON(filename string) error {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, lg)
+}
+
+// LesionsFileName returns the name of the lesion log file saved alongside
+// given weights file name, replacing the .wts or .wts.gz extension with
+// .lesions.json
+func LesionsFileName(wtsFile string) string {
+	fnm := strings.TrimSuffix(wtsFile, ".gz")
+	fnm = strings.TrimSuffix(fnm, filepath.Ext(fnm))
+	return fnm + ".lesions.json"
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//  Layer neuron lesions
+
+// LesionNeuronIdxs lesions (sets the Off flag) for given layer-relative
+// neuron indexes, and records the lesion in the network LesionLog.
+// Neurons that are already lesioned are skipped.
+// Returns the lesion record, or nil if no neurons were lesioned.
+func (ly *Layer) LesionNeuronIdxs(idxs []int) *Lesion {
+	return ly.lesionNeurons(idxs, fmt.Sprintf("Idxs: %v", idxs))
+}
+
+// lesionNeurons lesions given neurons, recording the lesion with given desc
+func (ly *Layer) lesionNeurons(idxs []int, desc string) *Lesion {
+	ctx := &ly.Network.Ctx
+	ls := &Lesion{Type: NeuronLesion, Layer: ly.Nm, Desc: desc}
+	for _, lni := range idxs {
+		if lni < 0 || lni >= int(ly.NNeurons) {
+			continue
+		}
+		ni := ly.NeurStIdx + uint32(lni)
+		if NrnIsOff(ctx, ni) {
+			continue
+		}
+		for di := uint32(0); di < ly.MaxData; di++ {
+			NrnSetFlag(ctx, ni, di, NeuronOff)
+		}
+		ls.Idxs = append(ls.Idxs, lni)
+	}
+	if len(ls.Idxs) == 0 {
+		return nil
+	}
+	ly.Network.Lesions.Add(ls)
+	if ly.Network.GPU.On {
+		ly.Network.GPU.SyncNeuronsToGPU()
+	}
+	return ls
+}
+
+// LesionPools lesions all of the neurons in given sub-pools of a 4D layer,
+// specified by 0-based pool index (not including the layer-level pool).
+// Returns the lesion record, or nil if no neurons were lesioned.
+func (ly *Layer) LesionPools(pools ...int) *Lesion {
+	var idxs []int
+	for _, pi := range pools {
+		if pi < 0 || pi+1 >= int(ly.NPools) {
+			continue
+		}
+		pl := ly.Pool(uint32(pi+1), 0)
+		for lni := pl.StIdx; lni < pl.EdIdx; lni++ {
+			idxs = append(idxs, int(lni))
+		}
+	}
+	return ly.lesionNeurons(idxs, fmt.Sprintf("Pools: %v", pools))
+}
+
+// LesionRegion lesions the neurons within the rectangular region
+// from y0, x0 (inclusive) to y1, x1 (exclusive), in the 2D display
+// coordinates of the layer, where the units within pools are nested
+// within the pools for 4D layers (e.g., the region can span multiple
+// pools, or part of a pool).
+// Returns the lesion record, or nil if no neurons were lesioned.
+func (ly *Layer) LesionRegion(y0, x0, y1, x1 int) *Lesion {
+	var idxs []int
+	for lni := 0; lni < int(ly.NNeurons); lni++ {
+		y, x := pos2D(&ly.Shp, lni)
+		if y >= y0 && y < y1 && x >= x0 && x < x1 {
+			idxs = append(idxs, lni)
+		}
+	}
+	return ly.lesionNeurons(idxs, fmt.Sprintf("Region: %d,%d - %d,%d", y0, x0, y1, x1))
+}
+
+// LesionNeuronsProp lesions given proportion (0-1) of the neurons in
+// the layer that are not already lesioned, selected at random using
+// the RandFunLesion random stream keyed by network neuron index, with
+// the network RandCtr counter, so that lesions are reproducible for
+// a given random seed, independent of any other use of random numbers.
+// Returns the lesion record, or nil if no neurons were lesioned.
+func (ly *Layer) LesionNeuronsProp(prop float32) *Lesion {
+	if prop > 1 {
+		prop = 1
+	}
+	ctx := &ly.Network.Ctx
+	var on []int
+	var keys []uint32
+	for lni := 0; lni < int(ly.NNeurons); lni++ {
+		ni := ly.NeurStIdx + uint32(lni)
+		if !NrnIsOff(ctx, ni) {
+			on = append(on, lni)
+			keys = append(keys, ni)
+		}
+	}
+	p := RandKeyOrder(keys, ly.Network.RandCtr, RandFunLesion)
+	nl := int(prop * float32(len(on)))
+	idxs := make([]int, nl)
+	for i := 0; i < nl; i++ {
+		idxs[i] = on[p[i]]
+	}
+	return ly.lesionNeurons(idxs, fmt.Sprintf("Prop: %g", prop))
+}
+
+// LesionGraded applies graded damage to all of the neurons in the layer,
+// multiplying the excitatory conductance Gbar.E by gbarMult, and setting
+// the noise in the excitatory and inhibitory conductances (Acts.Noise.Ge, Gi)
+// to given noise value, which is turned on if noise > 0 (otherwise the
+// existing noise params are unchanged).  The original parameters are restored
+// by UnLesion.  Note that subsequent parameter settings (e.g., applying a
+// params sheet) will override this lesion.
+func (ly *Layer) LesionGraded(gbarMult, noise float32) *Lesion {
+	ac := &ly.Params.Acts
+	ls := &Lesion{Type: GradedLesion, Layer: ly.Nm, GbarMult: gbarMult, Noise: noise, OrigGbarE: ac.Gbar.E, OrigNoise: ac.Noise}
+	ac.Gbar.E *= gbarMult
+	if noise > 0 {
+		ac.Noise.On.SetBool(true)
+		ac.Noise.Ge = noise
+		ac.Noise.Gi = noise
+		ac.Noise.Update()
+	}
+	ly.Network.Lesions.Add(ls)
+	if ly.Network.GPU.On {
+		ly.Network.GPU.SyncParamsToGPU()
+	}
+	return ls
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//  Prjn synapse lesions
+
+// LesionSynIdxs lesions the synapses at given projection-relative synapse
+// indexes (0 to NSyns), by setting the Wt, LWt, SWt and DWt values to 0,
+// and records the lesion in the network LesionLog.  If rescale is true,
+// then PrjnScale.Abs is multiplied by the inverse of the proportion of
+// non-lesioned synapses remaining, so that the overall strength of the
+// projection is preserved, as in homeostatic compensation after partial
+// damage.  Lesioned synapses are those with a SWt of 0.
+// Note that learning can partially restore lesioned weights,
+// and InitWts resets them (use Network.ApplyLesions to reapply).
+// Returns the lesion record, or nil if no synapses were lesioned.
+func (pj *Prjn) LesionSynIdxs(syis []int, rescale bool) *Lesion {
+	return pj.lesionSynsRescale(syis, rescale, fmt.Sprintf("Idxs: %v", syis))
+}
+
+// lesionSynsRescale lesions given synapses, computing the rescaling
+// multiplier if rescale, based on the number of non-lesioned synapses
+// before and after.
+func (pj *Prjn) lesionSynsRescale(syis []int, rescale bool, desc string) *Lesion {
+	net := pj.Recv.Network
+	ctx := &net.Ctx
+	if net.GPU.On {
+		net.GPU.SyncSynapsesFmGPU()
+	}
+	mult := float32(1)
+	if rescale {
+		nact := 0
+		for syi := uint32(0); syi < pj.NSyns; syi++ {
+			if SynV(ctx, pj.SynStIdx+syi, SWt) != 0 {
+				nact++
+			}
+		}
+		nles := 0
+		for _, syi := range syis {
+			if syi >= 0 && syi < int(pj.NSyns) && SynV(ctx, pj.SynStIdx+uint32(syi), SWt) != 0 {
+				nles++
+			}
+		}
+		if nact > nles {
+			mult = float32(nact) / float32(nact-nles)
+		}
+	}
+	return pj.lesionSyns(syis, mult, desc)
+}
+
+// lesionSyns lesions given synapses, multiplying PrjnScale.Abs by
+// given scaling multiplier, and recording the lesion with given desc.
+// Synapses are always recorded, even if already zero, so that lesions
+// can be reapplied to weights saved from a lesioned network.
+func (pj *Prjn) lesionSyns(syis []int, scaleMult float32, desc string) *Lesion {
+	net := pj.Recv.Network
+	ctx := &net.Ctx
+	if net.GPU.On {
+		net.GPU.SyncSynapsesFmGPU()
+	}
+	ls := &Lesion{Type: SynapseLesion, Layer: pj.Recv.Nm, Prjn: pj.Name(), Desc: desc, ScaleMult: 1}
+	for _, syi := range syis {
+		if syi < 0 || syi >= int(pj.NSyns) {
+			continue
+		}
+		syni := pj.SynStIdx + uint32(syi)
+		ls.OrigWts = append(ls.OrigWts, SynV(ctx, syni, Wt), SynV(ctx, syni, LWt), SynV(ctx, syni, SWt))
+		SetSynV(ctx, syni, Wt, 0)
+		SetSynV(ctx, syni, LWt, 0)
+		SetSynV(ctx, syni, SWt, 0)
+		SetSynV(ctx, syni, DWt, 0)
+		ls.Idxs = append(ls.Idxs, syi)
+	}
+	if len(ls.Idxs) == 0 {
+		return nil
+	}
+	if scaleMult != 1 {
+		ls.ScaleMult = scaleMult
+		pj.Params.PrjnScale.Abs *= scaleMult
+		pj.Recv.InitGScale(ctx)
+	}
+	net.Lesions.Add(ls)
+	if net.GPU.On {
+		net.GPU.SyncParamsToGPU()
+		net.GPU.SyncSynapsesToGPU()
+	}
+	return ls
+}
+
+// LesionSynapses lesions given proportion (0-1) of the synapses in the
+// projection that are not already lesioned (i.e., have a non-zero SWt),
+// selected at random using the RandFunLesion random stream keyed by
+// network synapse index, with the network RandCtr counter, so that
+// lesions are reproducible for a given random seed.
+// See LesionSynIdxs for details, including rescale.
+// Returns the lesion record, or nil if no synapses were lesioned.
+func (pj *Prjn) LesionSynapses(prop float32, rescale bool) *Lesion {
+	if prop > 1 {
+		prop = 1
+	}
+	net := pj.Recv.Network
+	ctx := &net.Ctx
+	if net.GPU.On {
+		net.GPU.SyncSynapsesFmGPU()
+	}
+	var on []int
+	var keys []uint32
+	for syi := 0; syi < int(pj.NSyns); syi++ {
+		syni := pj.SynStIdx + uint32(syi)
+		if SynV(ctx, syni, SWt) != 0 {
+			on = append(on, syi)
+			keys = append(keys, syni)
+		}
+	}
+	p := RandKeyOrder(keys, net.RandCtr, RandFunLesion)
+	nl := int(prop * float32(len(on)))
+	syis := make([]int, nl)
+	for i := 0; i < nl; i++ {
+		syis[i] = on[p[i]]
+	}
+	return pj.lesionSynsRescale(syis, rescale, fmt.Sprintf("Prop: %g", prop))
+}
+
+// recvPrjnByName returns the receiving projection with given name
+func (ly *Layer) recvPrjnByName(name string) (*Prjn, error) {
+	for _, pj := range ly.RcvPrjns {
+		if pj.Name() == name {
+			return pj, nil
+		}
+	}
+	return nil, fmt.Errorf("Layer: %s receiving projection named: %s not found", ly.Nm, name)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//  Network lesion management
+
+// UnLesion reverses given lesion, which must have been applied to this
+// network (i.e., it is in the Lesions log), and removes it from the log.
+// Lesions that overlap with other lesions should be reversed in the
+// reverse order in which they were applied, as in UnLesionAll.
+func (nt *Network) UnLesion(ls *Lesion) error {
+	if !nt.Lesions.Remove(ls) {
+		return fmt.Errorf("UnLesion: lesion not found in network: %s", ls)
+	}
+	ctx := &nt.Ctx
+	ly, err := nt.LayByNameTry(ls.Layer)
+	if err != nil {
+		return err
+	}
+	switch ls.Type {
+	case NeuronLesion:
+		for _, lni := range ls.Idxs {
+			ni := ly.NeurStIdx + uint32(lni)
+			for di := uint32(0); di < ly.MaxData; di++ {
+				NrnClearFlag(ctx, ni, di, NeuronOff)
+			}
+		}
+		if nt.GPU.On {
+			nt.GPU.SyncNeuronsToGPU()
+		}
+	case SynapseLesion:
+		pj, err := ly.recvPrjnByName(ls.Prjn)
+		if err != nil {
+			return err
+		}
+		if nt.GPU.On {
+			nt.GPU.SyncSynapsesFmGPU()
+		}
+		for i, syi := range ls.Idxs {
+			syni := pj.SynStIdx + uint32(syi)
+			SetSynV(ctx, syni, Wt, ls.OrigWts[i*3])
+			SetSynV(ctx, syni, LWt, ls.OrigWts[i*3+1])
+			SetSynV(ctx, syni, SWt, ls.OrigWts[i*3+2])
+		}
+		if ls.ScaleMult != 1 {
+			pj.Params.PrjnScale.Abs /= ls.ScaleMult
+			ly.InitGScale(ctx)
+		}
+		if nt.GPU.On {
+			nt.GPU.SyncParamsToGPU()
+			nt.GPU.SyncSynapsesToGPU()
+		}
+	case GradedLesion:
+		ly.Params.Acts.Gbar.E = ls.OrigGbarE
+		ly.Params.Acts.Noise = ls.OrigNoise
+		if nt.GPU.On {
+			nt.GPU.SyncParamsToGPU()
+		}
+	}
+	return nil
+}
+
+// UnLesionAll reverses all of the lesions in the Lesions log,
+// in the reverse order in which they were applied.
+func (nt *Network) UnLesionAll() error {
+	var errs []error
+	for i := len(nt.Lesions.Lesions) - 1; i >= 0; i-- {
+		if err := nt.UnLesion(nt.Lesions.Lesions[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("UnLesionAll: %v", errs)
+	}
+	return nil
+}
+
+// ApplyLesions applies the lesions in given log to this network,
+// lesioning exactly the same neurons and synapses as recorded,
+// adding them to the network Lesions log.
+func (nt *Network) ApplyLesions(lg *LesionLog) error {
+	var errs []error
+	for _, ls := range lg.Lesions {
+		ly, err := nt.LayByNameTry(ls.Layer)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var nls *Lesion
+		switch ls.Type {
+		case NeuronLesion:
+			nls = ly.lesionNeurons(ls.Idxs, ls.Desc)
+		case SynapseLesion:
+			pj, err := ly.recvPrjnByName(ls.Prjn)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			nls = pj.lesionSyns(ls.Idxs, ls.ScaleMult, ls.Desc)
+			if nls != nil && len(nls.OrigWts) == len(ls.OrigWts) {
+				// synapses already zeroed in the current weights were lesioned
+				// when saved: use the recorded original weights for restoring
+				for i := 0; i < len(ls.OrigWts); i += 3 {
+					if nls.OrigWts[i+2] == 0 {
+						copy(nls.OrigWts[i:i+3], ls.OrigWts[i:i+3])
+					}
+				}
+			}
+		case GradedLesion:
+			nls = ly.LesionGraded(ls.GbarMult, ls.Noise)
+		}
+		if nls == nil {
+			errs = append(errs, fmt.Errorf("ApplyLesions: nothing lesioned for: %s", ls))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("ApplyLesions: %v", errs)
+	}
+	return nil
+}
+
+// SaveLesionsJSON saves the Lesions log to a JSON-formatted file.
+// See also SaveWtsLesionsJSON.
+func (nt *Network) SaveLesionsJSON(filename string) error {
+	return nt.Lesions.SaveJSON(filename)
+}
+
+// OpenLesionsJSON opens a lesion log from a JSON-formatted file,
+// and applies the lesions to the network (see ApplyLesions).
+func (nt *Network) OpenLesionsJSON(filename string) error {
+	lg := &LesionLog{}
+	if err := lg.OpenJSON(filename); err != nil {
+		return err
+	}
+	return nt.ApplyLesions(lg)
+}
+
+// SaveWtsLesionsJSON saves the network weights to given file (see SaveWtsJSON),
+// and the Lesions log, if there are any lesions, alongside it, in a file
+// named by LesionsFileName.
+func (nt *Network) SaveWtsLesionsJSON(filename string) error {
+	if err := nt.SaveWtsJSON(gi.FileName(filename)); err != nil {
+		return err
+	}
+	if len(nt.Lesions.Lesions) == 0 {
+		return nil
+	}
+	return nt.SaveLesionsJSON(LesionsFileName(filename))
+}
+
+// OpenWtsLesionsJSON opens the network weights from given file
+// (see OpenWtsJSON), and applies the lesions from the lesion log saved
+// alongside it by SaveWtsLesionsJSON, if it exists.
+func (nt *Network) OpenWtsLesionsJSON(filename string) error {
+	if err := nt.OpenWtsJSON(gi.FileName(filename)); err != nil {
+		return err
+	}
+	lfn := LesionsFileName(filename)
+	if _, err := os.Stat(lfn); err != nil {
+		return nil
+	}
+	return nt.OpenLesionsJSON(lfn)
+}
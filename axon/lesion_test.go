@@ -0,0 +1,172 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/emer/emergent/prjn"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLesionTestNet(t *testing.T) *Network {
+	net, _ := newTestNetLayers(t, 1, func(net *Network) {
+		in := net.AddLayer2D("Input", 4, 4, InputLayer)
+		hid := net.AddLayer4D("Hidden", 2, 2, 2, 2, SuperLayer)
+		net.ConnectLayers(in, hid, prjn.NewFull(), ForwardPrjn)
+	}, nil)
+	return net
+}
+
+func lesionedIdxs(ly *Layer) []int {
+	ctx := &ly.Network.Ctx
+	var idxs []int
+	for lni := 0; lni < int(ly.NNeurons); lni++ {
+		if NrnIsOff(ctx, ly.NeurStIdx+uint32(lni)) {
+			idxs = append(idxs, lni)
+		}
+	}
+	return idxs
+}
+
+func TestLesionNeurons(t *testing.T) {
+	net := newLesionTestNet(t)
+	hid := net.AxonLayerByName("Hidden")
+
+	ls := hid.LesionNeuronIdxs([]int{1, 3, 100})
+	assert.Equal(t, []int{1, 3}, ls.Idxs)
+	assert.Nil(t, hid.LesionNeuronIdxs([]int{1}))
+
+	ls2 := hid.LesionPools(3)
+	assert.Equal(t, []int{12, 13, 14, 15}, ls2.Idxs)
+
+	// upper-left 2x2 region = pool 0, which has 1, 3 already lesioned
+	ls3 := hid.LesionRegion(0, 0, 2, 2)
+	assert.Equal(t, []int{0, 2}, ls3.Idxs)
+	assert.Equal(t, []int{0, 1, 2, 3, 12, 13, 14, 15}, lesionedIdxs(hid))
+	assert.Equal(t, 3, len(net.Lesions.Lesions))
+
+	assert.NoError(t, net.UnLesion(ls2))
+	assert.Equal(t, []int{0, 1, 2, 3}, lesionedIdxs(hid))
+	assert.Error(t, net.UnLesion(ls2))
+	assert.NoError(t, net.UnLesionAll())
+	assert.Equal(t, 0, len(lesionedIdxs(hid)))
+	assert.Equal(t, 0, len(net.Lesions.Lesions))
+
+	// random lesions are reproducible given the same seed,
+	// independent of other uses of the network Rand
+	net.SetRndSeed(7)
+	net.InitWts(&net.Ctx)
+	ls = hid.LesionNeuronsProp(0.5)
+	assert.Equal(t, 8, len(ls.Idxs))
+	idxs := lesionedIdxs(hid)
+	hid.UnLesionNeurons()
+	assert.Equal(t, 0, len(net.Lesions.Lesions))
+	net.Rand.Float64(-1)
+	assert.Equal(t, 8, hid.LesionNeurons(0.5))
+	assert.Equal(t, idxs, lesionedIdxs(hid))
+	hid.UnLesionNeurons()
+	net.SetRndSeed(8)
+	net.InitWts(&net.Ctx)
+	hid.LesionNeurons(0.5)
+	assert.NotEqual(t, idxs, lesionedIdxs(hid))
+}
+
+func TestLesionSynapses(t *testing.T) {
+	net := newLesionTestNet(t)
+	ctx := &net.Ctx
+	hid := net.AxonLayerByName("Hidden")
+	pj := hid.RcvPrjns[0]
+	abs := pj.Params.PrjnScale.Abs
+	gscale := pj.Params.GScale.Scale
+	wt5 := SynV(ctx, pj.SynStIdx+5, Wt)
+
+	ls := pj.LesionSynapses(0.25, true)
+	nsyn := int(pj.NSyns)
+	assert.Equal(t, nsyn/4, len(ls.Idxs))
+	assert.InDelta(t, 4.0/3.0, ls.ScaleMult, 1.0e-6)
+	assert.InDelta(t, abs*ls.ScaleMult, pj.Params.PrjnScale.Abs, 1.0e-6)
+	assert.Greater(t, pj.Params.GScale.Scale, gscale)
+	for _, syi := range ls.Idxs {
+		assert.Equal(t, float32(0), SynV(ctx, pj.SynStIdx+uint32(syi), Wt))
+	}
+
+	ls2 := pj.LesionSynIdxs([]int{5}, false)
+	assert.Equal(t, float32(1), ls2.ScaleMult)
+	assert.Equal(t, float32(0), SynV(ctx, pj.SynStIdx+5, SWt))
+
+	assert.NoError(t, net.UnLesionAll())
+	assert.Equal(t, abs, pj.Params.PrjnScale.Abs)
+	assert.InDelta(t, gscale, pj.Params.GScale.Scale, 1.0e-6)
+	assert.Equal(t, wt5, SynV(ctx, pj.SynStIdx+5, Wt))
+	for syi := uint32(0); syi < pj.NSyns; syi++ {
+		assert.NotEqual(t, float32(0), SynV(ctx, pj.SynStIdx+syi, SWt))
+	}
+}
+
+func TestLesionGraded(t *testing.T) {
+	net := newLesionTestNet(t)
+	hid := net.AxonLayerByName("Hidden")
+	ac := &hid.Params.Acts
+	gbar := ac.Gbar.E
+	ac.Noise.Ge = 0.005
+	noise := ac.Noise
+
+	ls := hid.LesionGraded(0.5, 0.01)
+	assert.Equal(t, gbar*0.5, ac.Gbar.E)
+	assert.True(t, ac.Noise.On.IsTrue())
+	assert.Equal(t, float32(0.01), ac.Noise.Ge)
+	assert.Equal(t, float32(0.01), ac.Noise.Gi)
+
+	assert.NoError(t, net.UnLesion(ls))
+	assert.Equal(t, gbar, ac.Gbar.E)
+	assert.Equal(t, noise, ac.Noise)
+}
+
+func TestLesionsJSON(t *testing.T) {
+	net := newLesionTestNet(t)
+	ctx := &net.Ctx
+	hid := net.AxonLayerByName("Hidden")
+	pj := hid.RcvPrjns[0]
+	var origWts []float32
+	for syi := uint32(0); syi < pj.NSyns; syi++ {
+		origWts = append(origWts, SynV(ctx, pj.SynStIdx+syi, Wt))
+	}
+	abs := pj.Params.PrjnScale.Abs
+	hid.LesionPools(1)
+	pj.LesionSynapses(0.5, true)
+	hid.LesionGraded(0.8, 0)
+	// neuron and synapse values must be read before building another network
+	idxs := lesionedIdxs(hid)
+	synIdxs := net.Lesions.Lesions[1].Idxs
+
+	fn := filepath.Join(t.TempDir(), "lesion.wts.gz")
+	assert.Equal(t, filepath.Join(filepath.Dir(fn), "lesion.lesions.json"), LesionsFileName(fn))
+	assert.NoError(t, net.SaveWtsLesionsJSON(fn))
+
+	net2 := newLesionTestNet(t)
+	ctx2 := &net2.Ctx
+	hid2 := net2.AxonLayerByName("Hidden")
+	pj2 := hid2.RcvPrjns[0]
+	assert.NoError(t, net2.OpenWtsLesionsJSON(fn))
+	assert.Equal(t, 3, len(net2.Lesions.Lesions))
+	assert.Equal(t, idxs, lesionedIdxs(hid2))
+	assert.Equal(t, pj.Params.PrjnScale.Abs, pj2.Params.PrjnScale.Abs)
+	assert.Equal(t, hid.Params.Acts.Gbar.E, hid2.Params.Acts.Gbar.E)
+	assert.Equal(t, synIdxs, net2.Lesions.Lesions[1].Idxs)
+	for _, syi := range synIdxs {
+		assert.Equal(t, float32(0), SynV(ctx2, pj2.SynStIdx+uint32(syi), Wt))
+	}
+
+	// unlesioning the replayed network restores the original weights,
+	// to the precision of the weights file
+	assert.NoError(t, net2.UnLesionAll())
+	assert.Equal(t, 0, len(lesionedIdxs(hid2)))
+	for syi := uint32(0); syi < pj2.NSyns; syi++ {
+		assert.InDelta(t, origWts[syi], SynV(ctx2, pj2.SynStIdx+syi, Wt), 1.0e-4)
+	}
+	assert.Equal(t, abs, pj2.Params.PrjnScale.Abs)
+}
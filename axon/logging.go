@@ -224,6 +224,29 @@ func LogAddLayerGeActAvgItems(lg *elog.Logs, net *Network, mode etime.Modes, etm
 	}
 }
 
+// LogAddLRateSchedItems adds the LRate.Sched learning rate multiplier for
+// each projection having an LRateSchedule, for given mode and time
+// (e.g., Train, Epoch).  Schedules must be set prior to calling this
+// (see Network.SetPrjnLRateSched).
+func LogAddLRateSchedItems(lg *elog.Logs, net *Network, mode etime.Modes, etm etime.Times) {
+	for _, ly := range net.Layers {
+		for _, pj := range ly.RcvPrjns {
+			if pj.LRateSchedule.Policy == LRateSchedOff {
+				continue
+			}
+			cpj := pj
+			lg.AddItem(&elog.Item{
+				Name:  cpj.Name() + "_LRateSched",
+				Type:  etensor.FLOAT64,
+				Range: minmax.F64{Max: 1},
+				Write: elog.WriteMap{
+					etime.Scope(mode, etm): func(ctx *elog.Context) {
+						ctx.SetFloat32(cpj.Params.Learn.LRate.Sched)
+					}}})
+		}
+	}
+}
+
 // LogAddExtraDiagnosticItems adds extra Axon diagnostic statistics to given logs,
 // across two given time levels, in higher to lower order, e.g., Epoch, Trial
 // These are useful for tuning and diagnosing the behavior of the network.
@@ -78,7 +78,9 @@ func LooperSimCycleAndLearn(man *looper.Manager, net *Network, ctx *Context, vie
 		})
 	}
 	man.GetLoop(etime.Train, trl).OnEnd.Add("UpdateWeights", func() {
-		net.DWt(ctx)
+		if net.PhaseSeq == nil { // otherwise done at end of each plus phase
+			net.DWt(ctx)
+		}
 		if viewupdt.IsViewingSynapse() {
 			net.GPU.SyncSynapsesFmGPU()
 			net.GPU.SyncSynCaFmGPU() // note: only time we call this
@@ -165,3 +167,16 @@ func LooperUpdtPlots(man *looper.Manager, gui *egui.GUI) {
 		}
 	}
 }
+
+// LooperLRateSched adds a call to Network.LRateSchedEpoch at the end of
+// each Train Epoch, applying the Prjn.LRateSchedule learning rate schedules
+// for the next epoch (InitWts applies them for epoch 0), using given
+// function to return the error measure for the epoch just completed
+// (e.g., PctErr) for the Plateau policy.
+// Call after adding the epoch logging functions, so the error is current.
+func LooperLRateSched(man *looper.Manager, net *Network, errFun func() float32) {
+	epcLoop := man.GetLoop(etime.Train, etime.Epoch)
+	epcLoop.OnEnd.Add("LRateSched", func() {
+		net.LRateSchedEpoch(epcLoop.Counter.Cur+1, errFun())
+	})
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+//go:generate stringer -type=LRateSchedTypes
+
+var KiT_LRateSchedTypes = kit.Enums.AddEnum(LRateSchedTypesN, kit.NotBitFlag, nil)
+
+// LRateSchedTypes are the policies for the epoch-based
+// learning rate schedule in LRateSchedParams.
+type LRateSchedTypes int32
+
+const (
+	// LRateSchedOff does not apply any schedule: LRate.Sched is only
+	// set by explicit calls to LRateSched.
+	LRateSchedOff LRateSchedTypes = iota
+
+	// LRateSchedWarmup only ramps up the learning rate over the
+	// initial Warmup epochs, after which it stays at 1.
+	LRateSchedWarmup
+
+	// LRateSchedStep multiplies the learning rate by Factor every
+	// Step epochs after Warmup, down to Min.
+	LRateSchedStep
+
+	// LRateSchedCosine anneals the learning rate from 1 down to Min
+	// over Period epochs after Warmup, following a half cosine,
+	// and stays at Min thereafter.
+	LRateSchedCosine
+
+	// LRateSchedPlateau multiplies the learning rate by Factor, down to Min,
+	// whenever the error measure (e.g., PctErr) has not improved by at least
+	// Thr over Patience epochs after Warmup.
+	LRateSchedPlateau
+
+	LRateSchedTypesN
+)
+
+// LRateSchedParams specifies an epoch-based learning rate schedule,
+// which sets the Learn.LRate.Sched multiplier for each epoch via
+// Network.LRateSchedEpoch.  The Warmup applies to all policies.
+// These params are only used on the CPU, between epochs, so they live on
+// the Prjn (Prjn.LRateSched) instead of the GPU PrjnParams, and are set
+// with Network.SetPrjnLRateSched, using param Sel selector syntax
+// to select projections by class or name.
+type LRateSchedParams struct {
+
+	// schedule policy -- Off = no automatic schedule
+	Policy LRateSchedTypes `desc:"schedule policy -- Off = no automatic schedule"`
+
+	// [viewif: Policy!=LRateSchedOff] number of initial epochs over which the learning rate ramps up linearly from 1/Warmup to 1 -- 0 = no warmup
+	Warmup int32 `viewif:"Policy!=LRateSchedOff" desc:"number of initial epochs over which the learning rate ramps up linearly from 1/Warmup to 1 -- 0 = no warmup"`
+
+	// [def: 100] [viewif: Policy=LRateSchedStep] number of epochs between each reduction by Factor, for Step policy
+	Step int32 `def:"100" viewif:"Policy=LRateSchedStep" desc:"number of epochs between each reduction by Factor, for Step policy"`
+
+	// [def: 500] [viewif: Policy=LRateSchedCosine] number of epochs over which the learning rate anneals to Min, for Cosine policy
+	Period int32 `def:"500" viewif:"Policy=LRateSchedCosine" desc:"number of epochs over which the learning rate anneals to Min, for Cosine policy"`
+
+	// [def: 10] [viewif: Policy=LRateSchedPlateau] number of epochs without improvement in the error before reducing by Factor, for Plateau policy
+	Patience int32 `def:"10" viewif:"Policy=LRateSchedPlateau" desc:"number of epochs without improvement in the error before reducing by Factor, for Plateau policy"`
+
+	// [def: 0.5] [viewif: Policy!=LRateSchedOff] multiplier applied at each reduction, for Step and Plateau policies
+	Factor float32 `def:"0.5" viewif:"Policy!=LRateSchedOff" desc:"multiplier applied at each reduction, for Step and Plateau policies"`
+
+	// [def: 0.01] [viewif: Policy!=LRateSchedOff] minimum schedule multiplier
+	Min float32 `def:"0.01" viewif:"Policy!=LRateSchedOff" desc:"minimum schedule multiplier"`
+
+	// [def: 0.01] [viewif: Policy=LRateSchedPlateau] minimum decrease in the error that counts as an improvement, for Plateau policy
+	Thr float32 `def:"0.01" viewif:"Policy=LRateSchedPlateau" desc:"minimum decrease in the error that counts as an improvement, for Plateau policy"`
+}
+
+func (ls *LRateSchedParams) Defaults() {
+	ls.Policy = LRateSchedOff
+	ls.Warmup = 0
+	ls.Step = 100
+	ls.Period = 500
+	ls.Patience = 10
+	ls.Factor = 0.5
+	ls.Min = 0.01
+	ls.Thr = 0.01
+}
+
+func (ls *LRateSchedParams) Update() {
+}
+
+// LRateSchedState is the state of the LRateSchedParams schedule
+// for a projection, which is saved in the weights file MetaData.
+type LRateSchedState struct {
+
+	// current multiplier from reductions by the Plateau policy
+	Mult float32 `inactive:"+" desc:"current multiplier from reductions by the Plateau policy"`
+
+	// best (lowest) error so far, for the Plateau policy
+	Best float32 `inactive:"+" desc:"best (lowest) error so far, for the Plateau policy"`
+
+	// number of epochs since the last improvement in the error, for the Plateau policy
+	NBad int32 `inactive:"+" desc:"number of epochs since the last improvement in the error, for the Plateau policy"`
+}
+
+// Init initializes the state at the start of learning
+func (st *LRateSchedState) Init() {
+	st.Mult = 1
+	st.Best = mat32.MaxFloat32
+	st.NBad = 0
+}
+
+// Sched returns the learning rate schedule multiplier to use during
+// the given epoch (0 based).  For the Plateau policy, it first updates
+// the state with given error measure (e.g., PctErr) computed over the
+// previous epoch, which is ignored for epoch 0 and during Warmup.
+func (ls *LRateSchedParams) Sched(st *LRateSchedState, epoch int, err float32) float32 {
+	if ls.Policy == LRateSchedOff {
+		return 1
+	}
+	ep := epoch - int(ls.Warmup)
+	if ep < 0 {
+		return float32(epoch+1) / float32(ls.Warmup)
+	}
+	sched := float32(1)
+	switch ls.Policy {
+	case LRateSchedStep:
+		if ls.Step > 0 {
+			sched = mat32.Pow(ls.Factor, float32(ep/int(ls.Step)))
+		}
+	case LRateSchedCosine:
+		if ls.Period > 0 {
+			prog := mat32.Min(float32(ep)/float32(ls.Period), 1)
+			sched = ls.Min + 0.5*(1-ls.Min)*(1+mat32.Cos(mat32.Pi*prog))
+		}
+	case LRateSchedPlateau:
+		if ep > 0 {
+			ls.PlateauUpdate(st, err)
+		}
+		sched = st.Mult
+	}
+	return mat32.Max(sched, ls.Min)
+}
+
+// PlateauUpdate updates the Plateau policy state with given error
+// measure computed over an epoch, reducing Mult by Factor if
+// the error has not improved over Patience epochs.
+func (ls *LRateSchedParams) PlateauUpdate(st *LRateSchedState, err float32) {
+	if err < st.Best-ls.Thr {
+		st.Best = err
+		st.NBad = 0
+		return
+	}
+	st.NBad++
+	if st.NBad >= ls.Patience {
+		st.Mult = mat32.Max(st.Mult*ls.Factor, ls.Min)
+		st.Best = mat32.Min(st.Best, err)
+		st.NBad = 0
+	}
+}
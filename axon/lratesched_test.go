@@ -0,0 +1,134 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/looper"
+	"github.com/emer/emergent/prjn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRateSchedParams(t *testing.T) {
+	ls := LRateSchedParams{}
+	ls.Defaults()
+	st := LRateSchedState{}
+	st.Init()
+	assert.Equal(t, float32(1), ls.Sched(&st, 10, 0))
+
+	ls.Policy = LRateSchedWarmup
+	ls.Warmup = 4
+	scheds := map[int]float32{0: 0.25, 1: 0.5, 3: 1, 100: 1}
+	for epc, sched := range scheds {
+		assert.Equal(t, sched, ls.Sched(&st, epc, 0), "epoch: %d", epc)
+	}
+
+	ls.Policy = LRateSchedStep
+	ls.Warmup = 0
+	ls.Step = 10
+	scheds = map[int]float32{0: 1, 9: 1, 10: 0.5, 25: 0.25, 1000: 0.01}
+	for epc, sched := range scheds {
+		assert.Equal(t, sched, ls.Sched(&st, epc, 0), "epoch: %d", epc)
+	}
+
+	ls.Policy = LRateSchedCosine
+	ls.Period = 100
+	ls.Min = 0
+	scheds = map[int]float32{0: 1, 50: 0.5, 100: 0, 200: 0}
+	for epc, sched := range scheds {
+		assert.InDelta(t, sched, ls.Sched(&st, epc, 0), 1.0e-6, "epoch: %d", epc)
+	}
+
+	ls.Policy = LRateSchedPlateau
+	ls.Patience = 3
+	ls.Min = 0.01
+	assert.Equal(t, float32(1), ls.Sched(&st, 0, 0)) // no prior epoch error
+	assert.Equal(t, int32(0), st.NBad)
+	// errs are for each completed epoch, used for the following epoch
+	errs := []float32{1, 0.8, 0.6, 0.6, 0.6, 0.595, 0.6, 0.4, 0.4, 0.4, 0.4}
+	scheds1 := []float32{1, 1, 1, 1, 1, 0.5, 0.5, 0.5, 0.5, 0.5, 0.25}
+	for epc, err := range errs {
+		assert.Equal(t, scheds1[epc], ls.Sched(&st, epc+1, err), "epoch: %d", epc+1)
+	}
+}
+
+// newLRateSchedTestNet returns an Input -> Hidden network with the
+// learning rate schedule set by given params function before InitWts.
+func newLRateSchedTestNet(t *testing.T, params func(net *Network)) *Network {
+	net, _ := newTestNetLayers(t, 1, func(net *Network) {
+		inp := net.AddLayer2D("Input", 2, 2, InputLayer)
+		hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+		net.ConnectLayers(inp, hid, prjn.NewFull(), ForwardPrjn)
+	}, params)
+	return net
+}
+
+func newPlateauTestNet(t *testing.T) *Network {
+	return newLRateSchedTestNet(t, func(net *Network) {
+		ls := LRateSchedParams{}
+		ls.Defaults()
+		ls.Policy = LRateSchedPlateau
+		ls.Patience = 2
+		assert.Equal(t, 1, net.SetPrjnLRateSched("#InputToHidden", &ls))
+		assert.Equal(t, 0, net.SetPrjnLRateSched(".BackPrjn", &ls))
+	})
+}
+
+func TestLRateSchedWts(t *testing.T) {
+	net := newPlateauTestNet(t)
+	pj := net.AxonLayerByName("Hidden").RcvPrjns[0]
+	for epc, err := range []float32{0.5, 0.4, 0.4, 0.4, 0.4} {
+		net.LRateSchedEpoch(epc+1, err)
+	}
+	assert.Equal(t, float32(0.5), pj.Params.Learn.LRate.Sched)
+	assert.Equal(t, int32(1), pj.LRateSchedState.NBad)
+	assert.InDelta(t, 0.5*pj.Params.Learn.LRate.Base, pj.Params.Learn.LRate.Eff, 1.0e-6)
+
+	var buf bytes.Buffer
+	assert.NoError(t, net.WriteWtsJSON(&buf))
+	assert.Contains(t, buf.String(), "LRateSchedMult")
+	st := pj.LRateSchedState
+
+	net2 := newPlateauTestNet(t)
+	pj2 := net2.AxonLayerByName("Hidden").RcvPrjns[0]
+	assert.Equal(t, float32(1), pj2.Params.Learn.LRate.Sched)
+	assert.NoError(t, net2.ReadWtsJSON(&buf))
+	assert.Equal(t, st, pj2.LRateSchedState)
+	assert.Equal(t, float32(0.5), pj2.Params.Learn.LRate.Sched)
+
+	// continues from the saved state: one more bad epoch reduces again
+	net2.LRateSchedEpoch(6, 0.4)
+	assert.Equal(t, float32(0.25), pj2.Params.Learn.LRate.Sched)
+}
+
+// TestLooperLRateSched checks that LooperLRateSched applies the schedule
+// value for each epoch during that epoch, starting from InitWts.
+func TestLooperLRateSched(t *testing.T) {
+	net := newLRateSchedTestNet(t, func(net *Network) {
+		ls := LRateSchedParams{}
+		ls.Defaults()
+		ls.Policy = LRateSchedStep
+		ls.Warmup = 2
+		ls.Step = 2
+		net.SetPrjnLRateSched("Prjn", &ls)
+	})
+	pj := net.AxonLayerByName("Hidden").RcvPrjns[0]
+
+	man := looper.NewManager()
+	man.AddStack(etime.Train).AddTime(etime.Epoch, 7).AddTime(etime.Trial, 2)
+	LooperLRateSched(man, net, func() float32 { return 0 })
+	var scheds []float32
+	man.GetLoop(etime.Train, etime.Trial).OnStart.Add("Record", func() {
+		scheds = append(scheds, pj.Params.Learn.LRate.Sched)
+	})
+	man.Run(etime.Train)
+
+	// warmup over epochs 0, 1, then 1 for 2 epochs, halving every 2
+	exp := []float32{0.5, 0.5, 1, 1, 1, 1, 1, 1, 0.5, 0.5, 0.5, 0.5, 0.25, 0.25}
+	assert.Equal(t, exp, scheds)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/c2h5oh/datasize"
 	"github.com/emer/emergent/emer"
+	"github.com/emer/emergent/params"
 	"github.com/emer/emergent/prjn"
 	"github.com/emer/etable/etensor"
 	"github.com/goki/ki/ki"
@@ -32,6 +33,7 @@ func (nt *Network) InitName(net emer.Network, name string) {
 	nt.EmerNet = net
 	nt.Nm = name
 	nt.MaxData = 1
+	nt.UseGPUOrder = true
 	nt.NetIdx = uint32(len(Networks))
 	Networks = append(Networks, nt)
 	TheNetwork = nt
@@ -117,23 +119,34 @@ func (nt *Network) Cycle(ctx *Context) {
 	if ctx.Testing.IsFalse() {
 		nt.NeuronMapPar(ctx, func(ly *Layer, ni uint32) { ly.SynCa(ctx, ni) }, "SynCa")
 	}
-	var ldt, vta *Layer
+	var ldt, vta, lc, drn *Layer
 	for _, ly := range nt.Layers {
-		if ly.LayerType() == VTALayer {
+		switch ly.LayerType() {
+		case VTALayer:
 			vta = ly
-		} else if ly.LayerType() == LDTLayer {
+		case LDTLayer:
 			ldt = ly
-		} else {
+		case LCLayer:
+			lc = ly
+		case DRNLayer:
+			drn = ly
+		default:
 			ly.CyclePost(ctx)
 		}
 	}
-	// ordering of these is important
+	// ordering of these is important: LC depends on DA from VTA
 	if ldt != nil {
 		ldt.CyclePost(ctx)
 	}
 	if vta != nil {
 		vta.CyclePost(ctx)
 	}
+	if lc != nil {
+		lc.CyclePost(ctx)
+	}
+	if drn != nil {
+		drn.CyclePost(ctx)
+	}
 }
 
 // MinusPhase does updating after end of minus phase
@@ -288,8 +301,13 @@ func (nt *Network) SlowAdapt(ctx *Context) {
 //  Init methods
 
 // InitWts initializes synaptic weights and all other associated long-term state variables
-// including running-average state values (e.g., layer running average activations etc)
+// including running-average state values (e.g., layer running average activations etc).
+// First seeds the network RandCtr from the network Rand, so that the random initial
+// values (e.g., RandFunSWtInit) are determined by the network random seed
+// (as set by SetRndSeed or directly on Rand), independent of NData and threading.
+// The Context RandCtr used for the per-cycle random numbers is not affected.
 func (nt *Network) InitWts(ctx *Context) {
+	nt.RandCtr.Seed(nt.Rand.Uint32(-1))
 	for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
 		nt.PVLV.Reset(ctx, di)
 	}
@@ -312,6 +330,7 @@ func (nt *Network) InitWts(ctx *Context) {
 	}
 	// dur := time.Now().Sub(st)
 	// fmt.Printf("sym: %v\n", dur)
+	nt.LRateSchedEpoch(0, 0)
 	nt.GPU.SyncAllToGPU()
 	nt.GPU.SyncSynCaToGPU() // only time we call this
 	nt.GPU.SyncGBufToGPU()
@@ -444,10 +463,14 @@ func (nt *Network) InitExt(ctx *Context) {
 // that were set in prior layer-specific ApplyExt calls.
 // This does nothing on the CPU, but is critical for the GPU,
 // and should be added to all sims where GPU will be used.
+// Any ApplyExtsFuncs are called first, on both CPU and GPU, so they can
+// add to the layer Exts inputs that are then sent to the GPU.
 func (nt *Network) ApplyExts(ctx *Context) {
+	for _, fun := range nt.ApplyExtsFuncs {
+		fun(ctx)
+	}
 	if nt.GPU.On {
 		nt.GPU.RunApplyExts()
-		return
 	}
 }
 
@@ -463,6 +486,37 @@ func (nt *Network) UpdateExtFlags(ctx *Context) {
 	}
 }
 
+// InitTDPredTrace resets the TD(lambda) eligibility trace for all TDPredPrjn
+// projections with RLPred.Lambda > 0, for given data indexes, or all data
+// indexes if none are given -- call at the start of each episode
+// (e.g., the first event of a sequence), so that the trace does not carry
+// over from the end of the prior episode.  Syncs the SynCa state with
+// the GPU once if on, so all data indexes should be reset in one call.
+func (nt *Network) InitTDPredTrace(ctx *Context, dis ...uint32) {
+	var pjs []*Prjn
+	for _, pj := range nt.Prjns {
+		if pj.IsOff() || !pj.Params.DoTDPredTrace() {
+			continue
+		}
+		pjs = append(pjs, pj)
+	}
+	if len(pjs) == 0 {
+		return
+	}
+	if len(dis) == 0 {
+		for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
+			dis = append(dis, di)
+		}
+	}
+	nt.GPU.SyncSynCaFmGPU()
+	for _, pj := range pjs {
+		for _, di := range dis {
+			pj.InitTDPredTrace(ctx, di)
+		}
+	}
+	nt.GPU.SyncSynCaToGPU()
+}
+
 // SynFail updates synaptic failure
 func (nt *Network) SynFail(ctx *Context) {
 	nt.PrjnMapSeq(func(pj *Prjn) { pj.SynFail(ctx) }, "SynFail")
@@ -492,6 +546,47 @@ func (nt *Network) LRateSched(sched float32) {
 	}
 }
 
+// SetPrjnLRateSched sets the LRateSchedule learning rate schedule for all
+// projections matching given param Sel selector: ".Class" for a projection
+// class (including the type, e.g., ".BackPrjn"), "#Name" for a projection
+// name (e.g., "#InputToHidden"), or "Prjn" for all projections.
+// Returns the number of projections set.  Call after Defaults, which
+// resets the schedules, and before InitWts, which applies the schedule
+// for epoch 0.
+func (nt *Network) SetPrjnLRateSched(sel string, ls *LRateSchedParams) int {
+	n := 0
+	for _, ly := range nt.Layers {
+		for _, pj := range ly.RcvPrjns {
+			if !params.SelMatch(sel, pj.Name(), pj.Class(), pj.TypeName(), "Prjn") {
+				continue
+			}
+			pj.LRateSchedule = *ls
+			n++
+		}
+	}
+	return n
+}
+
+// LRateSchedEpoch sets the schedule-based learning rate multiplier for all
+// projections according to their LRateSchedule, for use during given
+// epoch (0 based), using given error measure (e.g., PctErr) computed over
+// the previous epoch for the Plateau policy.  It is called for epoch 0
+// in InitWts, and at the end of each training epoch for the next epoch
+// -- see LooperLRateSched.
+func (nt *Network) LRateSchedEpoch(epoch int, err float32) {
+	updt := false
+	for _, ly := range nt.Layers {
+		for _, pj := range ly.RcvPrjns {
+			if pj.LRateSchedEpoch(epoch, err) {
+				updt = true
+			}
+		}
+	}
+	if updt {
+		nt.GPU.SyncParamsToGPU()
+	}
+}
+
 // SetSubMean sets the SubMean parameters in all the layers in the network
 // trgAvg is for Learn.TrgAvgAct.SubMean
 // prjn is for the prjns Learn.Trace.SubMean
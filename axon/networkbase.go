@@ -30,6 +30,7 @@ import (
 	"github.com/emer/emergent/timer"
 	"github.com/emer/emergent/weights"
 	"github.com/goki/gi/gi"
+	"github.com/goki/gosl/slrand"
 	"github.com/goki/ki/indent"
 	"github.com/goki/kigen/dedupe"
 	"github.com/goki/mat32"
@@ -66,8 +67,11 @@ type NetworkBase struct {
 	// optional metadata that is saved in network weights files -- e.g., can indicate number of epochs that were trained, or any other information about this network that would be useful to save
 	MetaData map[string]string `desc:"optional metadata that is saved in network weights files -- e.g., can indicate number of epochs that were trained, or any other information about this network that would be useful to save"`
 
-	// if true, the neuron and synapse variables will be organized into a gpu-optimized memory order, otherwise cpu-optimized. This must be set before network Build() is called.
-	UseGPUOrder bool `inactive:"+" desc:"if true, the neuron and synapse variables will be organized into a gpu-optimized memory order, otherwise cpu-optimized. This must be set before network Build() is called."`
+	// [view: -] functions called at the start of ApplyExts, before the Exts external inputs are sent to the GPU -- e.g., BGActionSelector re-applies its exploration noise here, so it does not depend on the order of calls relative to InitExt
+	ApplyExtsFuncs []func(ctx *Context) `view:"-" desc:"functions called at the start of ApplyExts, before the Exts external inputs are sent to the GPU -- e.g., BGActionSelector re-applies its exploration noise here, so it does not depend on the order of calls relative to InitExt"`
+
+	// if true, the neuron and synapse variables will be organized into a gpu-optimized memory order, otherwise cpu-optimized. This must be set before network Build() is called.  Defaults to true (set by InitName).
+	UseGPUOrder bool `inactive:"+" desc:"if true, the neuron and synapse variables will be organized into a gpu-optimized memory order, otherwise cpu-optimized. This must be set before network Build() is called.  Defaults to true (set by InitName)."`
 
 	// [view: -] network index in global Networks list of networks -- needed for GPU shader kernel compatible network variable access functions (e.g., NrnV, SynV etc) in CPU mode
 	NetIdx uint32 `view:"-" desc:"network index in global Networks list of networks -- needed for GPU shader kernel compatible network variable access functions (e.g., NrnV, SynV etc) in CPU mode"`
@@ -150,6 +154,15 @@ type NetworkBase struct {
 	// [view: -] random number generator for the network -- all random calls must use this -- set seed here for weight initialization values
 	Rand erand.SysRand `view:"-" desc:"random number generator for the network -- all random calls must use this -- set seed here for weight initialization values"`
 
+	// [view: -] random counter for the random streams that are only used on the CPU outside of the per-cycle updates (e.g., RandFunLesion) -- seeded from Rand in InitWts, so the values are determined by the random seed, independent of how many random numbers are drawn afterward
+	RandCtr slrand.Counter `view:"-" desc:"random counter for the random streams that are only used on the CPU outside of the per-cycle updates (e.g., RandFunLesion) -- seeded from Rand in InitWts, so the values are determined by the random seed, independent of how many random numbers are drawn afterward"`
+
+	// record of all the lesions applied to the network, which can be reversed and saved alongside the weights -- see Layer.LesionNeuronIdxs, Prjn.LesionSynapses, etc
+	Lesions LesionLog `desc:"record of all the lesions applied to the network, which can be reversed and saved alongside the weights -- see Layer.LesionNeuronIdxs, Prjn.LesionSynapses, etc"`
+
+	// [view: -] configurable sequence of phases within each trial, if set by LooperPhaseSeq -- weight changes are then computed at the end of each plus phase instead of at the end of the trial
+	PhaseSeq *PhaseSeq `view:"-" json:"-" xml:"-" desc:"configurable sequence of phases within each trial, if set by LooperPhaseSeq -- weight changes are then computed at the end of each plus phase instead of at the end of the trial"`
+
 	// random seed to be set at the start of configuring the network and initializing the weights -- set this to get a different set of weights
 	RndSeed int64 `inactive:"+" desc:"random seed to be set at the start of configuring the network and initializing the weights -- set this to get a different set of weights"`
 
@@ -756,7 +769,6 @@ func (nt *NetworkBase) SetMaxData(simCtx *Context, maxData int) {
 // Configures the given Context object used in the simulation with the memory
 // access strides for this network -- must be set properly -- see SetCtxStrides.
 func (nt *NetworkBase) Build(simCtx *Context) error {
-	nt.UseGPUOrder = true // todo: set externally
 	if nt.PVLV.NPosUSs == 0 {
 		nt.PVLV.SetNUSs(simCtx, 1, 1)
 	}
@@ -1058,6 +1070,9 @@ func (nt *NetworkBase) BuildPrjnGBuf() {
 // BuildGlobals builds Globals vars, using params set in given context
 func (nt *NetworkBase) BuildGlobals(ctx *Context) {
 	nt.Globals = make([]float32, ctx.GlobalVNFloats())
+	for di := uint32(0); di < ctx.NetIdxs.MaxData; di++ {
+		nt.Globals[ctx.GlobalIdx(di, GvLearnWt)] = 1 // default learning weight
+	}
 }
 
 // DeleteAll deletes all layers, prepares network for re-configuring and building
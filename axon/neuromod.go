@@ -89,6 +89,18 @@ type NeuroModParams struct {
 	// [def: 1] [min: 0] multiplicative gain factor applied to negative dopamine signals -- this operates on the raw dopamine signal prior to any effect of D2 receptors in reversing its sign! should be small for acq, but roughly equal to burst for ext
 	DipGain float32 `min:"0" def:"1" desc:"multiplicative gain factor applied to negative dopamine signals -- this operates on the raw dopamine signal prior to any effect of D2 receptors in reversing its sign! should be small for acq, but roughly equal to burst for ext"`
 
+	// [def: 0] [min: 0] multiplicative gain on excitatory conductance Ge from NE (norepinephrine) arousal level -- resulting gain factor is: 1 + NEGain * NE -- higher arousal increases overall excitability
+	NEGain float32 `min:"0" def:"0" desc:"multiplicative gain on excitatory conductance Ge from NE (norepinephrine) arousal level -- resulting gain factor is: 1 + NEGain * NE -- higher arousal increases overall excitability"`
+
+	// [min: 0] [max: 1] proportion of maximum learning rate that NE can modulate -- e.g., if 0.2, then NE = 0 = 80% of std learning rate, 1 = 100%
+	NELRateMod float32 `min:"0" max:"1" desc:"proportion of maximum learning rate that NE can modulate -- e.g., if 0.2, then NE = 0 = 80% of std learning rate, 1 = 100%"`
+
+	// [min: 0] [max: 1] proportion of maximum learning rate that 5-HT (serotonin) can modulate -- e.g., if 0.2, then Ser = 0 = 80% of std learning rate, 1 = 100%
+	SerLRateMod float32 `min:"0" max:"1" desc:"proportion of maximum learning rate that 5-HT (serotonin) can modulate -- e.g., if 0.2, then Ser = 0 = 80% of std learning rate, 1 = 100%"`
+
+	// [def: 0] [min: 0] amount of extra Gi inhibition added in proportion to 5-HT (serotonin) level -- supports behavioral inhibition / patience, e.g., in action selection layers
+	SerInhib float32 `min:"0" def:"0" desc:"amount of extra Gi inhibition added in proportion to 5-HT (serotonin) level -- supports behavioral inhibition / patience, e.g., in action selection layers"`
+
 	pad, pad1, pad2 float32
 }
 
@@ -97,6 +109,10 @@ func (nm *NeuroModParams) Defaults() {
 	nm.DAModGain = 0.5
 	nm.DALRateMod = 0
 	nm.AChLRateMod = 0
+	nm.NEGain = 0
+	nm.NELRateMod = 0
+	nm.SerLRateMod = 0
+	nm.SerInhib = 0
 	nm.BurstGain = 1
 	nm.DipGain = 1
 }
@@ -104,6 +120,8 @@ func (nm *NeuroModParams) Defaults() {
 func (nm *NeuroModParams) Update() {
 	nm.DALRateMod = mat32.Clamp(nm.DALRateMod, 0, 1)
 	nm.AChLRateMod = mat32.Clamp(nm.AChLRateMod, 0, 1)
+	nm.NELRateMod = mat32.Clamp(nm.NELRateMod, 0, 1)
+	nm.SerLRateMod = mat32.Clamp(nm.SerLRateMod, 0, 1)
 }
 
 // IsBLAExt returns true if this is Positive, D2 or Negative D1 -- BLA extinction
@@ -137,11 +155,13 @@ func (nm *NeuroModParams) DASign() float32 {
 }
 
 // LRMod returns overall learning rate modulation factor due to neuromodulation
-// from given dopamine (DA) and ACh inputs.
+// from given dopamine (DA), ACh, NE and Ser (5-HT) inputs.
 // If DALRateMod is true and DAMod == D1Mod or D2Mod, then the sign is a function
 // of the DA
-func (nm *NeuroModParams) LRMod(da, ach float32) float32 {
+func (nm *NeuroModParams) LRMod(da, ach, ne, ser float32) float32 {
 	mod := nm.LRModFact(nm.AChLRateMod, ach)
+	mod *= nm.LRModFact(nm.NELRateMod, ne)
+	mod *= nm.LRModFact(nm.SerLRateMod, ser)
 	if nm.DALRateSign.IsTrue() {
 		mod *= nm.DAGain(da) * nm.DASign()
 	} else {
@@ -185,4 +205,16 @@ func (nm *NeuroModParams) GiFmACh(ach float32) float32 {
 	return nm.AChDisInhib * ai
 }
 
+// GeGainFmNE returns the multiplicative gain factor on Ge from
+// NE (norepinephrine) arousal level: 1 + NEGain * NE.
+func (nm *NeuroModParams) GeGainFmNE(ne float32) float32 {
+	return 1 + nm.NEGain*ne
+}
+
+// GiFmSer returns amount of extra inhibition to add based on
+// 5-HT (serotonin) level, supporting patience / behavioral inhibition.
+func (nm *NeuroModParams) GiFmSer(ser float32) float32 {
+	return nm.SerInhib * ser
+}
+
 //gosl: end neuromod
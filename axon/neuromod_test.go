@@ -0,0 +1,113 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"os"
+	"testing"
+
+	"github.com/emer/emergent/prjn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeuroModNESer(t *testing.T) {
+	nm := NeuroModParams{}
+	nm.Defaults()
+	assert.Equal(t, float32(1), nm.LRMod(0, 0, 0, 0))
+	assert.Equal(t, float32(1), nm.GeGainFmNE(1))
+	assert.Equal(t, float32(0), nm.GiFmSer(1))
+
+	nm.NELRateMod = 0.2
+	nm.SerLRateMod = 0.5
+	nm.NEGain = 0.5
+	nm.SerInhib = 2
+	assert.InDelta(t, 0.8*0.5, nm.LRMod(0, 0, 0, 0), 1.0e-6)
+	assert.InDelta(t, 0.5, nm.LRMod(0, 0, 1, 0), 1.0e-6)
+	assert.InDelta(t, 1, nm.LRMod(0, 0, 1, 1), 1.0e-6)
+	assert.Equal(t, float32(1.25), nm.GeGainFmNE(0.5))
+	assert.Equal(t, float32(1), nm.GiFmSer(0.5))
+}
+
+// TestLCDRNLayers checks that LC and DRN layers compute NE and 5-HT
+// separately for each data index, from Global values and source layer.
+func TestLCDRNLayers(t *testing.T) {
+	net, ctx, lc, drn := newLCDRNTestNet(t)
+	for cyc := 0; cyc < 200; cyc++ {
+		net.Cycle(ctx)
+		ctx.CycleInc()
+		if cyc == 0 {
+			assert.InDelta(t, 0.5, GlbV(ctx, 0, GvNE), 1.0e-6) // instant up
+			assert.Equal(t, float32(0), GlbV(ctx, 1, GvNE))
+			assert.Less(t, GlbV(ctx, 0, GvSer), float32(0.1)) // slow integration
+		}
+	}
+	assert.InDelta(t, 0.5, GlbV(ctx, 0, GvNE), 1.0e-6)
+	assert.Equal(t, float32(0), GlbV(ctx, 1, GvNE))
+	assert.InDelta(t, 0.6, GlbV(ctx, 0, GvSer), 0.02)
+	assert.InDelta(t, 0.5*0.4, GlbV(ctx, 1, GvSer), 0.02)
+	assert.Equal(t, GlbV(ctx, 0, GvNERaw), NrnV(ctx, lc.NeurStIdx, 0, Act))
+	assert.Equal(t, GlbV(ctx, 1, GvSerRaw), NrnV(ctx, drn.NeurStIdx, 1, Act))
+
+	// NE decays when the drive goes away
+	SetGlbV(ctx, 0, GvUrgency, 0)
+	for cyc := 0; cyc < 5; cyc++ {
+		net.Cycle(ctx)
+		ctx.CycleInc()
+	}
+	assert.Less(t, GlbV(ctx, 0, GvNE), float32(0.5))
+	assert.Greater(t, GlbV(ctx, 0, GvNE), float32(0.2))
+}
+
+// TestGPULCDRNLayers checks that the GPU CyclePost kernel computes the
+// same NE and 5-HT values as the CPU for LC and DRN layers.
+func TestGPULCDRNLayers(t *testing.T) {
+	if os.Getenv("TEST_GPU") != "true" {
+		t.Skip("Set TEST_GPU env var to run GPU tests")
+	}
+	cpuNet, cpuCtx, _, _ := newLCDRNTestNet(t)
+	gpuNet, gpuCtx, _, _ := newLCDRNTestNet(t)
+	gpuNet.ConfigGPUnoGUI(gpuCtx)
+	defer gpuNet.GPU.Destroy()
+	gpuNet.GPU.CycleByCycle = true
+	gpuNet.GPU.SyncContextToGPU()
+
+	vars := []GlobalVars{GvNE, GvNERaw, GvSer, GvSerRaw}
+	for cyc := 0; cyc < 50; cyc++ {
+		cpuNet.Cycle(cpuCtx)
+		cpuCtx.CycleInc()
+		gpuNet.Cycle(gpuCtx)
+		gpuCtx.CycleInc()
+		for di := uint32(0); di < 2; di++ {
+			for _, gv := range vars {
+				assert.InDelta(t, GlbV(cpuCtx, di, gv), GlbV(gpuCtx, di, gv), 1.0e-6, "cyc: %d di: %d %s", cyc, di, gv)
+			}
+		}
+	}
+}
+
+// newLCDRNTestNet returns a network with an LC layer driven by Hidden
+// and a DRN layer with no source layer, with 2 data indexes whose
+// Global drives differ.
+func newLCDRNTestNet(t *testing.T) (*Network, *Context, *Layer, *Layer) {
+	ctx := NewContext()
+	net := NewNetwork("NeuroModTest")
+	net.SetMaxData(ctx, 2)
+	inp := net.AddLayer2D("Input", 2, 2, InputLayer)
+	hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+	net.ConnectLayers(inp, hid, prjn.NewFull(), ForwardPrjn)
+	lc := net.AddLCLayer("", hid)
+	drn := net.AddDRNLayer("", nil)
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	assert.Equal(t, int32(hid.Index()), lc.Params.LC.SrcLayIdx)
+	assert.Equal(t, int32(-1), drn.Params.DRN.SrcLayIdx)
+	net.InitWts(ctx)
+
+	net.NewState(ctx)
+	SetGlbV(ctx, 0, GvUrgency, 0.5)
+	SetGlbV(ctx, 0, GvPVposEst, 0.6)
+	SetGlbV(ctx, 1, GvPVneg, 0.4)
+	return net, ctx, lc, drn
+}
@@ -339,6 +339,21 @@ const (
 	// and are writable (indexes are read only).
 	NrnFlags
 
+	// SRPrv is the successor representation prediction from the prior trial (ActP at end of previous plus phase), for SRPredLayer.
+	SRPrv
+
+	// SRErr is the successor representation TD error: state + Discount * ActP - SRPrv, computed at end of plus phase for SRPredLayer, driving SRPrjn learning.
+	SRErr
+
+	// GiTopo is topographic inhibition from the gaussian-weighted neighborhood of neurons or pools, computed if Inhib.Topo.On
+	GiTopo
+
+	// GeNoiseOU is Ornstein-Uhlenbeck excitatory noise conductance, computed if Acts.OUNoise.On, added into Ge
+	GeNoiseOU
+
+	// GiNoiseOU is Ornstein-Uhlenbeck inhibitory noise conductance, computed if Acts.OUNoise.On, added into Gi
+	GiNoiseOU
+
 	NeuronVarsN
 )
 
@@ -555,10 +570,12 @@ var NeuronVarProps = map[string]string{
 	/////////////////////////////////////////
 	// Noise
 
-	"GeNoiseP": `desc:"accumulating poisson probability factor for driving excitatory noise spiking -- multiply times uniform random deviate at each time step, until it gets below the target threshold based on lambda."`,
-	"GeNoise":  `desc:"integrated noise excitatory conductance, added into Ge"`,
-	"GiNoiseP": `desc:"accumulating poisson probability factor for driving inhibitory noise spiking -- multiply times uniform random deviate at each time step, until it gets below the target threshold based on lambda."`,
-	"GiNoise":  `desc:"integrated noise inhibotyr conductance, added into Gi"`,
+	"GeNoiseP":  `desc:"accumulating poisson probability factor for driving excitatory noise spiking -- multiply times uniform random deviate at each time step, until it gets below the target threshold based on lambda."`,
+	"GeNoise":   `desc:"integrated noise excitatory conductance, added into Ge"`,
+	"GiNoiseP":  `desc:"accumulating poisson probability factor for driving inhibitory noise spiking -- multiply times uniform random deviate at each time step, until it gets below the target threshold based on lambda."`,
+	"GiNoise":   `desc:"integrated noise inhibotyr conductance, added into Gi"`,
+	"GeNoiseOU": `auto-scale:"+" desc:"Ornstein-Uhlenbeck excitatory noise conductance, computed if Acts.OUNoise.On, added into Ge"`,
+	"GiNoiseOU": `auto-scale:"+" desc:"Ornstein-Uhlenbeck inhibitory noise conductance, computed if Acts.OUNoise.On, added into Gi"`,
 
 	/////////////////////////////////////////
 	// Ge, Gi integration
@@ -581,6 +598,7 @@ var NeuronVarProps = map[string]string{
 
 	"SSGi":     `auto-scale:"+" desc:"SST+ somatostatin positive slow spiking inhibition"`,
 	"SSGiDend": `auto-scale:"+" desc:"amount of SST+ somatostatin positive slow spiking inhibition applied to dendritic Vm (VmDend)"`,
+	"GiTopo":   `auto-scale:"+" desc:"topographic inhibition from the gaussian-weighted neighborhood of neurons or pools, computed if Inhib.Topo.On"`,
 	"Gak":      `auto-scale:"+" desc:"conductance of A-type K potassium channels"`,
 
 	/////////////////////////////////////////
@@ -633,6 +651,8 @@ var NeuronVarProps = map[string]string{
 	"CtxtGe":     `desc:"context (temporally delayed) excitatory conductance, driven by deep bursting at end of the plus phase, for CT layers."`,
 	"CtxtGeRawa": `desc:"raw update of context (temporally delayed) excitatory conductance, driven by deep bursting at end of the plus phase, for CT layers."`,
 	"CtxtGeOrig": `desc:"original CtxtGe value prior to any decay factor -- updates at end of plus phase."`,
+	"SRPrv":      `desc:"successor representation prediction from the prior trial (ActP at end of previous plus phase), for SRPredLayer."`,
+	"SRErr":      `auto-scale:"+" desc:"successor representation TD error: state + Discount * ActP - SRPrv, computed at end of plus phase for SRPredLayer, driving SRPrjn learning."`,
 
 	"NrnFlags": `view:"-" desc:"bit flags for external input and other neuron status state"`,
 
@@ -93,12 +93,17 @@ func _() {
 	_ = x[CtxtGeRaw-77]
 	_ = x[CtxtGeOrig-78]
 	_ = x[NrnFlags-79]
-	_ = x[NeuronVarsN-80]
+	_ = x[SRPrv-80]
+	_ = x[SRErr-81]
+	_ = x[GiTopo-82]
+	_ = x[GeNoiseOU-83]
+	_ = x[GiNoiseOU-84]
+	_ = x[NeuronVarsN-85]
 }
 
-const _NeuronVars_name = "SpikeSpikedActActIntActMActPExtTargetGeGiGkInetVmVmDendISIISIAvgCaSpkPCaSpkDCaSynCaSpkMCaSpkPMCaLrnNrnCaMNrnCaPNrnCaDCaDiffAttnRLRateSpkMaxCaSpkMaxSpkPrvSpkSt1SpkSt2GeNoisePGeNoiseGiNoisePGiNoiseGeExtGeRawGeSynGiRawGiSynGeIntGeIntNormGiIntGModRawGModSynGMaintRawGMaintSynSSGiSSGiDendGakMahpNSahpCaSahpNGknaMedGknaSlowGnmdaSynGnmdaGnmdaMaintGnmdaLrnNmdaCaGgabaBGABABGABABxGvgccVgccMVgccHVgccCaVgccCaIntSKCaInSKCaRSKCaMGskBurstBurstPrvCtxtGeCtxtGeRawCtxtGeOrigNrnFlagsNeuronVarsN"
+const _NeuronVars_name = "SpikeSpikedActActIntActMActPExtTargetGeGiGkInetVmVmDendISIISIAvgCaSpkPCaSpkDCaSynCaSpkMCaSpkPMCaLrnNrnCaMNrnCaPNrnCaDCaDiffAttnRLRateSpkMaxCaSpkMaxSpkPrvSpkSt1SpkSt2GeNoisePGeNoiseGiNoisePGiNoiseGeExtGeRawGeSynGiRawGiSynGeIntGeIntNormGiIntGModRawGModSynGMaintRawGMaintSynSSGiSSGiDendGakMahpNSahpCaSahpNGknaMedGknaSlowGnmdaSynGnmdaGnmdaMaintGnmdaLrnNmdaCaGgabaBGABABGABABxGvgccVgccMVgccHVgccCaVgccCaIntSKCaInSKCaRSKCaMGskBurstBurstPrvCtxtGeCtxtGeRawCtxtGeOrigNrnFlagsSRPrvSRErrGiTopoGeNoiseOUGiNoiseOUNeuronVarsN"
 
-var _NeuronVars_index = [...]uint16{0, 5, 11, 14, 20, 24, 28, 31, 37, 39, 41, 43, 47, 49, 55, 58, 64, 70, 76, 81, 87, 94, 99, 105, 111, 117, 123, 127, 133, 141, 147, 153, 159, 165, 173, 180, 188, 195, 200, 205, 210, 215, 220, 225, 234, 239, 246, 253, 262, 271, 275, 283, 286, 291, 297, 302, 309, 317, 325, 330, 340, 348, 354, 360, 365, 371, 376, 381, 386, 392, 401, 407, 412, 417, 420, 425, 433, 439, 448, 458, 466, 477}
+var _NeuronVars_index = [...]uint16{0, 5, 11, 14, 20, 24, 28, 31, 37, 39, 41, 43, 47, 49, 55, 58, 64, 70, 76, 81, 87, 94, 99, 105, 111, 117, 123, 127, 133, 141, 147, 153, 159, 165, 173, 180, 188, 195, 200, 205, 210, 215, 220, 225, 234, 239, 246, 253, 262, 271, 275, 283, 286, 291, 297, 302, 309, 317, 325, 330, 340, 348, 354, 360, 365, 371, 376, 381, 386, 392, 401, 407, 412, 417, 420, 425, 433, 439, 448, 458, 466, 471, 476, 482, 491, 500, 511}
 
 func (i NeuronVars) String() string {
 	if i < 0 || i >= NeuronVars(len(_NeuronVars_index)-1) {
@@ -198,7 +203,12 @@ var _NeuronVars_descMap = map[NeuronVars]string{
 	77: `CtxtGeRaw is raw update of context (temporally delayed) excitatory conductance, driven by deep bursting at end of the plus phase, for CT layers.`,
 	78: `CtxtGeOrig is original CtxtGe value prior to any decay factor -- updates at end of plus phase.`,
 	79: `NrnFlags are bit flags for binary state variables, which are converted to / from uint32. These need to be in Vars because they can be differential per data (for ext inputs) and are writable (indexes are read only).`,
-	80: ``,
+	80: `SRPrv is the successor representation prediction from the prior trial (ActP at end of previous plus phase), for SRPredLayer.`,
+	81: `SRErr is the successor representation TD error: state + Discount * ActP - SRPrv, computed at end of plus phase for SRPredLayer, driving SRPrjn learning.`,
+	82: `GiTopo is topographic inhibition from the gaussian-weighted neighborhood of neurons or pools, computed if Inhib.Topo.On`,
+	83: `GeNoiseOU is Ornstein-Uhlenbeck excitatory noise conductance, computed if Acts.OUNoise.On, added into Ge`,
+	84: `GiNoiseOU is Ornstein-Uhlenbeck inhibitory noise conductance, computed if Acts.OUNoise.On, added into Gi`,
+	85: ``,
 }
 
 func (i NeuronVars) Desc() string {
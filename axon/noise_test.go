@@ -0,0 +1,90 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/prjn"
+	"github.com/goki/mat32"
+	"github.com/stretchr/testify/assert"
+)
+
+// ouNoiseVals runs OU noise for ncyc cycles in a new network with nData,
+// returning GeNoiseOU for each [di][lni][cycle] of the Hidden layer.
+func ouNoiseVals(t *testing.T, nData, ncyc int) [][][]float32 {
+	ctx := NewContext()
+	net := NewNetwork("NoiseTest")
+	net.SetMaxData(ctx, nData)
+	net.SetRndSeed(1)
+	inp := net.AddLayer2D("Input", 2, 2, InputLayer)
+	hid := net.AddLayer4D("Hidden", 1, 2, 4, 4, SuperLayer)
+	net.ConnectLayers(inp, hid, prjn.NewFull(), ForwardPrjn)
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	on := &hid.Params.Acts.OUNoise
+	on.On.SetBool(true)
+	on.Ge = 0.1
+	on.Gi = 0.1
+	on.Corr = 0.5
+	on.Update()
+	net.InitWts(ctx)
+
+	vals := make([][][]float32, nData)
+	for di := range vals {
+		vals[di] = make([][]float32, hid.NNeurons)
+	}
+	for cyc := 0; cyc < ncyc; cyc++ {
+		for di := uint32(0); di < uint32(nData); di++ {
+			for lni := uint32(0); lni < hid.NNeurons; lni++ {
+				ni := hid.NeurStIdx + lni
+				hid.Params.Acts.OUNoiseFmRnd(ctx, ni, di, hid.Params.Idxs.PoolSt/hid.Params.Idxs.MaxData+NrnI(ctx, ni, NrnSubPool))
+				vals[di][lni] = append(vals[di][lni], NrnV(ctx, ni, di, GeNoiseOU))
+			}
+		}
+		ctx.CycleInc()
+	}
+	return vals
+}
+
+func noiseCorr(a, b []float32) float32 {
+	var ma, mb, sab, saa, sbb float32
+	n := float32(len(a))
+	for i := range a {
+		ma += a[i]
+		mb += b[i]
+	}
+	ma /= n
+	mb /= n
+	for i := range a {
+		sab += (a[i] - ma) * (b[i] - mb)
+		saa += (a[i] - ma) * (a[i] - ma)
+		sbb += (b[i] - mb) * (b[i] - mb)
+	}
+	return sab / mat32.Sqrt(saa*sbb)
+}
+
+func TestOUNoise(t *testing.T) {
+	ncyc := 4000
+	vals := ouNoiseVals(t, 2, ncyc)
+	vals1 := ouNoiseVals(t, 1, 100)
+	assert.Equal(t, vals[0][0][:100], vals1[0][0]) // same regardless of NData
+
+	// stationary sigma
+	var ss float32
+	for _, v := range vals[0][0] {
+		ss += v * v
+	}
+	assert.InDelta(t, 0.1, mat32.Sqrt(ss/float32(ncyc)), 0.015)
+
+	// temporal autocorrelation at lag GeTau = Exp(-1)
+	assert.InDelta(t, mat32.Exp(-1), noiseCorr(vals[0][0][:ncyc-3], vals[0][0][3:]), 0.1)
+
+	// correlated within pool, independent across pools and data
+	assert.InDelta(t, 0.5, noiseCorr(vals[0][0], vals[0][1]), 0.1)
+	assert.InDelta(t, 0, noiseCorr(vals[0][0], vals[0][16]), 0.1)
+	assert.InDelta(t, 0, noiseCorr(vals[0][0], vals[1][0]), 0.1)
+	assert.InDelta(t, 0, noiseCorr(vals[0][0], vals[1][1]), 0.1)
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"github.com/goki/mat32"
+)
+
+// BGActionSelector provides a standard readout of the action selected
+// by a PCORE basal ganglia stack (e.g., as created by AddBG, AddBG4D or AddVS),
+// in terms of which Matrix stripe (pool) gated, when it gated, and how
+// confidently, for each data parallel index.
+// Call Select after the PlusPhase, when MatrixGated has updated the
+// Pool.Gated flags, and then read the results via Action or the
+// per-data slices directly.
+// It also supports exploration for RL training, via Explore, which adds
+// softmax-distributed noise to the Ge of the Matrix Go stripes.
+// Init configures the Matrix Go layer for this noise (Acts.Clamp.Add),
+// so it only affects Matrix layers used with a BGActionSelector.
+type BGActionSelector struct {
+
+	// Matrix Go (D1) layer that determines the gating -- the MtxGo layer from AddBG
+	MtxGo *Layer `desc:"Matrix Go (D1) layer that determines the gating -- the MtxGo layer from AddBG"`
+
+	// layer whose LayerVals.RT reaction time is used for the gating time -- defaults to MtxGo, but the BG-gated thalamus (e.g., PFCVM) is typically a better indicator
+	RTLay *Layer `desc:"layer whose LayerVals.RT reaction time is used for the gating time -- defaults to MtxGo, but the BG-gated thalamus (e.g., PFCVM) is typically a better indicator"`
+
+	// maximum amount of excitatory conductance added to Matrix Go stripes by Explore -- the softmax distributes this across stripes
+	ExploreGe float32 `def:"0.1" min:"0" desc:"maximum amount of excitatory conductance added to Matrix Go stripes by Explore -- the softmax distributes this across stripes"`
+
+	// temperature of the softmax over Gaussian random values for each stripe in Explore -- lower values concentrate the noise on a single random stripe, higher values spread it evenly across all stripes
+	ExploreTemp float32 `def:"0.2" min:"0" desc:"temperature of the softmax over Gaussian random values for each stripe in Explore -- lower values concentrate the noise on a single random stripe, higher values spread it evenly across all stripes"`
+
+	// [view: -] chosen stripe (0-based Matrix pool index) for each data index, -1 if no gating
+	Stripe []int `view:"-" desc:"chosen stripe (0-based Matrix pool index) for each data index, -1 if no gating"`
+
+	// [view: -] gating time in cycles for each data index, from the RTLay reaction time, -1 if no gating
+	GateCyc []float32 `view:"-" desc:"gating time in cycles for each data index, from the RTLay reaction time, -1 if no gating"`
+
+	// [view: -] confidence of the chosen stripe for each data index: proportion of total stripe SpkMax activity in the chosen stripe, 0 if no gating
+	Conf []float32 `view:"-" desc:"confidence of the chosen stripe for each data index: proportion of total stripe SpkMax activity in the chosen stripe, 0 if no gating"`
+
+	// [view: -] true if no stripe gated for each data index
+	NoGate []bool `view:"-" desc:"true if no stripe gated for each data index"`
+
+	// [view: -] current exploration noise Ge for each data index and stripe: [MaxData][NStripes]
+	Noise []float32 `view:"-" desc:"current exploration noise Ge for each data index and stripe: [MaxData][NStripes]"`
+
+	// network that ApplyNoise has been added to, in its ApplyExtsFuncs
+	applyNet *Network
+
+	// true if nonzero Noise was applied in the last ApplyNoise
+	noiseOn bool
+}
+
+// NewBGActionSelector returns a new BGActionSelector for given Matrix Go layer,
+// which must have already been built.
+func NewBGActionSelector(mtxGo *Layer) *BGActionSelector {
+	as := &BGActionSelector{}
+	as.Init(mtxGo)
+	return as
+}
+
+// Init initializes the selector for given Matrix Go layer,
+// which must have already been built.
+// It sets the Acts.Clamp.Add and Acts.Clamp.Ge parameters on the layer
+// so that the Explore noise adds to the synaptic input, and adds ApplyNoise
+// to the network ApplyExtsFuncs, so the noise is re-applied to the Exts
+// inputs of the layer by each ApplyExts call, after any InitExt.
+// Call after Defaults and any params have been applied to the network,
+// and before the GPU is configured (or call GPU.SyncParamsToGPU after).
+func (as *BGActionSelector) Init(mtxGo *Layer) {
+	as.MtxGo = mtxGo
+	mtxGo.Params.Acts.Clamp.Add.SetBool(true)
+	mtxGo.Params.Acts.Clamp.Ge = 1
+	nt := mtxGo.Network
+	if as.applyNet != nt {
+		nt.ApplyExtsFuncs = append(nt.ApplyExtsFuncs, as.ApplyNoise)
+		as.applyNet = nt
+	}
+	as.RTLay = mtxGo
+	as.ExploreGe = 0.1
+	as.ExploreTemp = 0.2
+	nd := int(mtxGo.MaxData)
+	as.Stripe = make([]int, nd)
+	as.GateCyc = make([]float32, nd)
+	as.Conf = make([]float32, nd)
+	as.NoGate = make([]bool, nd)
+	as.Noise = make([]float32, nd*as.NStripes())
+	for di := 0; di < nd; di++ {
+		as.Stripe[di] = -1
+		as.GateCyc[di] = -1
+		as.NoGate[di] = true
+	}
+}
+
+// NStripes returns the number of stripes in the Matrix layer:
+// number of pools for a 4D layer, else 1.
+func (as *BGActionSelector) NStripes() int {
+	if !as.MtxGo.Is4D() {
+		return 1
+	}
+	return int(as.MtxGo.NPools) - 1
+}
+
+// Select updates the chosen stripe, gating time, confidence and no-gate
+// signal for each data index, based on the Pool.Gated state set by
+// MatrixGated, and the SpkMax activity of each stripe.
+// Among the gated stripes, the one with the highest SpkMax is chosen.
+// Must be called after PlusPhase (on the CPU, GPU state is already
+// synced by that point).
+func (as *BGActionSelector) Select(ctx *Context) {
+	ly := as.MtxGo
+	for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
+		as.Stripe[di] = -1
+		as.GateCyc[di] = -1
+		as.Conf[di] = 0
+		as.NoGate[di] = !ly.AnyGated(di)
+		if as.NoGate[di] {
+			continue
+		}
+		if !ly.Is4D() {
+			as.Stripe[di] = 0
+			as.Conf[di] = 1
+		} else {
+			sum := float32(0)
+			max := float32(-1)
+			for pi := uint32(1); pi < ly.NPools; pi++ {
+				pl := ly.Pool(pi, di)
+				spk := pl.AvgMax.SpkMax.Cycle.Avg
+				sum += spk
+				if pl.Gated.IsTrue() && spk > max {
+					max = spk
+					as.Stripe[di] = int(pi) - 1
+				}
+			}
+			if sum > 0 && max > 0 {
+				as.Conf[di] = max / sum
+			}
+		}
+		as.GateCyc[di] = as.RTLay.LayerVals(di).RT
+	}
+}
+
+// Action returns the selection results for given data index:
+// chosen stripe (-1 if none), gating time in cycles (-1 if none),
+// confidence, and whether no stripe gated.
+func (as *BGActionSelector) Action(di uint32) (stripe int, gateCyc, conf float32, noGate bool) {
+	return as.Stripe[di], as.GateCyc[di], as.Conf[di], as.NoGate[di]
+}
+
+// Explore samples new exploration noise for each data index, as a softmax
+// with ExploreTemp temperature over Gaussian random values for each stripe,
+// scaled by ExploreGe, and applies it as additional excitatory conductance
+// to the neurons in each Matrix Go stripe, via the Ext input
+// (see Acts.Clamp.Add and Acts.Clamp.Ge, which multiplies the noise,
+// both set by Init).
+// The noise persists until cleared by ClearExplore or a new call to Explore,
+// and is re-applied by Network.ApplyExts, so Explore can be called
+// before or after InitExt.  On the GPU, it takes effect with the
+// next ApplyExts, which sends the Exts inputs.
+// The Gaussian values come from the RandFunExplore counter-based random
+// stream, so they are the same for a given data index regardless of NData,
+// and change with each cycle.
+func (as *BGActionSelector) Explore(ctx *Context) {
+	ns := as.NStripes()
+	for di := 0; di < int(ctx.NetIdxs.NData); di++ {
+		nz := as.Noise[di*ns : (di+1)*ns]
+		sum := float32(0)
+		for si := range nz {
+			nz[si] = mat32.Exp(GetRandomNormal(uint32(di*ns+si), ctx.RandCtr, RandFunExplore) / as.ExploreTemp)
+			sum += nz[si]
+		}
+		for si := range nz {
+			nz[si] *= as.ExploreGe / sum
+		}
+	}
+	as.ApplyNoise(ctx)
+}
+
+// ClearExplore clears any exploration noise applied by Explore.
+func (as *BGActionSelector) ClearExplore(ctx *Context) {
+	for i := range as.Noise {
+		as.Noise[i] = 0
+	}
+	as.ApplyNoise(ctx)
+}
+
+// ApplyNoise applies the current Noise values to the Matrix Go neurons,
+// via the layer Exts inputs as in ApplyExt, which are sent to the GPU
+// by Network.ApplyExts (the Matrix layer type is an IsExt type for this).
+// It is called automatically by Network.ApplyExts (see Init),
+// and does nothing if there is no noise to apply or clear.
+func (as *BGActionSelector) ApplyNoise(ctx *Context) {
+	on := false
+	for _, nz := range as.Noise {
+		if nz > 0 {
+			on = true
+			break
+		}
+	}
+	if !on && !as.noiseOn {
+		return
+	}
+	as.noiseOn = on
+	ly := as.MtxGo
+	clearMask, setMask, toTarg := ly.ApplyExtFlags()
+	ns := as.NStripes()
+	for lni := uint32(0); lni < ly.NNeurons; lni++ {
+		ni := ly.NeurStIdx + lni
+		if NrnIsOff(ctx, ni) {
+			continue
+		}
+		si := 0
+		if ly.Is4D() {
+			si = int(NrnI(ctx, ni, NrnSubPool)) - 1
+		}
+		for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
+			nz := as.Noise[int(di)*ns+si]
+			if nz > 0 {
+				ly.ApplyExtVal(ctx, lni, di, nz, clearMask, setMask, toTarg)
+			} else {
+				ly.Params.InitExt(ctx, ni, di)
+				ly.Exts[ly.Params.Idxs.ExtIdx(lni, di)] = -1
+			}
+		}
+	}
+}
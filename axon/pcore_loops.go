@@ -0,0 +1,176 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"fmt"
+
+	"github.com/emer/emergent/params"
+	"github.com/emer/emergent/prjn"
+	"github.com/emer/emergent/relpos"
+	"github.com/goki/mat32"
+)
+
+// BGLoopsConfig specifies the structure of multiple parallel
+// cortico-BG-thalamic loops created by AddBGLoops, and the
+// convergence among them.
+type BGLoopsConfig struct {
+
+	// name prefix for each loop, which also determines the number of loops -- order from ventral (limbic) to dorsal (motor), which determines the direction of the ThalToNext projections
+	Names []string `desc:"name prefix for each loop, which also determines the number of loops -- order from ventral (limbic) to dorsal (motor), which determines the direction of the ThalToNext projections"`
+
+	// number of pools in Y dimension of the Matrix and gated thalamus layers for each loop
+	NPoolsY int `desc:"number of pools in Y dimension of the Matrix and gated thalamus layers for each loop"`
+
+	// number of pools in X dimension of the Matrix and gated thalamus layers for each loop
+	NPoolsX int `desc:"number of pools in X dimension of the Matrix and gated thalamus layers for each loop"`
+
+	// number of neurons in Y dimension within each pool of the Matrix and gated thalamus layers
+	NNeurY int `desc:"number of neurons in Y dimension within each pool of the Matrix and gated thalamus layers"`
+
+	// number of neurons in X dimension within each pool of the Matrix and gated thalamus layers
+	NNeurX int `desc:"number of neurons in X dimension within each pool of the Matrix and gated thalamus layers"`
+
+	// number of neurons in Y dimension of the GP and STN layers
+	GPNeurY int `desc:"number of neurons in Y dimension of the GP and STN layers"`
+
+	// number of neurons in X dimension of the GP and STN layers
+	GPNeurX int `desc:"number of neurons in X dimension of the GP and STN layers"`
+
+	// use a single STNp and STNs shared across all loops, representing convergent hyperdirect pathway inhibition, which receives from all GPeIn layers and projects to the GP layers of all loops -- otherwise each loop has its own STN layers as in AddBG
+	SharedSTN bool `desc:"use a single STNp and STNs shared across all loops, representing convergent hyperdirect pathway inhibition, which receives from all GPeIn layers and projects to the GP layers of all loops -- otherwise each loop has its own STN layers as in AddBG"`
+
+	// [min: 0] relative strength (PrjnScale.Rel) of cross-loop inhibitory projections from each loop's GPeIn to the GPeIn of all other loops, producing competition among loops -- 0 = no cross-talk
+	GPeCross float32 `min:"0" desc:"relative strength (PrjnScale.Rel) of cross-loop inhibitory projections from each loop's GPeIn to the GPeIn of all other loops, producing competition among loops -- 0 = no cross-talk"`
+
+	// [min: 0] relative strength (PrjnScale.Rel) of the projections from the gated thalamus of each loop to the Matrix Go and No layers of the next loop in Names order, so that gating in a more ventral loop biases gating in the next more dorsal loop.  These are excitatory thalamo-striatal projections, not a striato-nigro-striatal dopamine spiral: dopamine remains a single global signal shared by all loops.  0 = none
+	ThalToNext float32 `min:"0" desc:"relative strength (PrjnScale.Rel) of the projections from the gated thalamus of each loop to the Matrix Go and No layers of the next loop in Names order, so that gating in a more ventral loop biases gating in the next more dorsal loop.  These are excitatory thalamo-striatal projections, not a striato-nigro-striatal dopamine spiral: dopamine remains a single global signal shared by all loops.  0 = none"`
+}
+
+func (lc *BGLoopsConfig) Defaults() {
+	lc.Names = []string{"Limbic", "PFC", "Motor"}
+	lc.NPoolsY = 1
+	lc.NPoolsX = 4
+	lc.NNeurY = 4
+	lc.NNeurX = 4
+	lc.GPNeurY = 4
+	lc.GPNeurX = 4
+	lc.SharedSTN = true
+	lc.GPeCross = 0.2
+	lc.ThalToNext = 0.2
+}
+
+// BGLoop has the layers for one cortico-BG-thalamic loop created by AddBGLoops.
+// If BGLoopsConfig.SharedSTN is set, the STNp and STNs layers are the same for all loops.
+type BGLoop struct {
+	MtxGo  *Layer
+	MtxNo  *Layer
+	GPeOut *Layer
+	GPeIn  *Layer
+	GPeTA  *Layer
+	STNp   *Layer
+	STNs   *Layer
+	GPi    *Layer
+	Thal   *Layer
+}
+
+// AddBGLoops adds N parallel cortico-BG-thalamic loops according to given config,
+// each with the same layers and internal connectivity as AddBG, named with the
+// config Names prefixes, plus a BG gated thalamus (BGThalLayer) for each loop
+// that receives from the loop's GPi and is the ThalLay1 for its Matrix layers.
+// If SharedSTN, the STNp and STNs layers have no prefix, and are positioned with the first loop.
+// Convergence across loops is determined by SharedSTN, GPeCross and ThalToNext.
+// Cortical inputs to Matrix (ConnectToMatrix) and STN layers, and from the
+// thalamus to cortex, must be made separately.
+// space is the spacing between layers (2 typical).
+func (net *Network) AddBGLoops(cfg *BGLoopsConfig, space float32) []*BGLoop {
+	full := prjn.NewFull()
+	nl := len(cfg.Names)
+	loops := make([]*BGLoop, nl)
+
+	var stnp, stns *Layer
+	for li, prefix := range cfg.Names {
+		lp := &BGLoop{}
+		loops[li] = lp
+		lp.GPi = net.AddGPiLayer2D(prefix+"GPi", cfg.GPNeurY, cfg.GPNeurX)
+		lp.GPeOut = net.AddGPeLayer2D(prefix+"GPeOut", cfg.GPNeurY, cfg.GPNeurX)
+		lp.GPeOut.SetBuildConfig("GPType", "GPeOut")
+		lp.GPeIn = net.AddGPeLayer2D(prefix+"GPeIn", cfg.GPNeurY, cfg.GPNeurX)
+		lp.GPeIn.SetBuildConfig("GPType", "GPeIn")
+		lp.GPeTA = net.AddGPeLayer2D(prefix+"GPeTA", cfg.GPNeurY, cfg.GPNeurX)
+		lp.GPeTA.SetBuildConfig("GPType", "GPeTA")
+		switch {
+		case !cfg.SharedSTN:
+			lp.STNp = net.AddSTNLayer2D(prefix+"STNp", cfg.GPNeurY, cfg.GPNeurX)
+			lp.STNs = net.AddSTNLayer2D(prefix+"STNs", cfg.GPNeurY, cfg.GPNeurX)
+		case li == 0:
+			stnp = net.AddSTNLayer2D("STNp", cfg.GPNeurY, cfg.GPNeurX)
+			stns = net.AddSTNLayer2D("STNs", cfg.GPNeurY, cfg.GPNeurX)
+			fallthrough
+		default:
+			lp.STNp = stnp
+			lp.STNs = stns
+		}
+		lp.MtxGo = net.AddMatrixLayer(prefix+"MtxGo", cfg.NPoolsY, cfg.NPoolsX, cfg.NNeurY, cfg.NNeurX, D1Mod)
+		lp.MtxNo = net.AddMatrixLayer(prefix+"MtxNo", cfg.NPoolsY, cfg.NPoolsX, cfg.NNeurY, cfg.NNeurX, D2Mod)
+		lp.Thal = net.AddBGThalLayer4D(prefix+"Thal", cfg.NPoolsY, cfg.NPoolsX, cfg.NNeurY, cfg.NNeurX)
+
+		lp.MtxGo.SetBuildConfig("OtherMatrixName", lp.MtxNo.Name())
+		lp.MtxNo.SetBuildConfig("OtherMatrixName", lp.MtxGo.Name())
+		lp.MtxGo.SetBuildConfig("ThalLay1Name", lp.Thal.Name())
+		lp.MtxNo.SetBuildConfig("ThalLay1Name", lp.Thal.Name())
+
+		net.ConnectBG(lp.MtxGo, lp.MtxNo, lp.GPeOut, lp.GPeIn, lp.GPeTA, lp.STNp, lp.STNs, lp.GPi, full)
+		net.ConnectLayers(lp.GPi, lp.Thal, full, InhibPrjn).SetClass("BgFixed")
+
+		if li > 0 {
+			// place to the right of the widest row of the prior loop
+			pl := loops[li-1]
+			gpw := pl.GPi.Size().X
+			wd := mat32.Max(3*gpw+2*space, pl.MtxGo.Size().X+pl.MtxNo.Size().X+space)
+			lp.GPi.SetRelPos(relpos.Rel{Rel: relpos.RightOf, Other: pl.GPi.Name(), YAlign: relpos.Front, Space: wd - gpw + space, Scale: 1})
+		}
+		lp.GPeOut.PlaceBehind(lp.GPi, space)
+		lp.GPeIn.PlaceRightOf(lp.GPeOut, space)
+		lp.GPeTA.PlaceRightOf(lp.GPeIn, space)
+		if !cfg.SharedSTN || li == 0 {
+			lp.STNp.PlaceRightOf(lp.GPi, space)
+			lp.STNs.PlaceRightOf(lp.STNp, space)
+		}
+		lp.MtxGo.PlaceBehind(lp.GPeOut, space)
+		lp.MtxNo.PlaceRightOf(lp.MtxGo, space)
+		lp.Thal.PlaceAbove(lp.GPi)
+	}
+
+	if cfg.GPeCross > 0 {
+		for si, slp := range loops {
+			for ri, rlp := range loops {
+				if si == ri {
+					continue
+				}
+				pj := net.ConnectLayers(slp.GPeIn, rlp.GPeIn, full, InhibPrjn)
+				pj.SetClass("GPeCross")
+				pj.DefParams = params.Params{
+					"Prjn.PrjnScale.Rel": fmt.Sprintf("%g", cfg.GPeCross),
+				}
+			}
+		}
+	}
+
+	if cfg.ThalToNext > 0 {
+		for li := 1; li < nl; li++ {
+			slp := loops[li-1]
+			rlp := loops[li]
+			for _, mtx := range []*Layer{rlp.MtxGo, rlp.MtxNo} {
+				pj := net.ConnectToMatrix(slp.Thal, mtx, full)
+				pj.SetClass("BGThalToNext")
+				pj.DefParams = params.Params{
+					"Prjn.PrjnScale.Rel": fmt.Sprintf("%g", cfg.ThalToNext),
+				}
+			}
+		}
+	}
+	return loops
+}
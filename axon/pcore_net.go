@@ -31,34 +31,7 @@ func (net *Network) AddBG(prefix string, nPoolsY, nPoolsX, nNeurY, nNeurX, gpNeu
 	mtxGo.SetBuildConfig("OtherMatrixName", mtxNo.Name())
 	mtxNo.SetBuildConfig("OtherMatrixName", mtxGo.Name())
 
-	full := prjn.NewFull()
-
-	net.ConnectLayers(mtxGo, gpeOut, full, InhibPrjn).SetClass("BgFixed")
-
-	net.ConnectLayers(mtxNo, gpeIn, full, InhibPrjn)
-	net.ConnectLayers(gpeOut, gpeIn, full, InhibPrjn)
-
-	net.ConnectLayers(gpeIn, gpeTA, full, InhibPrjn).SetClass("BgFixed")
-	net.ConnectLayers(gpeIn, stnp, full, InhibPrjn).SetClass("BgFixed")
-
-	// note: this projection exists in bio, but does weird things with Ca dynamics in STNs..
-	// nt.ConnectLayers(gpeIn, stns, full, InhibPrjn).SetClass("BgFixed")
-
-	net.ConnectLayers(gpeIn, gpi, full, InhibPrjn)
-	net.ConnectLayers(mtxGo, gpi, full, InhibPrjn)
-
-	net.ConnectLayers(stnp, gpeOut, full, ForwardPrjn).SetClass("FmSTNp")
-	net.ConnectLayers(stnp, gpeIn, full, ForwardPrjn).SetClass("FmSTNp")
-	net.ConnectLayers(stnp, gpeTA, full, ForwardPrjn).SetClass("FmSTNp")
-	net.ConnectLayers(stnp, gpi, full, ForwardPrjn).SetClass("FmSTNp")
-
-	net.ConnectLayers(stns, gpi, full, ForwardPrjn).SetClass("FmSTNs")
-
-	net.ConnectLayers(gpeTA, mtxGo, full, InhibPrjn).SetClass("GPeTAToMtx")
-	net.ConnectLayers(gpeTA, mtxNo, full, InhibPrjn).SetClass("GPeTAToMtx")
-
-	net.ConnectLayers(gpeIn, mtxGo, full, InhibPrjn).SetClass("GPeInToMtx")
-	net.ConnectLayers(gpeIn, mtxNo, full, InhibPrjn).SetClass("GPeInToMtx")
+	net.ConnectBG(mtxGo, mtxNo, gpeOut, gpeIn, gpeTA, stnp, stns, gpi, prjn.NewFull())
 
 	gpeOut.PlaceBehind(gpi, space)
 	gpeIn.PlaceRightOf(gpeOut, space)
@@ -99,46 +72,54 @@ func (net *Network) AddBG4D(prefix string, nPoolsY, nPoolsX, nNeurY, nNeurX, gpN
 	mtxGo.SetBuildConfig("OtherMatrixName", mtxNo.Name())
 	mtxNo.SetBuildConfig("OtherMatrixName", mtxGo.Name())
 
-	p1to1 := prjn.NewPoolOneToOne()
+	net.ConnectBG(mtxGo, mtxNo, gpeOut, gpeIn, gpeTA, stnp, stns, gpi, prjn.NewPoolOneToOne())
+
+	gpeOut.PlaceBehind(gpi, space)
+	gpeIn.PlaceRightOf(gpeOut, space)
+	gpeTA.PlaceRightOf(gpeIn, space)
+	stnp.PlaceRightOf(gpi, space)
+	stns.PlaceRightOf(stnp, space)
+
+	mtxGo.PlaceBehind(gpeOut, space)
+	mtxNo.PlaceRightOf(mtxGo, space)
+
+	return
+}
+
+// ConnectBG makes the standard internal projections among the PCORE BG layers
+// created by AddBG, AddBG4D and AddBGLoops, using standard styles.
+// The topographic projections among the Matrix, GP and STN layers use the
+// given pattern (Full for 2D GP layers, PoolOneToOne for 4D pools),
+// while the STNp -> GPeTA and GPe -> Matrix projections are always Full.
+func (net *Network) ConnectBG(mtxGo, mtxNo, gpeOut, gpeIn, gpeTA, stnp, stns, gpi *Layer, pat prjn.Pattern) {
 	full := prjn.NewFull()
 
-	net.ConnectLayers(mtxGo, gpeOut, p1to1, InhibPrjn).SetClass("BgFixed")
+	net.ConnectLayers(mtxGo, gpeOut, pat, InhibPrjn).SetClass("BgFixed")
 
-	net.ConnectLayers(mtxNo, gpeIn, p1to1, InhibPrjn)
-	net.ConnectLayers(gpeOut, gpeIn, p1to1, InhibPrjn)
+	net.ConnectLayers(mtxNo, gpeIn, pat, InhibPrjn)
+	net.ConnectLayers(gpeOut, gpeIn, pat, InhibPrjn)
 
-	net.ConnectLayers(gpeIn, gpeTA, p1to1, InhibPrjn).SetClass("BgFixed")
-	net.ConnectLayers(gpeIn, stnp, p1to1, InhibPrjn).SetClass("BgFixed")
+	net.ConnectLayers(gpeIn, gpeTA, pat, InhibPrjn).SetClass("BgFixed")
+	net.ConnectLayers(gpeIn, stnp, pat, InhibPrjn).SetClass("BgFixed")
 
 	// note: this projection exists in bio, but does weird things with Ca dynamics in STNs..
-	// nt.ConnectLayers(gpeIn, stns, p1to1, InhibPrjn).SetClass("BgFixed")
+	// nt.ConnectLayers(gpeIn, stns, pat, InhibPrjn).SetClass("BgFixed")
 
-	net.ConnectLayers(gpeIn, gpi, p1to1, InhibPrjn)
-	net.ConnectLayers(mtxGo, gpi, p1to1, InhibPrjn)
+	net.ConnectLayers(gpeIn, gpi, pat, InhibPrjn)
+	net.ConnectLayers(mtxGo, gpi, pat, InhibPrjn)
 
-	net.ConnectLayers(stnp, gpeOut, p1to1, ForwardPrjn).SetClass("FmSTNp")
-	net.ConnectLayers(stnp, gpeIn, p1to1, ForwardPrjn).SetClass("FmSTNp")
+	net.ConnectLayers(stnp, gpeOut, pat, ForwardPrjn).SetClass("FmSTNp")
+	net.ConnectLayers(stnp, gpeIn, pat, ForwardPrjn).SetClass("FmSTNp")
 	net.ConnectLayers(stnp, gpeTA, full, ForwardPrjn).SetClass("FmSTNp")
-	net.ConnectLayers(stnp, gpi, p1to1, ForwardPrjn).SetClass("FmSTNp")
+	net.ConnectLayers(stnp, gpi, pat, ForwardPrjn).SetClass("FmSTNp")
 
-	net.ConnectLayers(stns, gpi, p1to1, ForwardPrjn).SetClass("FmSTNs")
+	net.ConnectLayers(stns, gpi, pat, ForwardPrjn).SetClass("FmSTNs")
 
 	net.ConnectLayers(gpeTA, mtxGo, full, InhibPrjn).SetClass("GPeTAToMtx")
 	net.ConnectLayers(gpeTA, mtxNo, full, InhibPrjn).SetClass("GPeTAToMtx")
 
 	net.ConnectLayers(gpeIn, mtxGo, full, InhibPrjn).SetClass("GPeInToMtx")
 	net.ConnectLayers(gpeIn, mtxNo, full, InhibPrjn).SetClass("GPeInToMtx")
-
-	gpeOut.PlaceBehind(gpi, space)
-	gpeIn.PlaceRightOf(gpeOut, space)
-	gpeTA.PlaceRightOf(gpeIn, space)
-	stnp.PlaceRightOf(gpi, space)
-	stns.PlaceRightOf(stnp, space)
-
-	mtxGo.PlaceBehind(gpeOut, space)
-	mtxNo.PlaceRightOf(mtxGo, space)
-
-	return
 }
 
 // AddBGThalLayer4D adds a BG gated thalamus (e.g., VA/VL/VM, MD) Layer
@@ -0,0 +1,158 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/etime"
+	"github.com/goki/mat32"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBGTestNet(t *testing.T) (*Network, *Context, *Layer) {
+	ctx := NewContext()
+	net := NewNetwork("BGTest")
+	net.SetMaxData(ctx, 2)
+	mtxGo, _, _, _, _, _ := net.AddBG("", 1, 3, 2, 2, 2, 2, 2)
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	net.InitWts(ctx)
+	return net, ctx, mtxGo
+}
+
+func TestBGActionSelect(t *testing.T) {
+	_, ctx, mtxGo := newBGTestNet(t)
+	as := NewBGActionSelector(mtxGo)
+	assert.Equal(t, 3, as.NStripes())
+
+	// di 0: stripes 1 and 2 gated, 2 more active; di 1: no gating
+	spks := []float32{0.1, 0.3, 0.6}
+	for si, spk := range spks {
+		pl := mtxGo.Pool(uint32(si+1), 0)
+		pl.AvgMax.SpkMax.Cycle.Avg = spk
+		pl.Gated.SetBool(si > 0)
+	}
+	mtxGo.Pool(0, 0).Gated.SetBool(true)
+	mtxGo.LayerVals(0).RT = 42
+	mtxGo.Pool(0, 1).Gated.SetBool(false)
+
+	as.Select(ctx)
+	stripe, gateCyc, conf, noGate := as.Action(0)
+	assert.Equal(t, 2, stripe)
+	assert.Equal(t, float32(42), gateCyc)
+	assert.InDelta(t, 0.6, conf, 1.0e-6)
+	assert.False(t, noGate)
+
+	stripe, gateCyc, conf, noGate = as.Action(1)
+	assert.Equal(t, -1, stripe)
+	assert.Equal(t, float32(-1), gateCyc)
+	assert.Equal(t, float32(0), conf)
+	assert.True(t, noGate)
+}
+
+func TestBGActionExplore(t *testing.T) {
+	net, ctx, mtxGo := newBGTestNet(t)
+	mtxNo := net.AxonLayerByName("MtxNo")
+	assert.False(t, mtxGo.Params.Acts.Clamp.Add.IsTrue())
+	as := NewBGActionSelector(mtxGo)
+	ns := as.NStripes()
+	assert.True(t, mtxGo.Params.Acts.Clamp.Add.IsTrue())
+	assert.False(t, mtxNo.Params.Acts.Clamp.Add.IsTrue())
+
+	// Explore before InitExt / ApplyExts: noise must survive them
+	ctx.NewState(etime.Train)
+	net.NewState(ctx)
+	as.Explore(ctx)
+	net.InitExt(ctx)
+	net.ApplyExts(ctx)
+	for di := 0; di < int(ctx.NetIdxs.NData); di++ {
+		sum := float32(0)
+		for si := 0; si < ns; si++ {
+			sum += as.Noise[di*ns+si]
+		}
+		assert.InDelta(t, as.ExploreGe, sum, 1.0e-5)
+	}
+	for lni := uint32(0); lni < mtxGo.NNeurons; lni++ {
+		ni := mtxGo.NeurStIdx + lni
+		si := int(NrnI(ctx, ni, NrnSubPool)) - 1
+		for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
+			assert.Equal(t, as.Noise[int(di)*ns+si], NrnV(ctx, ni, di, Ext))
+			assert.True(t, NrnHasFlag(ctx, ni, di, NeuronHasExt))
+		}
+	}
+
+	net.Cycle(ctx)
+	ni := mtxGo.NeurStIdx
+	assert.InDelta(t, as.Noise[0], NrnV(ctx, ni, 0, GeExt), 1.0e-6)
+
+	as.ClearExplore(ctx)
+	for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
+		assert.Equal(t, float32(0), NrnV(ctx, ni, di, Ext))
+		assert.False(t, NrnHasFlag(ctx, ni, di, NeuronHasExt))
+	}
+}
+
+func TestBGLoops(t *testing.T) {
+	ctx := NewContext()
+	net := NewNetwork("BGLoops")
+	cfg := &BGLoopsConfig{}
+	cfg.Defaults()
+	loops := net.AddBGLoops(cfg, 2)
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	net.InitWts(ctx)
+
+	assert.Equal(t, len(cfg.Names), len(loops))
+	stnp := loops[0].STNp
+	for _, lp := range loops {
+		assert.Equal(t, stnp, lp.STNp)
+		assert.Equal(t, lp.Thal.Index(), int(lp.MtxGo.Params.Matrix.ThalLay1Idx))
+		assert.Equal(t, lp.MtxNo.Index(), int(lp.MtxGo.Params.Matrix.OtherMatrixIdx))
+		_, err := stnp.SendNameTry(lp.GPeIn.Name())
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, len(loops), len(stnp.RcvPrjns))
+
+	for si, slp := range loops {
+		for ri, rlp := range loops {
+			pj, err := rlp.GPeIn.SendNameTry(slp.GPeIn.Name())
+			if si == ri {
+				assert.Error(t, err)
+				continue
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cfg.GPeCross, pj.(*Prjn).Params.PrjnScale.Rel)
+		}
+	}
+
+	for li := 1; li < len(loops); li++ {
+		pj, err := loops[li].MtxGo.SendNameTry(loops[li-1].Thal.Name())
+		assert.NoError(t, err)
+		assert.Equal(t, MatrixPrjn, pj.(*Prjn).PrjnType())
+		assert.Equal(t, cfg.ThalToNext, pj.(*Prjn).Params.PrjnScale.Rel)
+	}
+	_, err := loops[0].MtxGo.SendNameTry(loops[len(loops)-1].Thal.Name())
+	assert.Error(t, err)
+
+	ctx.NewState(etime.Train)
+	net.NewState(ctx)
+	for cyc := 0; cyc < 50; cyc++ {
+		net.Cycle(ctx)
+		ctx.CycleInc()
+	}
+	for _, lp := range loops {
+		act := lp.GPi.Pool(0, 0).AvgMax.Act.Cycle.Avg
+		assert.False(t, mat32.IsNaN(act))
+		assert.Greater(t, act, float32(0)) // tonically active
+	}
+
+	net.Layout()
+	x0 := loops[0].GPi.Pos().X
+	for _, lp := range loops[1:] {
+		assert.Greater(t, lp.GPi.Pos().X, x0)
+		x0 = lp.GPi.Pos().X
+	}
+}
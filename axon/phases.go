@@ -0,0 +1,280 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/looper"
+	"github.com/goki/ki/kit"
+)
+
+//go:generate stringer -type=PhaseTypes
+
+var KiT_PhaseTypes = kit.Enums.AddEnum(PhaseTypesN, kit.NotBitFlag, nil)
+
+// PhaseTypes are the types of phases within a trial (theta cycle),
+// as configured in a PhaseSeq.
+type PhaseTypes int32
+
+const (
+	// PhaseMinus is an expectation phase, at the end of which
+	// minus phase activity is recorded (Network.MinusPhase).
+	PhaseMinus PhaseTypes = iota
+
+	// PhasePlus is an outcome phase, in which Target layers are clamped
+	// (Network.PlusPhaseStart).  At the end, plus phase activity is
+	// recorded (Network.PlusPhase), and weight changes are computed
+	// (Network.DWt) when learning, which accumulate across multiple
+	// plus phases within the trial.
+	PhasePlus
+
+	PhaseTypesN
+)
+
+// Phase is one phase within a PhaseSeq
+type Phase struct {
+
+	// name of the phase, for display and debugging
+	Name string `desc:"name of the phase, for display and debugging"`
+
+	// type of phase: minus or plus
+	Type PhaseTypes `desc:"type of phase: minus or plus"`
+
+	// number of cycles in this phase -- can be changed on each trial, e.g., in ApplyInputs, for trial-specific lengths
+	Cycles int `desc:"number of cycles in this phase -- can be changed on each trial, e.g., in ApplyInputs, for trial-specific lengths"`
+}
+
+// PhaseHookFunc is a function called for each layer at the start
+// or end of a phase of a given type.
+type PhaseHookFunc func(ly *Layer, ctx *Context, ph *Phase)
+
+// PhaseSeq is a configurable sequence of minus and plus phases within
+// a trial, generalizing the standard single minus then plus phase,
+// e.g., minus-plus-minus, multiple plus phases, or trial-specific lengths.
+// Use LooperPhaseSeq to run it within the looper Cycle loop.
+// Phase lengths can differ across trials, but NOT across data parallel
+// items (NData > 1) within a trial: all data indexes share one PhaseSeq,
+// because they are updated together on each Cycle, with a single
+// Context.Cycle and Context.PlusPhase used by all of the CPU and GPU
+// code.  For data-specific timing of inputs within a trial, use
+// ClampSchedParams, or run each item with NData = 1.
+type PhaseSeq struct {
+
+	// the phases in order
+	Phases []Phase `desc:"the phases in order"`
+
+	// [view: -] functions called for each layer at the start of each phase, by phase type
+	StartHooks [PhaseTypesN][]PhaseHookFunc `view:"-" json:"-" xml:"-" desc:"functions called for each layer at the start of each phase, by phase type"`
+
+	// [view: -] functions called for each layer at the end of each phase, by phase type
+	EndHooks [PhaseTypesN][]PhaseHookFunc `view:"-" json:"-" xml:"-" desc:"functions called for each layer at the end of each phase, by phase type"`
+
+	// index of the current phase -- -1 prior to the start of the first phase
+	Cur int `inactive:"+" desc:"index of the current phase -- -1 prior to the start of the first phase"`
+}
+
+// NewPhaseSeq returns a new empty PhaseSeq -- use Add to add phases.
+func NewPhaseSeq() *PhaseSeq {
+	return &PhaseSeq{Cur: -1}
+}
+
+// NewStdPhaseSeq returns a new PhaseSeq with the standard single minus
+// phase followed by a single plus phase, with given numbers of cycles.
+func NewStdPhaseSeq(minusCycles, plusCycles int) *PhaseSeq {
+	ps := NewPhaseSeq()
+	ps.Add("Minus", PhaseMinus, minusCycles)
+	ps.Add("Plus", PhasePlus, plusCycles)
+	return ps
+}
+
+// Add adds a new phase to the sequence, returning the sequence for
+// chaining calls.
+func (ps *PhaseSeq) Add(name string, typ PhaseTypes, cycles int) *PhaseSeq {
+	ps.Phases = append(ps.Phases, Phase{Name: name, Type: typ, Cycles: cycles})
+	return ps
+}
+
+// AddStartHook adds a function called for each layer at the start of
+// each phase of given type.
+func (ps *PhaseSeq) AddStartHook(typ PhaseTypes, fun PhaseHookFunc) {
+	ps.StartHooks[typ] = append(ps.StartHooks[typ], fun)
+}
+
+// AddEndHook adds a function called for each layer at the end of
+// each phase of given type.
+func (ps *PhaseSeq) AddEndHook(typ PhaseTypes, fun PhaseHookFunc) {
+	ps.EndHooks[typ] = append(ps.EndHooks[typ], fun)
+}
+
+// NCycles returns the total number of cycles across all phases.
+func (ps *PhaseSeq) NCycles() int {
+	n := 0
+	for i := range ps.Phases {
+		n += ps.Phases[i].Cycles
+	}
+	return n
+}
+
+// NPlus returns the number of plus phases.
+func (ps *PhaseSeq) NPlus() int {
+	n := 0
+	for i := range ps.Phases {
+		if ps.Phases[i].Type == PhasePlus {
+			n++
+		}
+	}
+	return n
+}
+
+// StartCycle returns the cycle within the trial at which given phase starts.
+func (ps *PhaseSeq) StartCycle(pi int) int {
+	st := 0
+	for i := 0; i < pi; i++ {
+		st += ps.Phases[i].Cycles
+	}
+	return st
+}
+
+// SpkStCycles returns the cycles within the trial at which SpkSt1 and
+// SpkSt2 are recorded: 1/3 and 2/3 of the way through the first minus
+// phase, corresponding to the standard 50 and 100 cycles of a 150 cycle
+// minus phase.  Returns -1 for both if there is no minus phase.
+func (ps *PhaseSeq) SpkStCycles() (st1, st2 int) {
+	for i := range ps.Phases {
+		ph := &ps.Phases[i]
+		if ph.Type != PhaseMinus {
+			continue
+		}
+		st := ps.StartCycle(i)
+		return st + ph.Cycles/3, st + (2*ph.Cycles)/3
+	}
+	return -1, -1
+}
+
+// NewState resets the current phase at the start of a new trial,
+// and sets ctx.ThetaCycles to the total number of cycles.
+func (ps *PhaseSeq) NewState(ctx *Context) {
+	ps.Cur = -1
+	ctx.ThetaCycles = int32(ps.NCycles())
+}
+
+// Cycle must be called prior to each Network.Cycle call within the trial:
+// at the start of each phase it ends the prior phase and starts the new one.
+func (ps *PhaseSeq) Cycle(ctx *Context, net *Network) {
+	if ps.Cur == -1 {
+		ctx.ThetaCycles = int32(ps.NCycles()) // pick up any trial-specific lengths
+	}
+	nxt := ps.Cur + 1
+	if nxt >= len(ps.Phases) || int(ctx.Cycle) != ps.StartCycle(nxt) {
+		return
+	}
+	if ps.Cur >= 0 {
+		net.PhaseEnd(ctx, ps, ps.Cur)
+	}
+	ps.Cur = nxt
+	net.PhaseStart(ctx, ps, nxt)
+}
+
+// End must be called at the end of the trial, to end the final phase.
+func (ps *PhaseSeq) End(ctx *Context, net *Network) {
+	if ps.Cur >= 0 {
+		net.PhaseEnd(ctx, ps, ps.Cur)
+	}
+	ps.Cur = -1
+}
+
+// RunHooks calls the hook functions on all layers.
+func (ps *PhaseSeq) RunHooks(hooks []PhaseHookFunc, ctx *Context, net *Network, pi int) {
+	if len(hooks) == 0 {
+		return
+	}
+	ph := &ps.Phases[pi]
+	for _, ly := range net.Layers {
+		if ly.IsOff() {
+			continue
+		}
+		for _, fun := range hooks {
+			fun(ly, ctx, ph)
+		}
+	}
+}
+
+// PhaseStart does updating at the start of given phase in the sequence:
+// plus phases apply Target inputs as External inputs (PlusPhaseStart),
+// and minus phases following a plus phase clear them (ClearTargExt).
+func (nt *Network) PhaseStart(ctx *Context, ps *PhaseSeq, pi int) {
+	ph := &ps.Phases[pi]
+	ctx.Phase = int32(pi)
+	if ph.Type == PhasePlus {
+		ctx.NewPhase(true)
+		nt.PlusPhaseStart(ctx)
+	} else {
+		if pi > 0 && ps.Phases[pi-1].Type == PhasePlus {
+			nt.ClearTargExt(ctx)
+		}
+		ctx.NewPhase(false)
+	}
+	ps.RunHooks(ps.StartHooks[ph.Type], ctx, nt, pi)
+}
+
+// PhaseEnd does updating at the end of given phase in the sequence:
+// MinusPhase for minus phases, and PlusPhase for plus phases,
+// followed by DWt when not Testing, so that learning integrates
+// over all of the plus phases.
+func (nt *Network) PhaseEnd(ctx *Context, ps *PhaseSeq, pi int) {
+	ph := &ps.Phases[pi]
+	if ph.Type == PhasePlus {
+		nt.PlusPhase(ctx)
+		if ctx.Testing.IsFalse() {
+			nt.DWt(ctx)
+		}
+	} else {
+		nt.MinusPhase(ctx)
+	}
+	ps.RunHooks(ps.EndHooks[ph.Type], ctx, nt, pi)
+}
+
+// LooperPhaseSeq adds the given sequence of phases within each trial,
+// as an alternative to LooperStdPhases, using the sequence to determine
+// the number of cycles in each trial.  Weight changes are computed at
+// the end of each plus phase, so LooperSimCycleAndLearn only applies
+// them at the end of the trial (this is determined by the Network
+// PhaseSeq, which is set here).
+// SpkSt1 and SpkSt2 are recorded at the cycles given by SpkStCycles.
+// Can pass a trial-level time scale to use instead of the default etime.Trial
+func LooperPhaseSeq(man *looper.Manager, ctx *Context, net *Network, ps *PhaseSeq, trial ...etime.Times) {
+	trl := etime.Trial
+	if len(trial) > 0 {
+		trl = trial[0]
+	}
+	net.PhaseSeq = ps
+	for m := range man.Stacks {
+		mode := m // For closures
+		stack := man.Stacks[mode]
+		cycLoop := stack.Loops[etime.Cycle]
+		cycLoop.Counter.Max = ps.NCycles()
+		stack.Loops[trl].OnStart.Add("NewState", func() {
+			net.NewState(ctx)
+			ctx.NewState(mode)
+			ps.NewState(ctx)
+		})
+		cycLoop.OnStart.Add("PhaseSeq:Cycle", func() {
+			if ps.Cur == -1 {
+				cycLoop.Counter.Max = ps.NCycles() // trial-specific lengths
+			}
+			ps.Cycle(ctx, net)
+			st1, st2 := ps.SpkStCycles()
+			switch int(ctx.Cycle) {
+			case st1:
+				net.SpkSt1(ctx)
+			case st2:
+				net.SpkSt2(ctx)
+			}
+		})
+		stack.Loops[trl].OnEnd.Prepend("PhaseSeq:End", func() { // before UpdateWeights, stats
+			ps.End(ctx, net)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/looper"
+	"github.com/emer/emergent/netview"
+	"github.com/emer/emergent/prjn"
+	"github.com/emer/etable/etensor"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPhaseTestNet(t *testing.T) (*Network, *Context) {
+	return newTestNetLayers(t, 1, func(net *Network) {
+		inp := net.AddLayer2D("Input", 2, 2, InputLayer)
+		out := net.AddLayer2D("Output", 2, 2, TargetLayer)
+		net.ConnectLayers(inp, out, prjn.NewFull(), ForwardPrjn)
+	}, nil)
+}
+
+// runPhaseSeq runs one training trial of the given phase sequence,
+// returning ctx.PlusPhase on each cycle, and the sum of |DWt| for the
+// Output projection.
+func runPhaseSeq(t *testing.T, ps *PhaseSeq) ([]bool, float32) {
+	net, ctx := newPhaseTestNet(t)
+	inp := net.AxonLayerByName("Input")
+	out := net.AxonLayerByName("Output")
+
+	pat := etensor.NewFloat32([]int{2, 2}, nil, nil)
+	pat.SetZeros()
+	pat.Values[0] = 1
+	ctx.NewState(etime.Train)
+	net.NewState(ctx)
+	ps.NewState(ctx)
+	net.InitExt(ctx)
+	inp.ApplyExt(ctx, 0, pat)
+	out.ApplyExt(ctx, 0, pat)
+	net.ApplyExts(ctx)
+	var plus []bool
+	for cyc := 0; cyc < ps.NCycles(); cyc++ {
+		ps.Cycle(ctx, net)
+		net.Cycle(ctx)
+		plus = append(plus, ctx.PlusPhase.IsTrue())
+		ctx.CycleInc()
+	}
+	ps.End(ctx, net)
+
+	pj := out.RcvPrjns[0]
+	var dwt float32
+	for syi := uint32(0); syi < pj.NSyns; syi++ {
+		dwt += SynV(ctx, pj.SynStIdx+syi, DWt) * SynV(ctx, pj.SynStIdx+syi, DWt)
+	}
+	return plus, dwt
+}
+
+func TestPhaseSeq(t *testing.T) {
+	ps := NewStdPhaseSeq(150, 50)
+	assert.Equal(t, 200, ps.NCycles())
+	assert.Equal(t, 1, ps.NPlus())
+	assert.Equal(t, 150, ps.StartCycle(1))
+	st1, st2 := ps.SpkStCycles()
+	assert.Equal(t, 50, st1)
+	assert.Equal(t, 100, st2)
+	st1, st2 = NewPhaseSeq().Add("Plus", PhasePlus, 30).Add("Minus", PhaseMinus, 60).SpkStCycles()
+	assert.Equal(t, 50, st1)
+	assert.Equal(t, 70, st2)
+	st1, st2 = NewPhaseSeq().Add("Plus", PhasePlus, 30).SpkStCycles()
+	assert.Equal(t, -1, st1)
+	assert.Equal(t, -1, st2)
+
+	ps = NewPhaseSeq().Add("Minus", PhaseMinus, 50).Add("Plus", PhasePlus, 30).Add("Minus2", PhaseMinus, 40)
+	var starts, ends []string
+	ps.AddStartHook(PhasePlus, func(ly *Layer, ctx *Context, ph *Phase) {
+		if ly.Nm == "Output" {
+			starts = append(starts, ph.Name)
+		}
+	})
+	ps.AddEndHook(PhaseMinus, func(ly *Layer, ctx *Context, ph *Phase) {
+		if ly.Nm == "Output" {
+			ends = append(ends, ph.Name)
+		}
+	})
+	plus, dwt := runPhaseSeq(t, ps)
+	assert.Equal(t, 120, len(plus))
+	assert.False(t, plus[49])
+	assert.True(t, plus[50])
+	assert.True(t, plus[79])
+	assert.False(t, plus[80])
+	assert.Equal(t, []string{"Plus"}, starts)
+	assert.Equal(t, []string{"Minus", "Minus2"}, ends)
+	assert.Greater(t, dwt, float32(0))
+	assert.Equal(t, -1, ps.Cur)
+
+	// learning accumulates over plus phases: final DWt = DWt at end of
+	// first plus phase + DiDWt from second plus phase
+	ps = NewPhaseSeq().Add("Minus", PhaseMinus, 50).Add("Plus", PhasePlus, 30).Add("Plus2", PhasePlus, 30)
+	var dwts [][]float32
+	var didwt []float32
+	ps.AddEndHook(PhasePlus, func(ly *Layer, ctx *Context, ph *Phase) {
+		if ly.Nm != "Output" {
+			return
+		}
+		pj := ly.RcvPrjns[0]
+		var dw []float32
+		didwt = nil
+		for syi := uint32(0); syi < pj.NSyns; syi++ {
+			dw = append(dw, SynV(ctx, pj.SynStIdx+syi, DWt))
+			didwt = append(didwt, SynCaV(ctx, pj.SynStIdx+syi, 0, DiDWt))
+		}
+		dwts = append(dwts, dw)
+	})
+	runPhaseSeq(t, ps)
+	assert.Equal(t, 2, ps.NPlus())
+	assert.Equal(t, 2, len(dwts))
+	for i := range didwt {
+		assert.InDelta(t, dwts[0][i]+didwt[i], dwts[1][i], 1.0e-7)
+	}
+}
+
+// TestLooperPhaseSeq checks that LooperPhaseSeq runs the configured
+// phases and records SpkSt1 and SpkSt2 at SpkStCycles, not at the
+// standard 50 and 100 cycles.
+func TestLooperPhaseSeq(t *testing.T) {
+	net, ctx := newPhaseTestNet(t)
+	inp := net.AxonLayerByName("Input")
+	out := net.AxonLayerByName("Output")
+
+	pat := etensor.NewFloat32([]int{2, 2}, nil, nil)
+	pat.SetZeros()
+	pat.Values[0] = 1
+
+	ps := NewStdPhaseSeq(60, 30)
+	man := looper.NewManager()
+	man.AddStack(etime.Train).AddTime(etime.Trial, 1).AddTime(etime.Cycle, 200)
+	LooperPhaseSeq(man, ctx, net, ps)
+	LooperSimCycleAndLearn(man, net, ctx, &netview.ViewUpdt{})
+	man.GetLoop(etime.Train, etime.Trial).OnStart.Add("ApplyInputs", func() {
+		net.InitExt(ctx)
+		inp.ApplyExt(ctx, 0, pat)
+		out.ApplyExt(ctx, 0, pat)
+		net.ApplyExts(ctx)
+	})
+	var caSpkP []float32
+	var plus []bool
+	ni := inp.NeurStIdx
+	cycLoop := man.GetLoop(etime.Train, etime.Cycle)
+	cycLoop.OnEnd.Add("Record", func() {
+		caSpkP = append(caSpkP, NrnV(ctx, ni, 0, CaSpkP))
+		plus = append(plus, ctx.PlusPhase.IsTrue())
+	})
+	man.Run(etime.Train)
+
+	assert.Equal(t, 90, len(caSpkP))
+	assert.False(t, plus[59])
+	assert.True(t, plus[60])
+	assert.Greater(t, caSpkP[19], float32(0))
+	assert.Equal(t, caSpkP[19], NrnV(ctx, ni, 0, SpkSt1))
+	assert.Equal(t, caSpkP[39], NrnV(ctx, ni, 0, SpkSt2))
+}
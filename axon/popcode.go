@@ -0,0 +1,205 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"fmt"
+
+	"github.com/goki/mat32"
+)
+
+// PopCodeDecodeThr is the threshold, as a proportion of the maximum
+// value, below which unit values are ignored in decoding a population
+// code, to reduce the influence of background noise activity.
+var PopCodeDecodeThr = float32(0.1)
+
+// popCodeThr returns the decoding threshold for given values
+func popCodeThr(vals []float32) float32 {
+	max := float32(0)
+	for _, v := range vals {
+		max = mat32.Max(max, v)
+	}
+	return PopCodeDecodeThr * max
+}
+
+// DecodeVal decodes the value represented by given population code
+// values (e.g., neuron activities), one per neuron, as encoded by EncodeVal,
+// using the activity-weighted average of the values represented by
+// each neuron (centroid).  Values below PopCodeDecodeThr times the maximum
+// are ignored.  Returns 0 if there is no activity.
+func (pc *PopCodeParams) DecodeVal(vals []float32) float32 {
+	n := len(vals)
+	if n < 2 {
+		return 0
+	}
+	thr := popCodeThr(vals)
+	incr := (pc.Max - pc.Min) / float32(n-1)
+	sum := float32(0)
+	wsum := float32(0)
+	for i, v := range vals {
+		if v <= 0 || v < thr {
+			continue
+		}
+		trg := pc.Min + incr*float32(i)
+		sum += v
+		wsum += v * trg
+	}
+	if sum == 0 {
+		return 0
+	}
+	val := wsum / sum
+	if pc.Clip.IsTrue() {
+		val = pc.ClipVal(val)
+	}
+	return val
+}
+
+// EncodeValCirc returns value for given value, for neuron index i
+// out of n total neurons, for a circular variable such as an angle,
+// where Max wraps around to Min, so neurons are evenly spaced
+// around the circle, with neuron 0 at Min.
+func (pc *PopCodeParams) EncodeValCirc(i, n uint32, val float32) float32 {
+	rng := pc.Max - pc.Min
+	incr := rng / float32(n)
+	trg := pc.Min + incr*float32(i)
+	dist := mat32.Mod(trg-val, rng)
+	if dist < 0 {
+		dist += rng
+	}
+	if dist > 0.5*rng {
+		dist -= rng
+	}
+	sig := pc.MinSigma
+	gnrm := 1.0 / (rng * sig)
+	dist *= gnrm
+	return mat32.FastExp(-(dist * dist))
+}
+
+// DecodeValCirc decodes the value represented by given population code
+// values for a circular variable such as an angle, as encoded by
+// EncodeValCirc, using the activity-weighted average of the unit vectors
+// for the value of each neuron (population vector).
+// Values below PopCodeDecodeThr times the maximum are ignored.
+// Returns Min if there is no activity.
+func (pc *PopCodeParams) DecodeValCirc(vals []float32) float32 {
+	n := len(vals)
+	if n < 2 {
+		return pc.Min
+	}
+	thr := popCodeThr(vals)
+	incr := 2 * mat32.Pi / float32(n)
+	sx := float32(0)
+	sy := float32(0)
+	for i, v := range vals {
+		if v <= 0 || v < thr {
+			continue
+		}
+		ang := incr * float32(i)
+		sx += v * mat32.Cos(ang)
+		sy += v * mat32.Sin(ang)
+	}
+	if sx == 0 && sy == 0 {
+		return pc.Min
+	}
+	ang := mat32.Atan2(sy, sx)
+	if ang < 0 {
+		ang += 2 * mat32.Pi
+	}
+	return pc.Min + (pc.Max-pc.Min)*ang/(2*mat32.Pi)
+}
+
+// EncodeVal2D returns value for given x, y values, for neuron at
+// given y, x indexes in a 2D ny by nx layout, as the product of the 1D
+// EncodeVal codes for each dimension, with the same range for both.
+func (pc *PopCodeParams) EncodeVal2D(yi, xi, ny, nx uint32, x, y float32) float32 {
+	return pc.EncodeVal(xi, nx, x) * pc.EncodeVal(yi, ny, y)
+}
+
+// DecodeVal2D decodes the x, y values represented by given 2D population
+// code values in row-major ny by nx order, as encoded by EncodeVal2D,
+// by decoding the marginal sums of values along each dimension.
+func (pc *PopCodeParams) DecodeVal2D(vals []float32, ny, nx int) (x, y float32) {
+	xs := make([]float32, nx)
+	ys := make([]float32, ny)
+	thr := popCodeThr(vals)
+	for yi := 0; yi < ny; yi++ {
+		for xi := 0; xi < nx; xi++ {
+			v := vals[yi*nx+xi]
+			if v <= 0 || v < thr {
+				continue
+			}
+			xs[xi] += v
+			ys[yi] += v
+		}
+	}
+	return pc.DecodeVal(xs), pc.DecodeVal(ys)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//  Layer-level decoding
+
+// PopCodeVal decodes the value represented by the population code in given
+// neuron variable (e.g., "Act", "CaSpkP") across all the neurons in the layer,
+// for given data index, using the layer's Acts.PopCode parameters.
+// If circ is true, the value is decoded as a circular variable (DecodeValCirc).
+func (ly *Layer) PopCodeVal(varNm string, di int, circ bool) (float32, error) {
+	var vals []float32
+	if err := ly.UnitVals(&vals, varNm, di); err != nil {
+		return 0, err
+	}
+	pc := &ly.Params.Acts.PopCode
+	if circ {
+		return pc.DecodeValCirc(vals), nil
+	}
+	return pc.DecodeVal(vals), nil
+}
+
+// PopCodeVal2D decodes the x, y values represented by a 2D population code
+// in given neuron variable (e.g., "Act", "CaSpkP") for a 2D layer,
+// for given data index, using the layer's Acts.PopCode parameters.
+func (ly *Layer) PopCodeVal2D(varNm string, di int) (x, y float32, err error) {
+	if ly.Is4D() {
+		return 0, 0, fmt.Errorf("PopCodeVal2D: layer %s is 4D, not 2D", ly.Nm)
+	}
+	var vals []float32
+	if err = ly.UnitVals(&vals, varNm, di); err != nil {
+		return
+	}
+	x, y = ly.Params.Acts.PopCode.DecodeVal2D(vals, ly.Shp.Dim(0), ly.Shp.Dim(1))
+	return
+}
+
+// PopCodePoolVals decodes the value represented by the population code in
+// given neuron variable (e.g., "Act", "CaSpkP") within each pool of a 4D layer,
+// for given data index, using the layer's Acts.PopCode parameters,
+// as used for example by the DrivesLayer and USLayer.
+// vals is set to one value per pool, in pool order.
+// If circ is true, values are decoded as circular variables (DecodeValCirc).
+func (ly *Layer) PopCodePoolVals(vals *[]float32, varNm string, di int, circ bool) error {
+	if !ly.Is4D() {
+		return fmt.Errorf("PopCodePoolVals: layer %s is not 4D", ly.Nm)
+	}
+	var uvals []float32
+	if err := ly.UnitVals(&uvals, varNm, di); err != nil {
+		return err
+	}
+	np := int(ly.NPools) - 1
+	if cap(*vals) < np {
+		*vals = make([]float32, np)
+	} else {
+		*vals = (*vals)[:np]
+	}
+	pc := &ly.Params.Acts.PopCode
+	for pi := 1; pi <= np; pi++ {
+		pl := ly.Pool(uint32(pi), uint32(di))
+		pvals := uvals[pl.StIdx:pl.EdIdx]
+		if circ {
+			(*vals)[pi-1] = pc.DecodeValCirc(pvals)
+		} else {
+			(*vals)[pi-1] = pc.DecodeVal(pvals)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPopCodeDecode(t *testing.T) {
+	pc := PopCodeParams{}
+	pc.Defaults()
+	n := uint32(12)
+	vals := make([]float32, n)
+	for _, val := range []float32{0, 0.1, 0.33, 0.5, 0.77, 1} {
+		for i := range vals {
+			vals[i] = pc.EncodeVal(uint32(i), n, val)
+		}
+		assert.InDelta(t, val, pc.DecodeVal(vals), 0.02)
+	}
+	// rate code modulation does not affect decoding
+	pc.MinAct = 0.2
+	pc.MinSigma = 0.08
+	pc.MaxSigma = 0.12
+	for _, val := range []float32{0.2, 0.8} {
+		for i := range vals {
+			vals[i] = pc.EncodeVal(uint32(i), n, val)
+		}
+		assert.InDelta(t, val, pc.DecodeVal(vals), 0.02)
+	}
+	for i := range vals {
+		vals[i] = 0
+	}
+	assert.Equal(t, float32(0), pc.DecodeVal(vals))
+}
+
+func TestPopCodeDecodeCirc(t *testing.T) {
+	pc := PopCodeParams{}
+	pc.Defaults()
+	pc.SetRange(0, 360, 0.1, 0.1)
+	n := uint32(16)
+	vals := make([]float32, n)
+	for _, val := range []float32{0, 45, 100, 180, 270, 350} {
+		for i := range vals {
+			vals[i] = pc.EncodeValCirc(uint32(i), n, val)
+		}
+		dv := pc.DecodeValCirc(vals)
+		if val > 300 && dv < 10 { // equivalent around the wrap
+			dv += 360
+		}
+		assert.InDelta(t, val, dv, 2)
+	}
+	// wraps: 350 activates neuron 0 more than the middle
+	for i := range vals {
+		vals[i] = pc.EncodeValCirc(uint32(i), n, 350)
+	}
+	assert.Greater(t, vals[0], vals[n/2])
+}
+
+func TestPopCodeDecode2D(t *testing.T) {
+	pc := PopCodeParams{}
+	pc.Defaults()
+	ny, nx := 8, 10
+	vals := make([]float32, ny*nx)
+	x, y := float32(0.3), float32(0.75)
+	for yi := 0; yi < ny; yi++ {
+		for xi := 0; xi < nx; xi++ {
+			vals[yi*nx+xi] = pc.EncodeVal2D(uint32(yi), uint32(xi), uint32(ny), uint32(nx), x, y)
+		}
+	}
+	dx, dy := pc.DecodeVal2D(vals, ny, nx)
+	assert.InDelta(t, x, dx, 0.02)
+	assert.InDelta(t, y, dy, 0.02)
+}
+
+func TestLayerPopCodeVals(t *testing.T) {
+	ctx := NewContext()
+	net := NewNetwork("PopCode")
+	net.SetMaxData(ctx, 2)
+	l1 := net.AddLayer2D("L1", 1, 12, InputLayer)
+	l2 := net.AddLayer2D("L2", 8, 10, InputLayer)
+	l4 := net.AddLayer4D("L4", 1, 3, 1, 12, InputLayer)
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	net.InitWts(ctx)
+
+	// layer-wide 1D, different values per data index
+	pc := &l1.Params.Acts.PopCode
+	for di := uint32(0); di < 2; di++ {
+		val := 0.2 + 0.5*float32(di)
+		for lni := uint32(0); lni < l1.NNeurons; lni++ {
+			SetNrnV(ctx, l1.NeurStIdx+lni, di, CaSpkP, pc.EncodeVal(lni, l1.NNeurons, val))
+		}
+	}
+	for di := 0; di < 2; di++ {
+		val, err := l1.PopCodeVal("CaSpkP", di, false)
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.2+0.5*float32(di), val, 0.02)
+	}
+	_, err := l1.PopCodeVal("NotAVar", 0, false)
+	assert.Error(t, err)
+
+	// 2D
+	pc = &l2.Params.Acts.PopCode
+	for yi := uint32(0); yi < 8; yi++ {
+		for xi := uint32(0); xi < 10; xi++ {
+			SetNrnV(ctx, l2.NeurStIdx+yi*10+xi, 1, Act, pc.EncodeVal2D(yi, xi, 8, 10, 0.6, 0.1))
+		}
+	}
+	x, y, err := l2.PopCodeVal2D("Act", 1)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.6, x, 0.02)
+	assert.InDelta(t, 0.1, y, 0.02)
+	_, _, err = l4.PopCodeVal2D("Act", 0)
+	assert.Error(t, err)
+
+	// per pool, linear and circular
+	pc = &l4.Params.Acts.PopCode
+	pvals := []float32{0.1, 0.5, 0.9}
+	for pi, val := range pvals {
+		pl := l4.Pool(uint32(pi+1), 0)
+		nn := uint32(pl.NNeurons())
+		for pni := uint32(0); pni < nn; pni++ {
+			SetNrnV(ctx, l4.NeurStIdx+pl.StIdx+pni, 0, Act, pc.EncodeVal(pni, nn, val))
+		}
+	}
+	var dvals []float32
+	assert.NoError(t, l4.PopCodePoolVals(&dvals, "Act", 0, false))
+	assert.Equal(t, 3, len(dvals))
+	for pi, val := range pvals {
+		assert.InDelta(t, val, dvals[pi], 0.02)
+	}
+	pc.SetRange(0, 1, 0.1, 0.1)
+	for pi := range pvals { // re-encode with circular range
+		pl := l4.Pool(uint32(pi+1), 1)
+		nn := uint32(pl.NNeurons())
+		for pni := uint32(0); pni < nn; pni++ {
+			SetNrnV(ctx, l4.NeurStIdx+pl.StIdx+pni, 1, Act, pc.EncodeValCirc(pni, nn, pvals[pi]))
+		}
+	}
+	assert.NoError(t, l4.PopCodePoolVals(&dvals, "Act", 1, true))
+	for pi, val := range pvals {
+		assert.InDelta(t, val, dvals[pi], 0.02)
+	}
+	assert.Error(t, l1.PopCodePoolVals(&dvals, "Act", 0, false))
+}
@@ -5,13 +5,14 @@
 package axon
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
 
-	"github.com/emer/emergent/erand"
 	"github.com/emer/emergent/weights"
 	"github.com/emer/etable/etensor"
+	"github.com/goki/gosl/slrand"
 	"github.com/goki/ki/indent"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
@@ -29,6 +30,12 @@ type Prjn struct {
 
 	// all prjn-level parameters -- these must remain constant once configured
 	Params *PrjnParams `desc:"all prjn-level parameters -- these must remain constant once configured"`
+
+	// epoch-based learning rate schedule, which sets Params.Learn.LRate.Sched -- CPU-only, set with Network.SetPrjnLRateSched
+	LRateSchedule LRateSchedParams `view:"inline" desc:"epoch-based learning rate schedule, which sets Params.Learn.LRate.Sched -- CPU-only, set with Network.SetPrjnLRateSched"`
+
+	// state of the LRateSchedule learning rate schedule, which is saved in the weights file
+	LRateSchedState LRateSchedState `view:"inline" desc:"state of the LRateSchedule learning rate schedule, which is saved in the weights file"`
 }
 
 var KiT_Prjn = kit.Types.AddType(&Prjn{}, PrjnProps)
@@ -60,12 +67,13 @@ func (pj *Prjn) Defaults() {
 	}
 	pj.Params.PrjnType = pj.PrjnType()
 	pj.Params.Defaults()
+	pj.LRateSchedule.Defaults()
 	switch pj.PrjnType() {
 	case InhibPrjn:
 		pj.Params.SWts.Adapt.On.SetBool(false)
 	case BackPrjn:
 		pj.Params.PrjnScale.Rel = 0.1
-	case RWPrjn, TDPredPrjn:
+	case RWPrjn, TDPredPrjn, SRPrjn:
 		pj.Params.RLPredDefaults()
 	case BLAPrjn:
 		pj.Params.BLADefaults()
@@ -100,6 +108,10 @@ func (pj *Prjn) UpdateParams() {
 // AllParams returns a listing of all parameters in the Layer
 func (pj *Prjn) AllParams() string {
 	str := "///////////////////////////////////////////////////\nPrjn: " + pj.Name() + "\n" + pj.Params.AllParams()
+	if pj.LRateSchedule.Policy != LRateSchedOff {
+		b, _ := json.MarshalIndent(&pj.LRateSchedule, "", " ")
+		str += "LRateSchedule: {\n " + JsonToParams(b)
+	}
 	return str
 }
 
@@ -151,6 +163,23 @@ func (pj *Prjn) WriteWtsJSON(w io.Writer, depth int) {
 	w.Write(indent.TabBytes(depth))
 	w.Write([]byte(fmt.Sprintf("\"From\": %q,\n", slay.Name())))
 	w.Write(indent.TabBytes(depth))
+	if pj.LRateSchedule.Policy != LRateSchedOff {
+		st := &pj.LRateSchedState
+		w.Write([]byte(fmt.Sprintf("\"MetaData\": {\n")))
+		depth++
+		w.Write(indent.TabBytes(depth))
+		w.Write([]byte(fmt.Sprintf("\"LRateSched\": \"%g\",\n", pj.Params.Learn.LRate.Sched)))
+		w.Write(indent.TabBytes(depth))
+		w.Write([]byte(fmt.Sprintf("\"LRateSchedMult\": \"%g\",\n", st.Mult)))
+		w.Write(indent.TabBytes(depth))
+		w.Write([]byte(fmt.Sprintf("\"LRateSchedBest\": \"%g\",\n", st.Best)))
+		w.Write(indent.TabBytes(depth))
+		w.Write([]byte(fmt.Sprintf("\"LRateSchedNBad\": \"%d\"\n", st.NBad)))
+		depth--
+		w.Write(indent.TabBytes(depth))
+		w.Write([]byte("},\n"))
+		w.Write(indent.TabBytes(depth))
+	}
 	w.Write([]byte(fmt.Sprintf("\"Rs\": [\n")))
 	depth++
 	for ri := 0; ri < nr; ri++ {
@@ -231,6 +260,25 @@ func (pj *Prjn) ReadWtsJSON(r io.Reader) error {
 // SetWts sets the weights for this projection from weights.Prjn decoded values
 func (pj *Prjn) SetWts(pw *weights.Prjn) error {
 	var err error
+	if pw.MetaData != nil {
+		st := &pj.LRateSchedState
+		if ls, ok := pw.MetaData["LRateSched"]; ok {
+			pv, _ := strconv.ParseFloat(ls, 32)
+			pj.LRateSched(float32(pv))
+		}
+		if mu, ok := pw.MetaData["LRateSchedMult"]; ok {
+			pv, _ := strconv.ParseFloat(mu, 32)
+			st.Mult = float32(pv)
+		}
+		if bs, ok := pw.MetaData["LRateSchedBest"]; ok {
+			pv, _ := strconv.ParseFloat(bs, 32)
+			st.Best = float32(pv)
+		}
+		if nb, ok := pw.MetaData["LRateSchedNBad"]; ok {
+			pv, _ := strconv.ParseInt(nb, 10, 32)
+			st.NBad = int32(pv)
+		}
+	}
 	for i := range pw.Rs {
 		pr := &pw.Rs[i]
 		hasWt1 := len(pr.Wt1) >= len(pr.Si)
@@ -344,8 +392,8 @@ func (pj *Prjn) SetSWtsFunc(ctx *Context, swtFun func(si, ri int, send, recv *et
 // InitWtsSyn initializes weight values based on WtInit randomness parameters
 // for an individual synapse.
 // It also updates the linear weight value based on the sigmoidal weight value.
-func (pj *Prjn) InitWtsSyn(ctx *Context, syni uint32, rnd erand.Rand, mean, spct float32) {
-	pj.Params.SWts.InitWtsSyn(ctx, syni, rnd, mean, spct)
+func (pj *Prjn) InitWtsSyn(ctx *Context, syni uint32, ctr slrand.Counter, mean, spct float32) {
+	pj.Params.SWts.InitWtsSyn(ctx, syni, ctr, mean, spct)
 }
 
 // InitSynCa initializes synaptic calcium variables
@@ -357,6 +405,7 @@ func (pj *Prjn) InitSynCa(ctx *Context, syni, di uint32) {
 // enforcing current constraints.
 func (pj *Prjn) InitWts(ctx *Context, nt *Network) {
 	pj.Params.Learn.LRate.Init()
+	pj.LRateSchedState.Init()
 	pj.InitGBuffs()
 	rlay := pj.Recv
 	spct := pj.Params.SWts.Init.SPct
@@ -374,7 +423,7 @@ func (pj *Prjn) InitWts(ctx *Context, nt *Network) {
 		syIdxs := pj.RecvSynIdxs(lni)
 		for _, syi := range syIdxs {
 			syni := pj.SynStIdx + syi
-			pj.InitWtsSyn(ctx, syni, &nt.Rand, smn, spct)
+			pj.InitWtsSyn(ctx, syni, nt.RandCtr, smn, spct)
 			for di := uint32(0); di < rlay.MaxData; di++ {
 				pj.InitSynCa(ctx, syni, di)
 			}
@@ -113,7 +113,7 @@ func (pj *Prjn) DWt(ctx *Context, si uint32) {
 			layPool := rlay.Pool(0, di)
 			subPool := rlay.SubPool(ctx, ri, di)
 			pj.Params.DWtSyn(ctx, syni, si, ri, di, layPool, subPool, isTarget)
-			dwt += SynCaV(ctx, syni, di, DiDWt)
+			dwt += GlbV(ctx, di, GvLearnWt) * SynCaV(ctx, syni, di, DiDWt)
 		}
 		// note: on GPU, this must be a separate kernel, but can be combined here
 		AddSynV(ctx, syni, DWt, dwt)
@@ -289,6 +289,37 @@ func (pj *Prjn) SynFail(ctx *Context) {
 	}
 }
 
+// TDPredTrace updates the TD(lambda) eligibility trace of sending activity
+// in the synapse Tr value for a TDPredPrjn, from the prior trial activity
+// (SpkPrv), for each data index.  Called every trial by the sending
+// Layer.PlusPhase on the CPU (gpu_plusneuron on the GPU), so the trace
+// advances during testing as well as training.
+func (pj *Prjn) TDPredTrace(ctx *Context) {
+	slay := pj.Send
+	for lni := uint32(0); lni < slay.NNeurons; lni++ {
+		si := slay.NeurStIdx + lni
+		scon := pj.SendCon[lni]
+		for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
+			sact := NrnV(ctx, si, di, SpkPrv)
+			for syi := scon.Start; syi < scon.Start+scon.N; syi++ {
+				pj.Params.TDPredTraceSyn(ctx, pj.SynStIdx+syi, di, sact)
+			}
+		}
+	}
+}
+
+// InitTDPredTrace resets the TD(lambda) eligibility trace in the
+// synapse Tr value for a TDPredPrjn, for given data index.
+func (pj *Prjn) InitTDPredTrace(ctx *Context, di uint32) {
+	slay := pj.Send
+	for lni := uint32(0); lni < slay.NNeurons; lni++ {
+		scon := pj.SendCon[lni]
+		for syi := scon.Start; syi < scon.Start+scon.N; syi++ {
+			SetSynCaV(ctx, pj.SynStIdx+syi, di, Tr, 0)
+		}
+	}
+}
+
 // LRateMod sets the LRate modulation parameter for Prjns, which is
 // for dynamic modulation of learning rate (see also LRateSched).
 // Updates the effective learning rate factor accordingly.
@@ -304,3 +335,16 @@ func (pj *Prjn) LRateSched(sched float32) {
 	pj.Params.Learn.LRate.Sched = sched
 	pj.Params.Learn.LRate.Update()
 }
+
+// LRateSchedEpoch sets the schedule-based learning rate multiplier
+// according to the LRateSchedule, for use during given epoch (0 based),
+// using given error measure (e.g., PctErr) computed over the previous
+// epoch for the Plateau policy.  Returns false if the schedule is Off.
+func (pj *Prjn) LRateSchedEpoch(epoch int, err float32) bool {
+	ls := &pj.LRateSchedule
+	if ls.Policy == LRateSchedOff {
+		return false
+	}
+	pj.LRateSched(ls.Sched(&pj.LRateSchedState, epoch, err))
+	return true
+}
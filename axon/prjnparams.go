@@ -113,8 +113,8 @@ type PrjnParams struct {
 	// [view: inline] conductance scaling values
 	GScale GScaleVals `view:"inline" desc:"conductance scaling values"`
 
-	// [view: inline] [viewif: PrjnType=[RWPrjn,TDPredPrjn]] Params for RWPrjn and TDPredPrjn for doing dopamine-modulated learning for reward prediction: Da * Send activity. Use in RWPredLayer or TDPredLayer typically to generate reward predictions. If the Da sign is positive, the first recv unit learns fully; for negative, second one learns fully.  Lower lrate applies for opposite cases.  Weights are positive-only.
-	RLPred RLPredPrjnParams `viewif:"PrjnType=[RWPrjn,TDPredPrjn]" view:"inline" desc:"Params for RWPrjn and TDPredPrjn for doing dopamine-modulated learning for reward prediction: Da * Send activity. Use in RWPredLayer or TDPredLayer typically to generate reward predictions. If the Da sign is positive, the first recv unit learns fully; for negative, second one learns fully.  Lower lrate applies for opposite cases.  Weights are positive-only."`
+	// [view: inline] [viewif: PrjnType=[RWPrjn,TDPredPrjn,SRPrjn]] Params for RWPrjn and TDPredPrjn for doing dopamine-modulated learning for reward prediction: Da * Send activity. Use in RWPredLayer or TDPredLayer typically to generate reward predictions. If the Da sign is positive, the first recv unit learns fully; for negative, second one learns fully.  Lower lrate applies for opposite cases.  Weights are positive-only.
+	RLPred RLPredPrjnParams `viewif:"PrjnType=[RWPrjn,TDPredPrjn,SRPrjn]" view:"inline" desc:"Params for RWPrjn and TDPredPrjn for doing dopamine-modulated learning for reward prediction: Da * Send activity. Use in RWPredLayer or TDPredLayer typically to generate reward predictions. If the Da sign is positive, the first recv unit learns fully; for negative, second one learns fully.  Lower lrate applies for opposite cases.  Weights are positive-only."`
 
 	// [view: inline] [viewif: PrjnType=MatrixPrjn] for trace-based learning in the MatrixPrjn. A trace of synaptic co-activity is formed, and then modulated by dopamine whenever it occurs.  This bridges the temporal gap between gating activity and subsequent activity, and is based biologically on synaptic tags. Trace is reset at time of reward based on ACh level from CINs.
 	Matrix MatrixPrjnParams `viewif:"PrjnType=MatrixPrjn" view:"inline" desc:"for trace-based learning in the MatrixPrjn. A trace of synaptic co-activity is formed, and then modulated by dopamine whenever it occurs.  This bridges the temporal gap between gating activity and subsequent activity, and is based biologically on synaptic tags. Trace is reset at time of reward based on ACh level from CINs."`
@@ -164,7 +164,7 @@ func (pj *PrjnParams) AllParams() string {
 	str += "Learn: {\n " + strings.Replace(JsonToParams(b), " LRate: {", "\n  LRate: {", -1)
 
 	switch pj.PrjnType {
-	case RWPrjn, TDPredPrjn:
+	case RWPrjn, TDPredPrjn, SRPrjn:
 		b, _ = json.MarshalIndent(&pj.RLPred, "", " ")
 		str += "RLPred: {\n " + JsonToParams(b)
 	case MatrixPrjn:
@@ -249,7 +249,7 @@ func (pj *PrjnParams) GatherSpikes(ctx *Context, ly *LayerParams, ni, di uint32,
 // DoSynCa returns false if should not do synaptic-level calcium updating.
 // Done by default in Cortex, not for some other special projection types.
 func (pj *PrjnParams) DoSynCa() bool {
-	if pj.PrjnType == RWPrjn || pj.PrjnType == TDPredPrjn || pj.PrjnType == MatrixPrjn || pj.PrjnType == VSPatchPrjn || pj.PrjnType == BLAPrjn { // || pj.PrjnType == HipPrjn {
+	if pj.PrjnType == RWPrjn || pj.PrjnType == TDPredPrjn || pj.PrjnType == SRPrjn || pj.PrjnType == MatrixPrjn || pj.PrjnType == VSPatchPrjn || pj.PrjnType == BLAPrjn { // || pj.PrjnType == HipPrjn {
 		return false
 	}
 	return true
@@ -286,6 +286,8 @@ func (pj *PrjnParams) DWtSyn(ctx *Context, syni, si, ri, di uint32, layPool, sub
 		pj.DWtSynRWPred(ctx, syni, si, ri, di, layPool, subPool)
 	case TDPredPrjn:
 		pj.DWtSynTDPred(ctx, syni, si, ri, di, layPool, subPool)
+	case SRPrjn:
+		pj.DWtSynSR(ctx, syni, si, ri, di, layPool, subPool)
 	case MatrixPrjn:
 		pj.DWtSynMatrix(ctx, syni, si, ri, di, layPool, subPool)
 	case VSPatchPrjn:
@@ -493,10 +495,34 @@ func (pj *PrjnParams) DWtSynTDPred(ctx *Context, syni, si, ri, di uint32, layPoo
 		}
 	}
 
-	dwt := da * NrnV(ctx, si, di, SpkPrv) // no recv unit activation, prior trial act
+	sact := NrnV(ctx, si, di, SpkPrv) // no recv unit activation, prior trial act
+	if pj.RLPred.Lambda > 0 {
+		sact = SynCaV(ctx, syni, di, Tr) // trace of prior trial act, updated in PlusPhase
+	}
+	dwt := da * sact
 	SetSynCaV(ctx, syni, di, DiDWt, eff_lr*dwt)
 }
 
+// DoTDPredTrace returns true if this is a TDPredPrjn with a
+// TD(lambda) eligibility trace, i.e., RLPred.Lambda > 0.
+func (pj *PrjnParams) DoTDPredTrace() bool {
+	return pj.PrjnType == TDPredPrjn && pj.RLPred.Lambda > 0
+}
+
+// TDPredTraceSyn updates the TD(lambda) eligibility trace of sending
+// activity in the synapse Tr value, for given sending activity.
+// Called in PlusPhaseNeuron for each sending synapse, if DoTDPredTrace.
+func (pj *PrjnParams) TDPredTraceSyn(ctx *Context, syni, di uint32, sact float32) {
+	SetSynCaV(ctx, syni, di, Tr, pj.RLPred.EligTrace(SynCaV(ctx, syni, di, Tr), sact))
+}
+
+// DWtSynSR computes the weight change (learning) at given synapse,
+// for the SRPrjn type, using the recv SRErr successor representation error
+func (pj *PrjnParams) DWtSynSR(ctx *Context, syni, si, ri, di uint32, layPool, subPool *Pool) {
+	dwt := NrnV(ctx, ri, di, SRErr) * NrnV(ctx, si, di, SpkPrv) // prior trial state act
+	SetSynCaV(ctx, syni, di, DiDWt, pj.Learn.LRate.Eff*dwt)
+}
+
 // DWtSynMatrix computes the weight change (learning) at given synapse,
 // for the MatrixPrjn type.
 func (pj *PrjnParams) DWtSynMatrix(ctx *Context, syni, si, ri, di uint32, layPool, subPool *Pool) {
@@ -548,11 +574,12 @@ func (pj *PrjnParams) DWtSynVSPatch(ctx *Context, syni, si, ri, di uint32, layPo
 ///////////////////////////////////////////////////
 // WtFmDWt
 
-// DWtFmDiDWtSyn updates DWt from data parallel DiDWt values
+// DWtFmDiDWtSyn updates DWt from data parallel DiDWt values,
+// weighted by the LearnWt global for each data index.
 func (pj *PrjnParams) DWtFmDiDWtSyn(ctx *Context, syni uint32) {
 	dwt := float32(0)
 	for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
-		dwt += SynCaV(ctx, syni, di, DiDWt)
+		dwt += GlbV(ctx, di, GvLearnWt) * SynCaV(ctx, syni, di, DiDWt)
 	}
 	AddSynV(ctx, syni, DWt, dwt)
 }
@@ -564,6 +591,8 @@ func (pj *PrjnParams) WtFmDWtSyn(ctx *Context, syni uint32) {
 		pj.WtFmDWtSynNoLimits(ctx, syni)
 	case TDPredPrjn:
 		pj.WtFmDWtSynNoLimits(ctx, syni)
+	case SRPrjn:
+		pj.WtFmDWtSynNoLimits(ctx, syni)
 	case BLAPrjn:
 		pj.WtFmDWtSynNoLimits(ctx, syni)
 	case HipPrjn:
@@ -0,0 +1,220 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/anthonynsimon/bild/imgio"
+	"github.com/anthonynsimon/bild/transform"
+	"github.com/emer/emergent/prjn"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/etable/minmax"
+)
+
+// RFGapColor is the color of the gaps between the receptive field tiles
+// in images generated by RFTilesImage.
+var RFGapColor = color.RGBA{64, 64, 128, 255}
+
+// shape2D returns the 2D display shape (Y, X) of a 2D or 4D layer shape,
+// with the units within pools nested within the pools for 4D.
+func shape2D(shp *etensor.Shape) (ny, nx int) {
+	if shp.NumDims() == 4 {
+		return shp.Dim(0) * shp.Dim(2), shp.Dim(1) * shp.Dim(3)
+	}
+	return shp.Dim(0), shp.Dim(1)
+}
+
+// pos2D returns the 2D display position (Y, X) of given 1D unit index
+// within a 2D or 4D layer shape, consistent with shape2D.
+func pos2D(shp *etensor.Shape, idx int) (y, x int) {
+	if shp.NumDims() == 4 {
+		nu := shp.Dim(2) * shp.Dim(3)
+		pi := idx / nu
+		ui := idx % nu
+		return (pi/shp.Dim(1))*shp.Dim(2) + ui/shp.Dim(3), (pi%shp.Dim(1))*shp.Dim(3) + ui%shp.Dim(3)
+	}
+	return idx / shp.Dim(1), idx % shp.Dim(1)
+}
+
+// rfPoolTile returns the PoolTile pattern if this projection uses a
+// (non-reciprocal) prjn.PoolTile from a 4D sending layer, else nil.
+func (pj *PrjnBase) rfPoolTile() *prjn.PoolTile {
+	pt, ok := pj.Pat.(*prjn.PoolTile)
+	if !ok || pt.Recip || pj.Send.Shape().NumDims() != 4 {
+		return nil
+	}
+	return pt
+}
+
+// RFShape returns the 2D shape (Y, X) of the receptive field tile
+// for each receiving unit, in sending units.  For a prjn.PoolTile
+// projection from a 4D sending layer, this is the tile Size in pools
+// times the shape of the sending pools, so that each receiving unit's
+// weights are shown relative to its own position in the topography.
+// Otherwise, it is the 2D display shape of the entire sending layer.
+func (pj *PrjnBase) RFShape() (ny, nx int) {
+	ss := pj.Send.Shape()
+	if pt := pj.rfPoolTile(); pt != nil {
+		return pt.Size.Y * ss.Dim(2), pt.Size.X * ss.Dim(3)
+	}
+	return shape2D(ss)
+}
+
+// RFPos returns the position (Y, X) of given sending unit index
+// within the receptive field tile of given receiving unit index
+// (layer-relative indexes), as described in RFShape.
+// Returns false if the sending unit is outside the tile.
+func (pj *PrjnBase) RFPos(ri, si int) (y, x int, ok bool) {
+	ss := pj.Send.Shape()
+	pt := pj.rfPoolTile()
+	if pt == nil {
+		y, x = pos2D(ss, si)
+		return y, x, true
+	}
+	rs := pj.Recv.Shape()
+	rpy, rpx := 0, 0
+	if rs.NumDims() == 4 {
+		rpi := ri / (rs.Dim(2) * rs.Dim(3))
+		rpy, rpx = rpi/rs.Dim(1), rpi%rs.Dim(1)
+	}
+	snu := ss.Dim(2) * ss.Dim(3)
+	spi := si / snu
+	sui := si % snu
+	fy := spi/ss.Dim(1) - (pt.Start.Y + rpy*pt.Skip.Y)
+	fx := spi%ss.Dim(1) - (pt.Start.X + rpx*pt.Skip.X)
+	if pt.Wrap {
+		fy = ((fy % ss.Dim(0)) + ss.Dim(0)) % ss.Dim(0)
+		fx = ((fx % ss.Dim(1)) + ss.Dim(1)) % ss.Dim(1)
+	}
+	if fy < 0 || fy >= pt.Size.Y || fx < 0 || fx >= pt.Size.X {
+		return 0, 0, false
+	}
+	return fy*ss.Dim(2) + sui/ss.Dim(3), fx*ss.Dim(3) + sui%ss.Dim(3), true
+}
+
+// RFTiles assembles the receiving-field values of given synaptic variable
+// (e.g., Wt, LWt, SWt) for all units in the receiving layer into given
+// tensor, with shape: [RecvY, RecvX, RFY, RFX], where RecvY, RecvX is
+// the 2D display shape of the receiving layer (pools and units within
+// pools for 4D layers), and RFY, RFX is the RFShape.
+// Positions without a synapse are set to 0.
+func (pj *PrjnBase) RFTiles(tsr *etensor.Float32, varNm string) error {
+	vidx, err := pj.AxonPrj.SynVarIdx(varNm)
+	if err != nil {
+		return err
+	}
+	ctx := &pj.Recv.Network.Ctx
+	rs := pj.Recv.Shape()
+	ry, rx := shape2D(rs)
+	ny, nx := pj.RFShape()
+	tsr.SetShape([]int{ry, rx, ny, nx}, nil, []string{"RecvY", "RecvX", "RFY", "RFX"})
+	tsr.SetZeros()
+	for ri := 0; ri < int(pj.Recv.NNeurons); ri++ {
+		ryi, rxi := pos2D(rs, ri)
+		for _, syi := range pj.RecvSynIdxs(uint32(ri)) {
+			si := int(SynI(ctx, pj.SynStIdx+syi, SynSendIdx) - pj.Send.NeurStIdx)
+			y, x, ok := pj.RFPos(ri, si)
+			if !ok {
+				continue
+			}
+			tsr.Set([]int{ryi, rxi, y, x}, pj.AxonPrj.SynVal1D(vidx, int(syi)))
+		}
+	}
+	return nil
+}
+
+// RFImage returns an image of the receiving-field tiles of given
+// synaptic variable for all receiving units (see RFTiles and RFTilesImage).
+func (pj *PrjnBase) RFImage(varNm string, tileNorm bool, scale int) (*image.RGBA, error) {
+	tsr := &etensor.Float32{}
+	if err := pj.RFTiles(tsr, varNm); err != nil {
+		return nil, err
+	}
+	rs := pj.Recv.Shape()
+	if rs.NumDims() == 4 {
+		return RFTilesImage(tsr, rs.Dim(2), rs.Dim(3), tileNorm, scale), nil
+	}
+	return RFTilesImage(tsr, 0, 0, tileNorm, scale), nil
+}
+
+// SaveRFImage saves an image of the receiving-field tiles of given
+// synaptic variable for all receiving units to given file name,
+// in PNG format (see RFImage).
+func (pj *PrjnBase) SaveRFImage(fname, varNm string, tileNorm bool, scale int) error {
+	img, err := pj.RFImage(varNm, tileNorm, scale)
+	if err != nil {
+		return err
+	}
+	if err := imgio.Save(fname, img, imgio.PNGEncoder()); err != nil {
+		return fmt.Errorf("SaveRFImage: %s: %w", pj.Name(), err)
+	}
+	return nil
+}
+
+// RFTilesImage returns a grayscale montage image of the receptive field
+// tiles in given tensor, as generated by RFTiles, with one tile per
+// receiving unit separated by a 1 pixel gap (in RFGapColor), and a 3
+// pixel gap between receiving pools of poolY x poolX units
+// (0 = no pools).  Values are mapped from min (black) to max (white)
+// over the entire tensor, or within each tile if tileNorm is true.
+// If scale > 1, the image is scaled up by that factor, with
+// nearest-neighbor interpolation.
+func RFTilesImage(tsr *etensor.Float32, poolY, poolX int, tileNorm bool, scale int) *image.RGBA {
+	ry, rx, ny, nx := tsr.Dim(0), tsr.Dim(1), tsr.Dim(2), tsr.Dim(3)
+	gapPos := func(i, np, n int) int { // start of tile i
+		p := i * (n + 1)
+		if np > 0 {
+			p += (i / np) * 2
+		}
+		return p + 1
+	}
+	ht := gapPos(ry, poolY, ny)
+	wd := gapPos(rx, poolX, nx)
+	if poolY > 0 && ry%poolY == 0 {
+		ht -= 2
+	}
+	if poolX > 0 && rx%poolX == 0 {
+		wd -= 2
+	}
+	img := image.NewRGBA(image.Rect(0, 0, wd, ht))
+	for y := 0; y < ht; y++ {
+		for x := 0; x < wd; x++ {
+			img.Set(x, y, RFGapColor)
+		}
+	}
+	tsz := ny * nx
+	var mm minmax.F32
+	if !tileNorm {
+		mm.SetInfinity()
+		for _, v := range tsr.Values {
+			mm.FitValInRange(v)
+		}
+	}
+	for ti := 0; ti < ry*rx; ti++ {
+		vals := tsr.Values[ti*tsz : (ti+1)*tsz]
+		if tileNorm {
+			mm.SetInfinity()
+			for _, v := range vals {
+				mm.FitValInRange(v)
+			}
+		}
+		ty := gapPos(ti/rx, poolY, ny)
+		tx := gapPos(ti%rx, poolX, nx)
+		for i, v := range vals {
+			g := uint8(0)
+			if mm.Range() > 0 {
+				g = uint8(255 * mm.NormVal(v))
+			}
+			img.Set(tx+i%nx, ty+i/nx, color.RGBA{g, g, g, 255})
+		}
+	}
+	if scale > 1 {
+		return transform.Resize(img, wd*scale, ht*scale, transform.NearestNeighbor)
+	}
+	return img
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/anthonynsimon/bild/imgio"
+	"github.com/emer/emergent/prjn"
+	"github.com/emer/etable/etensor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrjnRFTiles(t *testing.T) {
+	ctx := NewContext()
+	net := NewNetwork("RF")
+	inp := net.AddLayer4D("Input", 4, 4, 2, 2, InputLayer)
+	hid := net.AddLayer4D("Hidden", 2, 2, 1, 2, SuperLayer)
+	out := net.AddLayer2D("Output", 2, 3, TargetLayer)
+	pt := prjn.NewPoolTile()
+	pt.Size.Set(2, 2)
+	pt.Skip.Set(2, 2)
+	pt.Start.Set(0, 0)
+	tpj := net.ConnectLayers(inp, hid, pt, ForwardPrjn)
+	fpj := net.ConnectLayers(hid, out, prjn.NewFull(), ForwardPrjn)
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	net.InitWts(ctx)
+
+	// set weights to encode the sending unit index
+	for _, pj := range []*Prjn{tpj, fpj} {
+		for syi := uint32(0); syi < pj.NSyns; syi++ {
+			syni := pj.SynStIdx + syi
+			si := SynI(ctx, syni, SynSendIdx) - pj.Send.NeurStIdx
+			SetSynV(ctx, syni, Wt, float32(si))
+		}
+	}
+
+	ny, nx := tpj.RFShape()
+	assert.Equal(t, 4, ny)
+	assert.Equal(t, 4, nx)
+	tsr := &etensor.Float32{}
+	assert.NoError(t, tpj.RFTiles(tsr, "Wt"))
+	assert.Equal(t, []int{2, 4, 4, 4}, tsr.Shapes())
+	// recv pool 1 (y=0, x=1), unit 1: sends from pools (0,2),(0,3),(1,2),(1,3)
+	// RF position (1, 2) = send pool (0, 3), unit (1, 0) = 3*4 + 2 = 14
+	assert.Equal(t, float32(14), tsr.Value([]int{0, 3, 1, 2}))
+	// RF position (3, 0) = send pool (1, 2), unit (1, 0) = 6*4 + 2 = 26
+	assert.Equal(t, float32(26), tsr.Value([]int{0, 3, 3, 0}))
+	// recv pool 2 (y=1, x=0): RF (0, 0) = send pool (2, 0), unit 0 = 32
+	assert.Equal(t, float32(32), tsr.Value([]int{1, 0, 0, 0}))
+
+	ny, nx = fpj.RFShape()
+	assert.Equal(t, 2, ny)
+	assert.Equal(t, 4, nx)
+	assert.NoError(t, fpj.RFTiles(tsr, "Wt"))
+	assert.Equal(t, []int{2, 3, 2, 4}, tsr.Shapes())
+	// hidden unit 5 = pool 2 (y=1, x=0), unit 1 -> display (1, 1)
+	assert.Equal(t, float32(5), tsr.Value([]int{1, 2, 1, 1}))
+	assert.Error(t, fpj.RFTiles(tsr, "NotAVar"))
+
+	img, err := tpj.RFImage("Wt", false, 1)
+	assert.NoError(t, err)
+	// 2 recv rows in 2 pools: 1 + 2*(4+1) + 2; 4 recv cols in 2 pools: 1 + 4*(4+1) + 2
+	assert.Equal(t, 13, img.Bounds().Dy())
+	assert.Equal(t, 23, img.Bounds().Dx())
+	assert.Equal(t, RFGapColor, img.RGBAAt(0, 0))
+	assert.Equal(t, RFGapColor, img.RGBAAt(11, 1)) // pool gap
+	assert.Equal(t, uint8(0), img.RGBAAt(1, 1).R)  // send unit 0 is the min
+
+	fnm := filepath.Join(t.TempDir(), "rf.png")
+	assert.NoError(t, tpj.SaveRFImage(fnm, "SWt", true, 3))
+	rimg, err := imgio.Open(fnm)
+	assert.NoError(t, err)
+	assert.Equal(t, 39, rimg.Bounds().Dy())
+	assert.Equal(t, 69, rimg.Bounds().Dx())
+}
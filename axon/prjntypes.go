@@ -49,7 +49,8 @@ const (
 	RWPrjn
 
 	// TDPredPrjn does dopamine-modulated learning for reward prediction:
-	// DWt = Da * Send.SpkPrv (activity on *previous* timestep)
+	// DWt = Da * Send.SpkPrv (activity on *previous* timestep),
+	// or an eligibility trace of it for TD(lambda) when RLPred.Lambda > 0.
 	// Uses RLPredPrjn parameters.
 	// Use in TDPredLayer typically to generate reward predictions.
 	// If the Da sign is positive, the first recv unit learns fully;
@@ -57,6 +58,12 @@ const (
 	// opposite cases.  Weights are positive-only.
 	TDPredPrjn
 
+	// SRPrjn does successor representation (SR) learning in an SRPredLayer:
+	// DWt = Recv.SRErr * Send.SpkPrv (state activity on *previous* timestep),
+	// where SRErr is the TD-like error on discounted future state occupancy.
+	// Uses RLPredPrjn parameters, and weights have no limits.
+	SRPrjn
+
 	// BLAPrjn implements the PVLV BLA learning rule:
 	// dW = ACh * X_t-1 * (Y_t - Y_t-1)
 	// The recv delta is across trials, where the US should activate on trial
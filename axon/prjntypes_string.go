@@ -20,16 +20,17 @@ func _() {
 	_ = x[CTCtxtPrjn-4]
 	_ = x[RWPrjn-5]
 	_ = x[TDPredPrjn-6]
-	_ = x[BLAPrjn-7]
-	_ = x[HipPrjn-8]
-	_ = x[VSPatchPrjn-9]
-	_ = x[MatrixPrjn-10]
-	_ = x[PrjnTypesN-11]
+	_ = x[SRPrjn-7]
+	_ = x[BLAPrjn-8]
+	_ = x[HipPrjn-9]
+	_ = x[VSPatchPrjn-10]
+	_ = x[MatrixPrjn-11]
+	_ = x[PrjnTypesN-12]
 }
 
-const _PrjnTypes_name = "ForwardPrjnBackPrjnLateralPrjnInhibPrjnCTCtxtPrjnRWPrjnTDPredPrjnBLAPrjnHipPrjnVSPatchPrjnMatrixPrjnPrjnTypesN"
+const _PrjnTypes_name = "ForwardPrjnBackPrjnLateralPrjnInhibPrjnCTCtxtPrjnRWPrjnTDPredPrjnSRPrjnBLAPrjnHipPrjnVSPatchPrjnMatrixPrjnPrjnTypesN"
 
-var _PrjnTypes_index = [...]uint8{0, 11, 19, 30, 39, 49, 55, 65, 72, 79, 90, 100, 110}
+var _PrjnTypes_index = [...]uint8{0, 11, 19, 30, 39, 49, 55, 65, 71, 78, 85, 96, 106, 116}
 
 func (i PrjnTypes) String() string {
 	if i < 0 || i >= PrjnTypes(len(_PrjnTypes_index)-1) {
@@ -55,12 +56,13 @@ var _PrjnTypes_descMap = map[PrjnTypes]string{
 	3:  `Inhib is an inhibitory projection that drives inhibitory synaptic conductances instead of the default excitatory ones.`,
 	4:  `CTCtxt are projections from Superficial layers to CT layers that send Burst activations drive updating of CtxtGe excitatory conductance, at end of plus (51B Bursting) phase. Biologically, this projection comes from the PT layer 5IB neurons, but it is simpler to use the Super neurons directly, and PT are optional for most network types. These projections also use a special learning rule that takes into account the temporal delays in the activation states. Can also add self context from CT for deeper temporal context.`,
 	5:  `RWPrjn does dopamine-modulated learning for reward prediction: Da * Send.CaSpkP (integrated current spiking activity). Uses RLPredPrjn parameters. Use in RWPredLayer typically to generate reward predictions. If the Da sign is positive, the first recv unit learns fully; for negative, second one learns fully. Lower lrate applies for opposite cases. Weights are positive-only.`,
-	6:  `TDPredPrjn does dopamine-modulated learning for reward prediction: DWt = Da * Send.SpkPrv (activity on *previous* timestep) Uses RLPredPrjn parameters. Use in TDPredLayer typically to generate reward predictions. If the Da sign is positive, the first recv unit learns fully; for negative, second one learns fully. Lower lrate applies for opposite cases. Weights are positive-only.`,
-	7:  `BLAPrjn implements the PVLV BLA learning rule: dW = ACh * X_t-1 * (Y_t - Y_t-1) The recv delta is across trials, where the US should activate on trial boundary, to enable sufficient time for gating through to OFC, so BLA initially learns based on US present - US absent. It can also learn based on CS onset if there is a prior CS that predicts that.`,
-	8:  ``,
-	9:  `VSPatchPrjn implements the VSPatch learning rule: dW = ACh * DA * X * Y where DA is D1 vs. D2 modulated DA level, X = sending activity factor, Y = receiving activity factor, and ACh provides overall modulation.`,
-	10: `MatrixPrjn supports trace-based learning, where an initial trace of synaptic co-activity is formed, and then modulated by subsequent phasic dopamine &amp; ACh when an outcome occurs. This bridges the temporal gap between gating activity and subsequent outcomes, and is based biologically on synaptic tags. Trace is reset at time of reward based on ACh level (from CINs in biology).`,
-	11: ``,
+	6:  `TDPredPrjn does dopamine-modulated learning for reward prediction: DWt = Da * Send.SpkPrv (activity on *previous* timestep), or an eligibility trace of it for TD(lambda) when RLPred.Lambda &gt; 0. Uses RLPredPrjn parameters. Use in TDPredLayer typically to generate reward predictions. If the Da sign is positive, the first recv unit learns fully; for negative, second one learns fully. Lower lrate applies for opposite cases. Weights are positive-only.`,
+	7:  `SRPrjn does successor representation (SR) learning in an SRPredLayer: DWt = Recv.SRErr * Send.SpkPrv (state activity on *previous* timestep), where SRErr is the TD-like error on discounted future state occupancy. Uses RLPredPrjn parameters, and weights have no limits.`,
+	8:  `BLAPrjn implements the PVLV BLA learning rule: dW = ACh * X_t-1 * (Y_t - Y_t-1) The recv delta is across trials, where the US should activate on trial boundary, to enable sufficient time for gating through to OFC, so BLA initially learns based on US present - US absent. It can also learn based on CS onset if there is a prior CS that predicts that.`,
+	9:  ``,
+	10: `VSPatchPrjn implements the VSPatch learning rule: dW = ACh * DA * X * Y where DA is D1 vs. D2 modulated DA level, X = sending activity factor, Y = receiving activity factor, and ACh provides overall modulation.`,
+	11: `MatrixPrjn supports trace-based learning, where an initial trace of synaptic co-activity is formed, and then modulated by subsequent phasic dopamine &amp; ACh when an outcome occurs. This bridges the temporal gap between gating activity and subsequent outcomes, and is based biologically on synaptic tags. Trace is reset at time of reward based on ACh level (from CINs in biology).`,
+	12: ``,
 }
 
 func (i PrjnTypes) Desc() string {
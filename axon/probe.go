@@ -0,0 +1,405 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/emer/emergent/elog"
+	"github.com/emer/emergent/etime"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/etable/minmax"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+//go:generate stringer -type=ProbeMethods
+
+var KiT_ProbeMethods = kit.Enums.AddEnum(ProbeMethodsN, kit.NotBitFlag, nil)
+
+// ProbeMethods are the methods for learning the readout weights of a Probe
+type ProbeMethods int32
+
+const (
+	// ProbeSGD: softmax (multinomial logistic) readout, with weights
+	// learned online by stochastic gradient descent on each trial
+	ProbeSGD ProbeMethods = iota
+
+	// ProbeRidge: linear readout of one-hot category targets, with weights
+	// fit by ridge regression on all of the trials trained so far,
+	// solved at the end of each epoch (EpochEnd)
+	ProbeRidge
+
+	ProbeMethodsN
+)
+
+// ProbeParams are parameters for a Probe
+type ProbeParams struct {
+
+	// method for learning the readout weights
+	Method ProbeMethods `desc:"method for learning the readout weights"`
+
+	// [def: ActM,CaSpkP] neuron variable to read from the layers
+	Var string `def:"ActM,CaSpkP" desc:"neuron variable to read from the layers"`
+
+	// [def: 0.1] [viewif: Method=ProbeSGD] learning rate for SGD
+	Lrate float32 `def:"0.1" viewif:"Method=ProbeSGD" desc:"learning rate for SGD"`
+
+	// [def: 1] [viewif: Method=ProbeRidge] ridge regularization penalty on the squared weights (not the bias)
+	Lambda float32 `def:"1" viewif:"Method=ProbeRidge" desc:"ridge regularization penalty on the squared weights (not the bias)"`
+}
+
+func (pp *ProbeParams) Defaults() {
+	pp.Method = ProbeSGD
+	pp.Var = "ActM"
+	pp.Lrate = 0.1
+	pp.Lambda = 1
+}
+
+func (pp *ProbeParams) Update() {
+}
+
+// Probe is a linear decoding probe that learns to read out category
+// labels from the activity of one or more layers, for assessing what
+// those layers represent.  It only reads the neuron variable (ActM by
+// default) for each data index, and does not affect the network
+// dynamics or weights in any way.  Call Step after the minus phase
+// (or at the end of the trial) with the labels for each data index,
+// and EpochEnd at the end of each epoch, prior to logging the
+// accuracy (see LogAddProbeItems).  When running on the GPU, the
+// neuron state must be synced back to the CPU before calling Step,
+// as for logging.
+type Probe struct {
+
+	// name of the probe, used in the log item names
+	Name string `desc:"name of the probe, used in the log item names"`
+
+	// names of the layers to read from, concatenated into one input vector
+	Layers []string `desc:"names of the layers to read from, concatenated into one input vector"`
+
+	// number of categories to decode
+	NCats int `desc:"number of categories to decode"`
+
+	// probe parameters
+	Params ProbeParams `view:"inline" desc:"probe parameters"`
+
+	// [view: -] total number of input units across the layers
+	NIn int `view:"-" desc:"total number of input units across the layers"`
+
+	// [view: -] readout weights: [NCats][NIn+1], with the bias as the last value for each category
+	Wts []float32 `view:"-" desc:"readout weights: [NCats][NIn+1], with the bias as the last value for each category"`
+
+	// [view: -] for ProbeRidge, sum of the outer products of the inputs: [NIn+1][NIn+1]
+	XtX []float64 `view:"-" desc:"for ProbeRidge, sum of the outer products of the inputs: [NIn+1][NIn+1]"`
+
+	// [view: -] for ProbeRidge, sum of the products of the inputs and one-hot targets: [NIn+1][NCats]
+	XtY []float64 `view:"-" desc:"for ProbeRidge, sum of the products of the inputs and one-hot targets: [NIn+1][NCats]"`
+
+	// [view: -] for ProbeRidge, number of trials accumulated in XtX, XtY
+	NSamples int `view:"-" desc:"for ProbeRidge, number of trials accumulated in XtX, XtY"`
+
+	// [view: -] inputs for the current data index, with a 1 for the bias as the last value
+	In []float32 `view:"-" desc:"inputs for the current data index, with a 1 for the bias as the last value"`
+
+	// [view: -] outputs for each category for the current data index -- softmax probabilities for ProbeSGD
+	Out []float32 `view:"-" desc:"outputs for each category for the current data index -- softmax probabilities for ProbeSGD"`
+
+	// [view: -] decoded category for each data index on the last Step
+	Resp []int `view:"-" desc:"decoded category for each data index on the last Step"`
+
+	// [view: -] number of trials decoded in the current epoch
+	NTrials int `view:"-" desc:"number of trials decoded in the current epoch"`
+
+	// [view: -] number of trials decoded correctly in the current epoch
+	NCorrect int `view:"-" desc:"number of trials decoded correctly in the current epoch"`
+
+	// proportion of trials decoded correctly over the last epoch, as of EpochEnd
+	Acc float32 `inactive:"+" desc:"proportion of trials decoded correctly over the last epoch, as of EpochEnd"`
+
+	// [view: -] temp values
+	Vals []float32 `view:"-" desc:"temp values"`
+}
+
+// NewProbe returns a new Probe with given name, number of categories,
+// reading from given layers, with default parameters, initialized
+// for this network.
+func (nt *Network) NewProbe(name string, ncats int, layers ...string) (*Probe, error) {
+	pr := &Probe{Name: name, NCats: ncats, Layers: layers}
+	pr.Params.Defaults()
+	err := pr.Init(nt)
+	return pr, err
+}
+
+// Init initializes the probe for the Layers in given network,
+// and initializes the weights.
+func (pr *Probe) Init(net *Network) error {
+	if pr.NCats < 2 {
+		return fmt.Errorf("Probe %s: NCats must be at least 2, is: %d", pr.Name, pr.NCats)
+	}
+	pr.NIn = 0
+	for _, lnm := range pr.Layers {
+		ely, err := net.LayerByNameTry(lnm)
+		if err != nil {
+			return err
+		}
+		pr.NIn += ely.(AxonLayer).AsAxon().Shape().Len()
+	}
+	if _, err := NeuronVarIdxByName(pr.Params.Var); err != nil {
+		return err
+	}
+	nw := pr.NIn + 1
+	pr.Wts = make([]float32, pr.NCats*nw)
+	pr.XtX = make([]float64, nw*nw)
+	pr.XtY = make([]float64, nw*pr.NCats)
+	pr.In = make([]float32, nw)
+	pr.Out = make([]float32, pr.NCats)
+	pr.Resp = make([]int, net.MaxData)
+	pr.InitWts()
+	return nil
+}
+
+// InitWts initializes the weights to zero, and resets the accumulated
+// ridge regression data and epoch counts.
+func (pr *Probe) InitWts() {
+	for i := range pr.Wts {
+		pr.Wts[i] = 0
+	}
+	for i := range pr.XtX {
+		pr.XtX[i] = 0
+	}
+	for i := range pr.XtY {
+		pr.XtY[i] = 0
+	}
+	pr.NSamples = 0
+	pr.NTrials = 0
+	pr.NCorrect = 0
+	pr.Acc = 0
+}
+
+// Input reads the inputs from the layers for given data index
+func (pr *Probe) Input(net *Network, di int) error {
+	idx := 0
+	for _, lnm := range pr.Layers {
+		ely, err := net.LayerByNameTry(lnm)
+		if err != nil {
+			return err
+		}
+		if err := ely.(AxonLayer).AsAxon().UnitVals(&pr.Vals, pr.Params.Var, di); err != nil {
+			return err
+		}
+		for _, v := range pr.Vals {
+			if mat32.IsNaN(v) {
+				v = 0
+			}
+			pr.In[idx] = v
+			idx++
+		}
+	}
+	pr.In[pr.NIn] = 1
+	return nil
+}
+
+// Forward computes the outputs from the current inputs,
+// returning the category with the maximum output.
+func (pr *Probe) Forward() int {
+	nw := pr.NIn + 1
+	mx := -1
+	for c := 0; c < pr.NCats; c++ {
+		wts := pr.Wts[c*nw : (c+1)*nw]
+		sum := float32(0)
+		for i, in := range pr.In {
+			sum += wts[i] * in
+		}
+		pr.Out[c] = sum
+		if mx < 0 || sum > pr.Out[mx] {
+			mx = c
+		}
+	}
+	if pr.Params.Method == ProbeSGD {
+		max := pr.Out[mx]
+		sum := float32(0)
+		for c, o := range pr.Out {
+			e := mat32.Exp(o - max)
+			pr.Out[c] = e
+			sum += e
+		}
+		for c := range pr.Out {
+			pr.Out[c] /= sum
+		}
+	}
+	return mx
+}
+
+// Decode returns the decoded category for given data index
+func (pr *Probe) Decode(net *Network, di int) (int, error) {
+	if err := pr.Input(net, di); err != nil {
+		return -1, err
+	}
+	return pr.Forward(), nil
+}
+
+// Train trains the readout on the current inputs and outputs
+// (after Decode), for given target category.  For ProbeSGD the weights
+// are updated immediately, while for ProbeRidge the data is accumulated
+// and the weights are solved at EpochEnd.
+func (pr *Probe) Train(targ int) {
+	nw := pr.NIn + 1
+	switch pr.Params.Method {
+	case ProbeSGD:
+		for c := 0; c < pr.NCats; c++ {
+			trg := float32(0)
+			if c == targ {
+				trg = 1
+			}
+			err := pr.Params.Lrate * (trg - pr.Out[c])
+			wts := pr.Wts[c*nw : (c+1)*nw]
+			for i, in := range pr.In {
+				wts[i] += err * in
+			}
+		}
+	case ProbeRidge:
+		for i, ini := range pr.In {
+			xi := float64(ini)
+			if xi == 0 {
+				continue
+			}
+			xtx := pr.XtX[i*nw : (i+1)*nw]
+			for j, inj := range pr.In {
+				xtx[j] += xi * float64(inj)
+			}
+			pr.XtY[i*pr.NCats+targ] += xi
+		}
+		pr.NSamples++
+	}
+}
+
+// Step decodes the category for each data index, recording the
+// accuracy against the given labels for each data index (-1 = skip),
+// and trains the readout on the labels if train is true.
+func (pr *Probe) Step(ctx *Context, net *Network, labels []int, train bool) error {
+	for di := 0; di < int(ctx.NetIdxs.NData); di++ {
+		cat, err := pr.Decode(net, di)
+		if err != nil {
+			return err
+		}
+		pr.Resp[di] = cat
+		lbl := labels[di]
+		if lbl < 0 {
+			continue
+		}
+		if lbl >= pr.NCats {
+			return fmt.Errorf("Probe %s: label %d is out of range for NCats: %d", pr.Name, lbl, pr.NCats)
+		}
+		pr.NTrials++
+		if cat == lbl {
+			pr.NCorrect++
+		}
+		if train {
+			pr.Train(lbl)
+		}
+	}
+	return nil
+}
+
+// EpochEnd computes the accuracy over the current epoch into Acc,
+// and resets the epoch counts.  For ProbeRidge, it also solves for the
+// weights from all the data trained so far.
+func (pr *Probe) EpochEnd() error {
+	if pr.NTrials > 0 {
+		pr.Acc = float32(pr.NCorrect) / float32(pr.NTrials)
+	}
+	pr.NTrials = 0
+	pr.NCorrect = 0
+	if pr.Params.Method == ProbeRidge && pr.NSamples > 0 {
+		return pr.SolveRidge()
+	}
+	return nil
+}
+
+// SolveRidge solves for the ProbeRidge weights from the accumulated
+// data: Wts = (XtX + Lambda I)^-1 XtY, with no penalty on the bias.
+func (pr *Probe) SolveRidge() error {
+	nw := pr.NIn + 1
+	a := make([]float64, len(pr.XtX))
+	copy(a, pr.XtX)
+	for i := 0; i < pr.NIn; i++ {
+		a[i*nw+i] += float64(pr.Params.Lambda)
+	}
+	b := make([]float64, len(pr.XtY))
+	copy(b, pr.XtY)
+	if err := probeSolve(a, b, nw, pr.NCats); err != nil {
+		return fmt.Errorf("Probe %s: %w", pr.Name, err)
+	}
+	for c := 0; c < pr.NCats; c++ {
+		for i := 0; i < nw; i++ {
+			pr.Wts[c*nw+i] = float32(b[i*pr.NCats+c])
+		}
+	}
+	return nil
+}
+
+// probeSolve solves a x = b in place by Gaussian elimination with partial
+// pivoting, for n x n matrix a and n x m matrix b, leaving the result in b.
+func probeSolve(a, b []float64, n, m int) error {
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a[i*n+k]) > math.Abs(a[p*n+k]) {
+				p = i
+			}
+		}
+		if math.Abs(a[p*n+k]) < 1.0e-12 {
+			return fmt.Errorf("ridge regression matrix is singular -- increase Lambda")
+		}
+		if p != k {
+			for j := 0; j < n; j++ {
+				a[k*n+j], a[p*n+j] = a[p*n+j], a[k*n+j]
+			}
+			for j := 0; j < m; j++ {
+				b[k*m+j], b[p*m+j] = b[p*m+j], b[k*m+j]
+			}
+		}
+		for i := k + 1; i < n; i++ {
+			f := a[i*n+k] / a[k*n+k]
+			if f == 0 {
+				continue
+			}
+			for j := k; j < n; j++ {
+				a[i*n+j] -= f * a[k*n+j]
+			}
+			for j := 0; j < m; j++ {
+				b[i*m+j] -= f * b[k*m+j]
+			}
+		}
+	}
+	for k := n - 1; k >= 0; k-- {
+		for j := 0; j < m; j++ {
+			sum := b[k*m+j]
+			for i := k + 1; i < n; i++ {
+				sum -= a[k*n+i] * b[i*m+j]
+			}
+			b[k*m+j] = sum / a[k*n+k]
+		}
+	}
+	return nil
+}
+
+// LogAddProbeItems adds an item to given logs for the accuracy of
+// each probe (Name_ProbeAcc), at given mode and time scale
+// (e.g., Train, Epoch).  EpochEnd must be called prior to logging.
+func LogAddProbeItems(lg *elog.Logs, mode etime.Modes, etm etime.Times, prbs ...*Probe) {
+	for _, pr := range prbs {
+		cpr := pr
+		lg.AddItem(&elog.Item{
+			Name:  cpr.Name + "_ProbeAcc",
+			Type:  etensor.FLOAT64,
+			Range: minmax.F64{Max: 1},
+			Write: elog.WriteMap{
+				etime.Scope(mode, etm): func(ctx *elog.Context) {
+					ctx.SetFloat32(cpr.Acc)
+				}}})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/elog"
+	"github.com/emer/emergent/estats"
+	"github.com/emer/emergent/etime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbe(t *testing.T) {
+	net, ctx := newSettleTestNet(t)
+	sgd, err := net.NewProbe("SGD", 4, "Hidden")
+	assert.NoError(t, err)
+	sgd.Params.Var = "CaSpkP"
+	ridge, err := net.NewProbe("Ridge", 4, "Input", "Hidden")
+	assert.NoError(t, err)
+	ridge.Params.Var = "CaSpkP"
+	ridge.Params.Method = ProbeRidge
+	ridge.Params.Lambda = 0.01
+	assert.Equal(t, 8, ridge.NIn)
+
+	hid := net.AxonLayerByName("Hidden")
+	var wts []float32
+	hid.RcvPrjns[0].SynVals(&wts, "Wt")
+
+	epcs := []float32{}
+	rdgs := []float32{}
+	for epc := 0; epc < 10; epc++ {
+		for trl := 0; trl < 2; trl++ {
+			ons := []int{2 * trl, 2*trl + 1}
+			runSettleTrial(net, ctx, ons)
+			assert.NoError(t, sgd.Step(ctx, net, ons, true))
+			assert.NoError(t, ridge.Step(ctx, net, ons, true))
+		}
+		assert.NoError(t, sgd.EpochEnd())
+		assert.NoError(t, ridge.EpochEnd())
+		epcs = append(epcs, sgd.Acc)
+		rdgs = append(rdgs, ridge.Acc)
+	}
+	assert.Less(t, epcs[0], float32(1))
+	assert.Equal(t, float32(1), epcs[9])
+	assert.Less(t, rdgs[0], float32(1)) // not solved until EpochEnd
+	assert.Equal(t, float32(1), rdgs[1])
+	assert.Equal(t, []int{2, 3}, sgd.Resp[:2])
+
+	// probes do not change the network weights
+	var nwts []float32
+	hid.RcvPrjns[0].SynVals(&nwts, "Wt")
+	assert.Equal(t, wts, nwts)
+
+	lg := &elog.Logs{}
+	LogAddProbeItems(lg, etime.Train, etime.Epoch, sgd, ridge)
+	lg.CreateTables()
+	lg.SetContext(&estats.Stats{}, net)
+	lg.LogRow(etime.Train, etime.Epoch, 0)
+	dt := lg.Table(etime.Train, etime.Epoch)
+	assert.Equal(t, 1.0, dt.CellFloat("SGD_ProbeAcc", 0))
+	assert.Equal(t, 1.0, dt.CellFloat("Ridge_ProbeAcc", 0))
+
+	assert.Error(t, sgd.Step(ctx, net, []int{4, 0}, false))
+	_, err = net.NewProbe("Bad", 4, "NotALayer")
+	assert.Error(t, err)
+	_, err = net.NewProbe("Bad", 1, "Hidden")
+	assert.Error(t, err)
+}
@@ -426,12 +426,24 @@ func LogisticFun(v, gain float32) float32 {
 	return (1.0 / (1.0 + mat32.Exp(-gain*v)))
 }
 
+// Prob returns the probability of giving up as a logistic function of
+// given difference, and whether to give up, sampled from given rnd.
+// PVLV uses ProbCtx, which is reproducible across NThreads and NData.
 func (gp *GiveUpParams) Prob(pvDiff float32, rnd erand.Rand) (float32, bool) {
 	prob := LogisticFun(pvDiff, gp.Gain)
 	giveUp := erand.BoolP32(prob, -1, rnd)
 	return prob, giveUp
 }
 
+// ProbCtx returns the probability of giving up as a logistic function of
+// given difference, and whether to give up, sampled using the RandFunGiveUp
+// random stream for given data index.
+func (gp *GiveUpParams) ProbCtx(ctx *Context, di uint32, pvDiff float32) (float32, bool) {
+	prob := LogisticFun(pvDiff, gp.Gain)
+	giveUp := GetRandomNumber(di, ctx.RandCtr, RandFunGiveUp) < prob
+	return prob, giveUp
+}
+
 //////////////////////////////////////////////////////////
 //  PVLV
 
@@ -679,7 +691,7 @@ func (pp *PVLV) ResetGiveUp(ctx *Context, di uint32) {
 
 // NewState is called at very start of new state (trial) of processing.
 // sets HadRew = HasRew from last trial -- used to then reset various things
-// after reward.
+// after reward.  rnd is not used.
 func (pp *PVLV) NewState(ctx *Context, di uint32, rnd erand.Rand) {
 	hadRewF := GlbV(ctx, di, GvHasRew)
 	hadRew := bools.FromFloat32(hadRewF)
@@ -862,6 +874,7 @@ func (pp *PVLV) DAFmPVs(pvPos, pvNeg, vsPatchPos float32) (burst, dip, da, rew f
 // GiveUpFmPV determines whether to give up on current goal
 // based on balance between estimated PVpos and accumulated PVneg.
 // returns true if give up triggered.
+// Uses the RandFunGiveUp random stream: rnd is not used.
 func (pp *PVLV) GiveUpFmPV(ctx *Context, di uint32, pvNeg float32, rnd erand.Rand) bool {
 	// now compute give-up
 	posEstSum, posEst := pp.PVposEst(ctx, di)
@@ -869,7 +882,7 @@ func (pp *PVLV) GiveUpFmPV(ctx *Context, di uint32, pvNeg float32, rnd erand.Ran
 	posDisc := posEst - vsPatchSum
 	// note: cannot do ratio here because discounting can get negative
 	diff := posDisc - pvNeg
-	prob, giveUp := pp.GiveUp.Prob(-diff, rnd)
+	prob, giveUp := pp.GiveUp.ProbCtx(ctx, di, -diff)
 
 	SetGlbV(ctx, di, GvPVposEst, posEst)
 	SetGlbV(ctx, di, GvPVposEstSum, posEstSum)
@@ -184,6 +184,106 @@ func (vt *VTAParams) VTADA(ctx *Context, di uint32, ach float32, hasRew bool) {
 	SetGlbV(ctx, di, GvDA, netDA)    // general neuromod DA
 }
 
+// LCParams are for computing the norepinephrine (NE) arousal signal
+// from the locus coeruleus, as a function of Urgency, the magnitude of DA
+// (surprise), and the activity of an optional source layer.
+// NE rises instantly and decays with Tau time constant, like LDT ACh.
+type LCParams struct {
+
+	// [def: 0] tonic baseline level of NE, added to other factors
+	Tonic float32 `def:"0" desc:"tonic baseline level of NE, added to other factors"`
+
+	// [def: 1] gain on Global Urgency -- arousal increases as time passes without obtaining a reward
+	UrgencyGain float32 `def:"1" desc:"gain on Global Urgency -- arousal increases as time passes without obtaining a reward"`
+
+	// [def: 0.5] gain on absolute value of Global DA -- surprising events, positive or negative, drive phasic NE
+	DAGain float32 `def:"0.5" desc:"gain on absolute value of Global DA -- surprising events, positive or negative, drive phasic NE"`
+
+	// [def: 1] gain on activity of the source layer, if present (e.g., a salience or threat layer)
+	SrcGain float32 `def:"1" desc:"gain on activity of the source layer, if present (e.g., a salience or threat layer)"`
+
+	// [def: 10] [min: 1] time constant in cycles for decay of NE after a phasic increase
+	Tau float32 `def:"10" min:"1" desc:"time constant in cycles for decay of NE after a phasic increase"`
+
+	// idx of Layer to get activity from -- set during Build from BuildConfig SrcLayName if present -- -1 if not used
+	SrcLayIdx int32 `inactive:"+" desc:"idx of Layer to get activity from -- set during Build from BuildConfig SrcLayName if present -- -1 if not used"`
+
+	// [view: -] rate = 1 / tau
+	Dt float32 `view:"-" json:"-" xml:"-" desc:"rate = 1 / tau"`
+
+	pad float32
+}
+
+func (lc *LCParams) Defaults() {
+	lc.Tonic = 0
+	lc.UrgencyGain = 1
+	lc.DAGain = 0.5
+	lc.SrcGain = 1
+	lc.Tau = 10
+	lc.Update()
+}
+
+func (lc *LCParams) Update() {
+	lc.Dt = 1 / lc.Tau
+}
+
+// NE returns the raw NE value based on given source layer activation
+// and key values from the ctx Context.
+func (lc *LCParams) NE(ctx *Context, di uint32, srcLayAct float32) float32 {
+	ne := lc.Tonic + lc.UrgencyGain*GlbV(ctx, di, GvUrgency) + lc.DAGain*mat32.Abs(GlbV(ctx, di, GvDA)) + lc.SrcGain*srcLayAct
+	return mat32.Clamp(ne, 0, 1)
+}
+
+// DRNParams are for computing the serotonin (5-HT) signal from the
+// dorsal raphe nucleus, as a function of expected future reward (PVposEst),
+// which promotes patience, aversive outcomes (PVneg), and the activity of
+// an optional source layer.  5-HT integrates slowly with Tau time constant.
+type DRNParams struct {
+
+	// [def: 0] tonic baseline level of 5-HT, added to other factors
+	Tonic float32 `def:"0" desc:"tonic baseline level of 5-HT, added to other factors"`
+
+	// [def: 1] gain on Global PVposEst -- expectation of a future reward sustains 5-HT, supporting patient waiting
+	PVposEstGain float32 `def:"1" desc:"gain on Global PVposEst -- expectation of a future reward sustains 5-HT, supporting patient waiting"`
+
+	// [def: 0.5] gain on Global PVneg -- aversive outcomes including accumulated effort increase 5-HT
+	PVnegGain float32 `def:"0.5" desc:"gain on Global PVneg -- aversive outcomes including accumulated effort increase 5-HT"`
+
+	// [def: 1] gain on activity of the source layer, if present
+	SrcGain float32 `def:"1" desc:"gain on activity of the source layer, if present"`
+
+	// [def: 50] [min: 1] time constant in cycles for integration of 5-HT -- slower than other neuromodulators
+	Tau float32 `def:"50" min:"1" desc:"time constant in cycles for integration of 5-HT -- slower than other neuromodulators"`
+
+	// idx of Layer to get activity from -- set during Build from BuildConfig SrcLayName if present -- -1 if not used
+	SrcLayIdx int32 `inactive:"+" desc:"idx of Layer to get activity from -- set during Build from BuildConfig SrcLayName if present -- -1 if not used"`
+
+	// [view: -] rate = 1 / tau
+	Dt float32 `view:"-" json:"-" xml:"-" desc:"rate = 1 / tau"`
+
+	pad float32
+}
+
+func (dp *DRNParams) Defaults() {
+	dp.Tonic = 0
+	dp.PVposEstGain = 1
+	dp.PVnegGain = 0.5
+	dp.SrcGain = 1
+	dp.Tau = 50
+	dp.Update()
+}
+
+func (dp *DRNParams) Update() {
+	dp.Dt = 1 / dp.Tau
+}
+
+// Ser returns the raw 5-HT value based on given source layer activation
+// and key values from the ctx Context.
+func (dp *DRNParams) Ser(ctx *Context, di uint32, srcLayAct float32) float32 {
+	ser := dp.Tonic + dp.PVposEstGain*GlbV(ctx, di, GvPVposEst) + dp.PVnegGain*GlbV(ctx, di, GvPVneg) + dp.SrcGain*srcLayAct
+	return mat32.Clamp(ser, 0, 1)
+}
+
 //gosl: end pvlv_layers
 
 // VSPatchAdaptThr adapts the learning threshold
@@ -192,7 +292,7 @@ func (ly *Layer) VSPatchAdaptThr(ctx *Context) {
 	for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
 		hasRew := GlbV(ctx, di, GvHasRew)
 		// note: this all must be based on t-1 values!!!
-		modlr := ly.Params.Learn.NeuroMod.LRMod(GlbV(ctx, di, GvDA), GlbV(ctx, di, GvACh))
+		modlr := ly.Params.Learn.NeuroMod.LRMod(GlbV(ctx, di, GvDA), GlbV(ctx, di, GvACh), GlbV(ctx, di, GvNE), GlbV(ctx, di, GvSer))
 		if hasRew == 0 {
 			vsval := GlbV(ctx, di, GvVSPatchPosPrev)    // must be prev!
 			dthr := ly.Params.VSPatch.ThrNonRew * vsval // increase threshold if active
@@ -313,6 +413,34 @@ func (ly *Layer) LDTDefaults() {
 	}
 }
 
+// LCDRNDefaults sets defaults for the LCLayer and DRNLayer types,
+// which do not learn and whose activity is set from Global values.
+func (ly *Layer) LCDRNDefaults() {
+	lp := ly.Params
+	lp.Inhib.ActAvg.Nominal = 0.1
+	lp.Inhib.Layer.On.SetBool(true)
+	lp.Inhib.Layer.Gi = 1
+	lp.Inhib.Pool.On.SetBool(false)
+	lp.Acts.Decay.Act = 1
+	lp.Acts.Decay.Glong = 1
+	lp.Learn.TrgAvgAct.On.SetBool(false)
+
+	for _, pj := range ly.RcvPrjns {
+		pj.Params.SetFixedWts()
+		pj.Params.PrjnScale.Abs = 1
+	}
+}
+
+// LCDRNPostBuild does post-Build config for LCLayer and DRNLayer
+func (ly *Layer) LCDRNPostBuild() {
+	srcIdx := ly.BuildConfigFindLayer("SrcLayName", false) // optional
+	if ly.LayerType() == LCLayer {
+		ly.Params.LC.SrcLayIdx = srcIdx
+	} else {
+		ly.Params.DRN.SrcLayIdx = srcIdx
+	}
+}
+
 func (ly *LayerParams) VSPatchDefaults() {
 	ly.Acts.Decay.Act = 1
 	ly.Acts.Decay.Glong = 1
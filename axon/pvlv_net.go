@@ -16,6 +16,26 @@ func (net *Network) AddLDTLayer(prefix string) *Layer {
 	return ldt
 }
 
+// AddLCLayer adds a LCLayer computing NE (norepinephrine) arousal.
+// Optional srcLay drives additional NE via BuildConfig SrcLayName.
+func (net *Network) AddLCLayer(prefix string, srcLay *Layer) *Layer {
+	lc := net.AddLayer2D(prefix+"LC", 1, 1, LCLayer)
+	if srcLay != nil {
+		lc.SetBuildConfig("SrcLayName", srcLay.Name())
+	}
+	return lc
+}
+
+// AddDRNLayer adds a DRNLayer computing 5-HT (serotonin).
+// Optional srcLay drives additional 5-HT via BuildConfig SrcLayName.
+func (net *Network) AddDRNLayer(prefix string, srcLay *Layer) *Layer {
+	drn := net.AddLayer2D(prefix+"DRN", 1, 1, DRNLayer)
+	if srcLay != nil {
+		drn.SetBuildConfig("SrcLayName", srcLay.Name())
+	}
+	return drn
+}
+
 // AddBLALayers adds two BLA layers, acquisition / extinction / D1 / D2,
 // for positive or negative valence
 func (net *Network) AddBLALayers(prefix string, pos bool, nUs, nNeurY, nNeurX int, rel relpos.Relations, space float32) (acq, ext *Layer) {
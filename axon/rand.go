@@ -1,6 +1,8 @@
 package axon
 
 import (
+	"sort"
+
 	"github.com/goki/gosl/slrand"
 )
 
@@ -16,9 +18,37 @@ type RandFunIdx uint32
 // requires random number generation. If you add a new function, you need to add
 // a new enum entry here.
 // RandFunIdxN is the total number of random functions. It autoincrements due to iota.
+// Each function is a separate named stream of random numbers, where the
+// index key (e.g., neuron, synapse or data index) selects the value within
+// the stream, so results do not depend on the order of computation.
 const (
+	// RandFunActPGe is the Poisson spiking noise for excitatory conductance
 	RandFunActPGe RandFunIdx = iota
+
+	// RandFunActPGi is the Poisson spiking noise for inhibitory conductance
 	RandFunActPGi
+
+	// RandFunSynFail is synaptic communication failure, keyed by synapse index
+	RandFunSynFail
+
+	// RandFunGiveUp is the PVLV give-up decision, keyed by data index
+	RandFunGiveUp
+
+	// RandFunExplore is the Matrix exploration noise, keyed by data index * NStripes + stripe
+	RandFunExplore
+
+	// RandFunOUGe is the private Ornstein-Uhlenbeck excitatory noise, keyed by data index * NNeurons + neuron
+	RandFunOUGe
+
+	// RandFunOUGi is the private Ornstein-Uhlenbeck inhibitory noise, keyed by data index * NNeurons + neuron
+	RandFunOUGi
+
+	// RandFunOUGePool is the pool-shared Ornstein-Uhlenbeck excitatory noise, keyed by data index * NPools + pool
+	RandFunOUGePool
+
+	// RandFunOUGiPool is the pool-shared Ornstein-Uhlenbeck inhibitory noise, keyed by data index * NPools + pool
+	RandFunOUGiPool
+
 	RandFunIdxN
 )
 
@@ -34,4 +64,55 @@ func GetRandomNumber(index uint32, counter slrand.Counter, funIdx RandFunIdx) fl
 	return slrand.Float(&ctr, index)
 }
 
+// GetRandomNormal returns a normally distributed (Gaussian) random number
+// with zero mean and unit variance, that depends on the index, counter and
+// function index, as in GetRandomNumber.
+func GetRandomNormal(index uint32, counter slrand.Counter, funIdx RandFunIdx) float32 {
+	var randCtr slrand.Counter
+	randCtr = counter
+	randCtr.Add(uint32(funIdx))
+	ctr := randCtr.Uint2()
+	return slrand.NormFloat(&ctr, index)
+}
+
 //gosl: end axonrand
+
+// RandFunIdx values for random streams that are only used on the CPU,
+// outside of the per-cycle updates, with the NetworkBase RandCtr counter
+// instead of the Context RandCtr.
+// They are thus not included in RandFunIdxN, and adding to them does
+// not affect the per-cycle random numbers.
+const (
+	// RandFunLesion is the random selection of lesioned neurons or synapses, keyed by network neuron or synapse index
+	RandFunLesion = RandFunIdxN + iota
+
+	// RandFunGeBase is the initial GeBase variance, keyed by neuron index
+	RandFunGeBase
+
+	// RandFunGiBase is the initial GiBase variance, keyed by neuron index
+	RandFunGiBase
+
+	// RandFunSWtInit is the initial weight variance, keyed by synapse index
+	RandFunSWtInit
+
+	// RandFunTrgAvgPerm is the random order of TrgAvg values (TrgAvgAct.Permute), keyed by neuron index
+	RandFunTrgAvgPerm
+)
+
+// RandKeyOrder returns a random permutation of indexes into given keys,
+// in order of the random number for each key in given random stream.
+// Because each key has its own random number, the relative order of any
+// subset of keys does not depend on the other keys or on the order in
+// which they are listed.
+func RandKeyOrder(keys []uint32, counter slrand.Counter, funIdx RandFunIdx) []int {
+	rnd := make([]float32, len(keys))
+	p := make([]int, len(keys))
+	for i, k := range keys {
+		rnd[i] = GetRandomNumber(k, counter, funIdx)
+		p[i] = i
+	}
+	sort.SliceStable(p, func(i, j int) bool {
+		return rnd[p[i]] < rnd[p[j]]
+	})
+	return p
+}
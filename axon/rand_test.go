@@ -0,0 +1,157 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"os"
+	"testing"
+
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/prjn"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRandTestNet(t *testing.T, nData int, seed int64) (*Network, *Context) {
+	return newTestNetLayers(t, nData, func(net *Network) {
+		inp := net.AddLayer2D("Input", 4, 4, InputLayer)
+		hid := net.AddLayer2D("Hidden", 4, 4, SuperLayer)
+		net.ConnectLayers(inp, hid, prjn.NewFull(), ForwardPrjn)
+	}, func(net *Network) {
+		net.SetRndSeed(seed)
+		hid := net.AxonLayerByName("Hidden")
+		hid.Params.Acts.Init.GeVar = 0.1
+		hid.Params.Acts.Init.GiVar = 0.1
+	})
+}
+
+func TestRandStreams(t *testing.T) {
+	var ctr1, ctr2 = NewContext().RandCtr, NewContext().RandCtr
+	ctr2.Add(uint32(RandFunIdxN))
+	vals := map[float32]bool{}
+	for fi := RandFunIdx(0); fi < RandFunIdxN; fi++ {
+		vals[GetRandomNumber(0, ctr1, fi)] = true
+		vals[GetRandomNumber(0, ctr2, fi)] = true
+	}
+	assert.Equal(t, 2*int(RandFunIdxN), len(vals)) // all distinct
+}
+
+// TestGPURandCtr checks that the GPU CycleInc advances the random counter
+// by RandFunIdxN per cycle, as on the CPU, which requires the shaders to be
+// regenerated whenever RandFunIdxN changes.
+func TestGPURandCtr(t *testing.T) {
+	if os.Getenv("TEST_GPU") != "true" {
+		t.Skip("Set TEST_GPU env var to run GPU tests")
+	}
+	net, ctx := newRandTestNet(t, 1, 1)
+	net.ConfigGPUnoGUI(ctx)
+	defer net.GPU.Destroy()
+	net.NewState(ctx)
+	ctx.NewState(etime.Train)
+	exp := ctx.RandCtr
+	exp.Add(uint32((CyclesN - 1) * int(RandFunIdxN)))
+	net.Cycle(ctx) // runs CyclesN cycles on the GPU
+	net.GPU.SyncContextFmGPU()
+	assert.Equal(t, exp.Uint2(), ctx.RandCtr.Uint2())
+}
+
+// randTestVals returns the Hidden weights and GeBase, GiBase values of
+// a new network -- must be read before building another network
+// (see GlobalNetwork).
+func randTestVals(t *testing.T, nData int, seed int64) (wts, ges, gis []float32) {
+	net, ctx := newRandTestNet(t, nData, seed)
+	hid := net.AxonLayerByName("Hidden")
+	pj := hid.RcvPrjns[0]
+	for syi := uint32(0); syi < pj.NSyns; syi++ {
+		wts = append(wts, SynV(ctx, pj.SynStIdx+syi, Wt))
+	}
+	for lni := uint32(0); lni < hid.NNeurons; lni++ {
+		ges = append(ges, NrnAvgV(ctx, hid.NeurStIdx+lni, GeBase))
+		gis = append(gis, NrnAvgV(ctx, hid.NeurStIdx+lni, GiBase))
+	}
+	return
+}
+
+func TestRandInitReproducible(t *testing.T) {
+	wts1, ges1, gis1 := randTestVals(t, 1, 1)
+	wts2, ges2, gis2 := randTestVals(t, 2, 1)
+	wts3, ges3, _ := randTestVals(t, 1, 2)
+	assert.Equal(t, wts1, wts2)
+	assert.Equal(t, ges1, ges2)
+	assert.Equal(t, gis1, gis2)
+	for i := range wts1 {
+		assert.NotEqual(t, wts1[i], wts3[i]) // different seed, different weights
+	}
+	assert.NotEqual(t, ges1, ges3)
+	nzero := 0
+	for _, ge := range ges1 {
+		if ge == 0 {
+			nzero++
+		}
+	}
+	assert.Less(t, nzero, len(ges1))
+}
+
+func TestRandSynFail(t *testing.T) {
+	net, ctx := newRandTestNet(t, 1, 1)
+	pj := net.AxonLayerByName("Hidden").RcvPrjns[0]
+	pj.Params.Com.PFail = 0.5
+	pj.SynFail(ctx)
+	fails := make([]bool, pj.NSyns)
+	nfail := 0
+	for syi := uint32(0); syi < pj.NSyns; syi++ {
+		fails[syi] = SynV(ctx, pj.SynStIdx+syi, Wt) == 0
+		if fails[syi] {
+			nfail++
+		}
+	}
+	assert.InDelta(t, 0.5, float32(nfail)/float32(pj.NSyns), 0.1)
+
+	// same counter gives the same failures
+	pj.SynFail(ctx)
+	for syi := uint32(0); syi < pj.NSyns; syi++ {
+		assert.Equal(t, fails[syi], SynV(ctx, pj.SynStIdx+syi, Wt) == 0)
+	}
+}
+
+func TestRandGiveUp(t *testing.T) {
+	ctx := NewContext()
+	gp := &GiveUpParams{}
+	gp.Defaults()
+	n := 0
+	for i := 0; i < 1000; i++ {
+		_, giveUp := gp.ProbCtx(ctx, 0, 0)
+		_, giveUp1 := gp.ProbCtx(ctx, 0, 0)
+		assert.Equal(t, giveUp, giveUp1)
+		if giveUp {
+			n++
+		}
+		ctx.CycleInc()
+	}
+	assert.InDelta(t, 500, n, 60)
+}
+
+func TestRandKeyOrder(t *testing.T) {
+	ctr := NewContext().RandCtr
+	keys := []uint32{3, 10, 11, 42, 7, 100}
+	p := RandKeyOrder(keys, ctr, RandFunLesion)
+	assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5}, p)
+	var order []uint32
+	for _, i := range p {
+		order = append(order, keys[i])
+	}
+	// relative order of a subset does not depend on the other keys
+	sub := []uint32{100, 10, 3}
+	var sorder []uint32
+	for _, i := range RandKeyOrder(sub, ctr, RandFunLesion) {
+		sorder = append(sorder, sub[i])
+	}
+	var exp []uint32
+	for _, k := range order {
+		if k == 100 || k == 10 || k == 3 {
+			exp = append(exp, k)
+		}
+	}
+	assert.Equal(t, exp, sorder)
+}
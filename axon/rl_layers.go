@@ -102,6 +102,32 @@ func (tp *TDDaParams) GeFmDA(da float32) float32 {
 	return tp.TonicGe * (1.0 + da)
 }
 
+// SRPredParams are params for the successor representation (SR) prediction layer,
+// which learns the discounted future occupancy of each unit in a state layer.
+type SRPredParams struct {
+
+	// discount factor on future state occupancy -- how much to discount the next SR prediction relative to the current state
+	Discount float32 `desc:"discount factor on future state occupancy -- how much to discount the next SR prediction relative to the current state"`
+
+	// idx of state layer whose future occupancy is predicted -- set during Build from BuildConfig SRStateLayName
+	StateLayIdx int32 `inactive:"+" desc:"idx of state layer whose future occupancy is predicted -- set during Build from BuildConfig SRStateLayName"`
+
+	pad, pad1 uint32
+}
+
+func (sp *SRPredParams) Defaults() {
+	sp.Discount = 0.9
+}
+
+func (sp *SRPredParams) Update() {
+}
+
+// Err returns the SR temporal difference error given the current state activity,
+// the current SR prediction, and the SR prediction from the previous trial.
+func (sp *SRPredParams) Err(state, pred, prv float32) float32 {
+	return state + sp.Discount*pred - prv
+}
+
 //gosl: end rl_layers
 
 // note: Defaults not called on GPU
@@ -147,3 +173,14 @@ func (ly *Layer) TDIntegPostBuild() {
 func (ly *Layer) TDDaPostBuild() {
 	ly.Params.TDDa.TDIntegLayIdx = ly.BuildConfigFindLayer("TDIntegLayName", true)
 }
+
+func (ly *LayerParams) SRPredDefaults() {
+	ly.Acts.Decay.Act = 1
+	ly.Acts.Decay.Glong = 1
+	ly.Acts.Dt.GeTau = 40
+}
+
+// SRPredPostBuild does post-Build config
+func (ly *Layer) SRPredPostBuild() {
+	ly.Params.SRPred.StateLayIdx = ly.BuildConfigFindLayer("SRStateLayName", true)
+}
@@ -65,3 +65,35 @@ func (nt *Network) AddRWLayers(prefix string, rel relpos.Relations, space float3
 func (nt *Network) ConnectToRWPrjn(send, recv *Layer, pat prjn.Pattern) *Prjn {
 	return nt.ConnectLayers(send, recv, pat, RWPrjn)
 }
+
+// AddSRPredLayer adds a SRPredLayer of given name that learns a successor
+// representation of the discounted future occupancy of given state layer,
+// with the same shape as the state layer, and a full SRPrjn from it.
+func (nt *Network) AddSRPredLayer(name string, state *Layer) *Layer {
+	sr := nt.AddLayer(name, state.Shp.Shapes(), SRPredLayer)
+	sr.SetBuildConfig("SRStateLayName", state.Name())
+	nt.ConnectToSRPred(state, sr, prjn.NewFull())
+	return sr
+}
+
+// AddSRLayers adds a successor representation (SR) SRPredLayer over given
+// state layer, along with the standard TD layers (see AddTDLayers),
+// where the RewPred layer reads out value from the SR via a TDPredPrjn,
+// whose weights learn the reward associated with each state.
+// The SR layer is placed relative to the TD layer.
+func (nt *Network) AddSRLayers(prefix string, state *Layer, rel relpos.Relations, space float32) (sr, rew, rp, ri, td *Layer) {
+	rew, rp, ri, td = nt.AddTDLayers(prefix, rel, space)
+	sr = nt.AddSRPredLayer(prefix+"SR", state)
+	if rel == relpos.Behind {
+		sr.PlaceBehind(td, space)
+	} else {
+		sr.PlaceRightOf(td, space)
+	}
+	nt.ConnectLayers(sr, rp, prjn.NewFull(), TDPredPrjn)
+	return
+}
+
+// ConnectToSRPred adds a SRPrjn from given sending state layer to a SRPred layer
+func (nt *Network) ConnectToSRPred(send, recv *Layer, pat prjn.Pattern) *Prjn {
+	return nt.ConnectLayers(send, recv, pat, SRPrjn)
+}
@@ -18,7 +18,10 @@ type RLPredPrjnParams struct {
 	// tolerance on DA -- if below this abs value, then DA goes to zero and there is no learning -- prevents prediction from exactly learning to cancel out reward value, retaining a residual valence of signal
 	DaTol float32 `desc:"tolerance on DA -- if below this abs value, then DA goes to zero and there is no learning -- prevents prediction from exactly learning to cancel out reward value, retaining a residual valence of signal"`
 
-	pad, pad1 float32
+	// [min: 0] [max: 1] for TDPredPrjn, decay factor for the TD(lambda) eligibility trace of sending activity, stored in the synapse Tr value and updated every trial (including testing) by Network.PlusPhase, such that DA drives learning on all recently active senders -- this is the effective lambda * discount factor.  The trace is a running average, with the new activity weighted by (1 - Lambda), so the effective learning rate does not grow with Lambda.  Reset at the start of each episode with Network.InitTDPredTrace.  0 = TD(0), where only the immediately prior sending activity is used
+	Lambda float32 `min:"0" max:"1" desc:"for TDPredPrjn, decay factor for the TD(lambda) eligibility trace of sending activity, stored in the synapse Tr value and updated every trial (including testing) by Network.PlusPhase, such that DA drives learning on all recently active senders -- this is the effective lambda * discount factor.  The trace is a running average, with the new activity weighted by (1 - Lambda), so the effective learning rate does not grow with Lambda.  Reset at the start of each episode with Network.InitTDPredTrace.  0 = TD(0), where only the immediately prior sending activity is used"`
+
+	pad float32
 }
 
 func (pj *RLPredPrjnParams) Defaults() {
@@ -28,6 +31,14 @@ func (pj *RLPredPrjnParams) Defaults() {
 func (pj *RLPredPrjnParams) Update() {
 }
 
+// EligTrace returns the updated TD(lambda) eligibility trace,
+// given the previous trace value and the sending activity.
+// The new activity is weighted by (1 - Lambda), so the trace is a
+// running average of sending activity, with the same scale as TD(0).
+func (pj *RLPredPrjnParams) EligTrace(tr, sact float32) float32 {
+	return pj.Lambda*tr + (1-pj.Lambda)*sact
+}
+
 //gosl: end rl_prjns
 
 func (pj *PrjnParams) RLPredDefaults() {
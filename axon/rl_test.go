@@ -0,0 +1,99 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/relpos"
+	"github.com/emer/etable/etensor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSRPredErr(t *testing.T) {
+	sp := SRPredParams{}
+	sp.Defaults()
+	assert.InDelta(t, 1.0+0.9*0.5-0.2, sp.Err(1, 0.5, 0.2), 1.0e-6)
+	assert.Equal(t, float32(0), sp.Err(0, 0, 0))
+}
+
+// TestSRLayers runs a cyclic sequence of one-hot states through an SR network,
+// and checks that the learned SR for the first state reflects discounted
+// future occupancy: next state > following states.
+func TestSRLayers(t *testing.T) {
+	ctx := NewContext()
+	net := NewNetwork("SRTest")
+	state := net.AddLayer2D("State", 1, 4, InputLayer)
+	sr, _, rp, _, _ := net.AddSRLayers("", state, relpos.Behind, 2)
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	assert.Equal(t, int32(state.Index()), sr.Params.SRPred.StateLayIdx)
+	assert.Equal(t, SRPrjn, sr.RcvPrjns[0].PrjnType())
+	assert.Equal(t, TDPredPrjn, rp.RcvPrjns[0].PrjnType())
+	srpj := sr.RcvPrjns[0]
+	srpj.Params.Learn.LRate.Base = 0.1
+	net.InitWts(ctx)
+
+	pat := etensor.NewFloat32([]int{1, 4}, nil, nil)
+	for trl := 0; trl < 80; trl++ {
+		ctx.NewState(etime.Train)
+		net.NewState(ctx)
+		for i := range pat.Values {
+			pat.Values[i] = 0
+		}
+		pat.Values[trl%4] = 1
+		net.InitExt(ctx)
+		state.ApplyExt(ctx, 0, pat)
+		net.ApplyExts(ctx)
+		for cyc := 0; cyc < 200; cyc++ {
+			net.Cycle(ctx)
+			ctx.CycleInc()
+			if cyc == 149 {
+				net.MinusPhase(ctx)
+				ctx.NewPhase(true)
+				net.PlusPhaseStart(ctx)
+			}
+		}
+		net.PlusPhase(ctx)
+		net.DWt(ctx)
+		net.WtFmDWt(ctx)
+	}
+	next := srpj.SynVal("Wt", 0, 1)
+	after := srpj.SynVal("Wt", 0, 3)
+	assert.Greater(t, next, after)
+	assert.Greater(t, next, float32(0))
+}
+
+// TestTDPredTrace checks that the TD(lambda) trace is a (1 - Lambda)
+// normalized running average of sending activity, updated by PlusPhase
+// in any mode, only when Lambda > 0, and reset by InitTDPredTrace.
+func TestTDPredTrace(t *testing.T) {
+	ctx := NewContext()
+	net := NewNetwork("TDTrace")
+	state := net.AddLayer2D("State", 1, 4, InputLayer)
+	sr, _, rp, _, _ := net.AddSRLayers("", state, relpos.Behind, 2)
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	pj := rp.RcvPrjns[0]
+	pj.Params.RLPred.Lambda = 0.8
+	net.InitWts(ctx)
+
+	si := sr.NeurStIdx
+	syni := pj.SynStIdx + pj.SendCon[0].Start
+	ctx.NewState(etime.Test)
+	SetNrnV(ctx, si, 0, SpkPrv, 1)
+	net.PlusPhase(ctx)
+	assert.InDelta(t, 0.2, SynCaV(ctx, syni, 0, Tr), 1.0e-6)
+	net.PlusPhase(ctx)
+	assert.InDelta(t, 0.8*0.2+0.2, SynCaV(ctx, syni, 0, Tr), 1.0e-6)
+
+	net.InitTDPredTrace(ctx)
+	assert.Equal(t, float32(0), SynCaV(ctx, syni, 0, Tr))
+
+	pj.Params.RLPred.Lambda = 0
+	net.PlusPhase(ctx)
+	assert.Equal(t, float32(0), SynCaV(ctx, syni, 0, Tr))
+}
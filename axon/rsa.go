@@ -0,0 +1,339 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/emer/emergent/elog"
+	"github.com/emer/emergent/etime"
+	"github.com/emer/etable/clust"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/etable/metric"
+	"github.com/emer/etable/simat"
+)
+
+// RSA supports representational similarity analysis, by accumulating
+// the activity patterns of given layers for each labeled stimulus,
+// averaging across repeated presentations over trials and data parallel
+// indexes, and computing representational dissimilarity matrices (RDMs)
+// over the stimuli, which can be compared against target (model) RDMs
+// and clustered.  Call Reset at the start of each epoch, Record at the end
+// of each trial for each data index, and the RDM, Cor, and Clust methods
+// (or LogAddRSAItems) at the end of the epoch -- results are computed
+// automatically as needed after new patterns are recorded.
+type RSA struct {
+
+	// names of the layers to analyze
+	Layers []string `desc:"names of the layers to analyze"`
+
+	// [def: ActM] neuron variable to record (e.g., ActM or ActP)
+	Var string `def:"ActM" desc:"neuron variable to record (e.g., ActM or ActP)"`
+
+	// [def: InvCorrelation] metric for computing the RDMs -- should be a distance (Increasing) metric for clustering and comparison with target dissimilarity matrices
+	Metric metric.StdMetrics `def:"InvCorrelation" desc:"metric for computing the RDMs -- should be a distance (Increasing) metric for clustering and comparison with target dissimilarity matrices"`
+
+	// [def: true] use Spearman rank correlation to compare RDMs, which is standard for RSA, otherwise Pearson correlation
+	Spearman bool `def:"true" desc:"use Spearman rank correlation to compare RDMs, which is standard for RSA, otherwise Pearson correlation"`
+
+	// labels of the stimuli, in the order first recorded
+	Labels []string `desc:"labels of the stimuli, in the order first recorded"`
+
+	// number of patterns recorded for each label
+	Counts []int `desc:"number of patterns recorded for each label"`
+
+	// target RDMs to compare against, by name, with Rows labels matching the stimulus labels
+	Targets map[string]*simat.SimMat `desc:"target RDMs to compare against, by name, with Rows labels matching the stimulus labels"`
+
+	// [view: no-inline] RDMs for each layer, as computed by Compute, using Metric
+	RDMs map[string]*simat.SimMat `view:"no-inline" desc:"RDMs for each layer, as computed by Compute, using Metric"`
+
+	// [view: -] average patterns for each layer, as computed by Compute: [Labels][Units]
+	Pats map[string]*etensor.Float64 `view:"-" desc:"average patterns for each layer, as computed by Compute: [Labels][Units]"`
+
+	// [view: -] correlations between layer and target RDMs, keyed by layer:target
+	Cors map[string]float64 `view:"-" desc:"correlations between layer and target RDMs, keyed by layer:target"`
+
+	// [view: -] map of label indexes
+	LabelMap map[string]int `view:"-" desc:"map of label indexes"`
+
+	// [view: -] sums of patterns for each layer and label
+	Sums map[string][][]float64 `view:"-" desc:"sums of patterns for each layer and label"`
+
+	// [view: -] true if new patterns have been recorded since last Compute
+	Dirty bool `view:"-" desc:"true if new patterns have been recorded since last Compute"`
+
+	// [view: -] temp values
+	Vals []float32 `view:"-" desc:"temp values"`
+}
+
+func (rs *RSA) Defaults() {
+	rs.Var = "ActM"
+	rs.Metric = metric.InvCorrelation
+	rs.Spearman = true
+}
+
+// Init initializes the RSA for given layers, with defaults
+func (rs *RSA) Init(layers ...string) {
+	rs.Defaults()
+	rs.Layers = layers
+	rs.Targets = make(map[string]*simat.SimMat)
+	rs.Reset()
+}
+
+// Reset resets the recorded patterns -- call at the start of each epoch.
+// Targets are retained.
+func (rs *RSA) Reset() {
+	rs.Labels = nil
+	rs.Counts = nil
+	rs.LabelMap = make(map[string]int)
+	rs.Sums = make(map[string][][]float64)
+	rs.RDMs = make(map[string]*simat.SimMat)
+	rs.Pats = make(map[string]*etensor.Float64)
+	rs.Cors = make(map[string]float64)
+	rs.Dirty = true
+}
+
+// SetTarget sets a target RDM to compare against, under given name.
+// The Rows of the SimMat must have the stimulus labels.
+func (rs *RSA) SetTarget(name string, smat *simat.SimMat) {
+	if rs.Targets == nil {
+		rs.Targets = make(map[string]*simat.SimMat)
+	}
+	rs.Targets[name] = smat
+	rs.Dirty = true
+}
+
+// TargetNames returns the sorted names of the targets
+func (rs *RSA) TargetNames() []string {
+	nms := make([]string, 0, len(rs.Targets))
+	for nm := range rs.Targets {
+		nms = append(nms, nm)
+	}
+	sort.Strings(nms)
+	return nms
+}
+
+// Record records the Var activity patterns for all layers for given
+// data index, under given stimulus label, adding to any prior patterns
+// recorded for the same label.
+func (rs *RSA) Record(net *Network, di int, label string) error {
+	li, ok := rs.LabelMap[label]
+	if !ok {
+		li = len(rs.Labels)
+		rs.LabelMap[label] = li
+		rs.Labels = append(rs.Labels, label)
+		rs.Counts = append(rs.Counts, 0)
+	}
+	rs.Counts[li]++
+	for _, lnm := range rs.Layers {
+		ely, err := net.LayerByNameTry(lnm)
+		if err != nil {
+			return err
+		}
+		ly := ely.(AxonLayer).AsAxon()
+		if err := ly.UnitVals(&rs.Vals, rs.Var, di); err != nil {
+			return err
+		}
+		sums := rs.Sums[lnm]
+		for len(sums) <= li {
+			sums = append(sums, make([]float64, len(rs.Vals)))
+		}
+		rs.Sums[lnm] = sums
+		for i, v := range rs.Vals {
+			sums[li][i] += float64(v)
+		}
+	}
+	rs.Dirty = true
+	return nil
+}
+
+// RecordAll records patterns for all data indexes, with given labels
+// for each data index.
+func (rs *RSA) RecordAll(ctx *Context, net *Network, labels []string) error {
+	for di := 0; di < int(ctx.NetIdxs.NData); di++ {
+		if err := rs.Record(net, di, labels[di]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compute computes the average patterns, RDMs using Metric, and
+// correlations with the Targets, for all layers.
+func (rs *RSA) Compute() {
+	rs.Dirty = false
+	nl := len(rs.Labels)
+	for _, lnm := range rs.Layers {
+		sums := rs.Sums[lnm]
+		if len(sums) == 0 {
+			continue
+		}
+		nu := len(sums[0])
+		pats := etensor.NewFloat64([]int{nl, nu}, nil, []string{"Label", "Unit"})
+		for li := 0; li < nl; li++ {
+			cnt := float64(rs.Counts[li])
+			for ui := 0; ui < nu; ui++ {
+				pats.Values[li*nu+ui] = sums[li][ui] / cnt
+			}
+		}
+		rs.Pats[lnm] = pats
+		rs.RDMs[lnm] = rs.RDM(lnm, rs.Metric)
+		for tnm, trg := range rs.Targets {
+			rs.Cors[lnm+":"+tnm] = rs.CorRDMs(rs.RDMs[lnm], trg)
+		}
+	}
+}
+
+// ComputeIfDirty calls Compute if new patterns have been recorded
+func (rs *RSA) ComputeIfDirty() {
+	if rs.Dirty {
+		rs.Compute()
+	}
+}
+
+// RDM returns a new RDM for given layer using given metric,
+// with labels for the rows and columns.  Can be used to compute RDMs
+// with other metrics than the default Metric.  Returns nil if the
+// layer has no recorded patterns.
+func (rs *RSA) RDM(lnm string, met metric.StdMetrics) *simat.SimMat {
+	rs.ComputeIfDirty()
+	pats := rs.Pats[lnm]
+	if pats == nil {
+		return nil
+	}
+	smat := &simat.SimMat{}
+	smat.Init()
+	simat.TensorStd(smat.Mat, pats, met)
+	smat.Rows = append([]string{}, rs.Labels...)
+	smat.Cols = smat.Rows
+	return smat
+}
+
+// Cor returns the correlation between the RDM for given layer and
+// given target RDM, computing as needed.
+func (rs *RSA) Cor(lnm, target string) float64 {
+	rs.ComputeIfDirty()
+	return rs.Cors[lnm+":"+target]
+}
+
+// MeanDist returns the mean off-diagonal value of the RDM for given layer,
+// reflecting the overall distinctiveness of the patterns, computing as needed.
+func (rs *RSA) MeanDist(lnm string) float64 {
+	rs.ComputeIfDirty()
+	smat := rs.RDMs[lnm]
+	if smat == nil {
+		return 0
+	}
+	n := smat.Mat.Dim(0)
+	if n < 2 {
+		return 0
+	}
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			sum += smat.Mat.FloatVal([]int{i, j})
+		}
+	}
+	return sum / float64(n*(n-1)/2)
+}
+
+// Clust returns the agglomerative clustering of the RDM for given layer,
+// using given distance function, computing as needed.
+// Use clust.Plot to plot the results in a table.
+func (rs *RSA) Clust(lnm string, dist clust.StdDists) *clust.Node {
+	rs.ComputeIfDirty()
+	smat := rs.RDMs[lnm]
+	if smat == nil {
+		return nil
+	}
+	return clust.GlomStd(smat, dist)
+}
+
+// CorRDMs returns the correlation between the upper triangles of the two
+// RDMs, over the labels that are present in both, matched by the Rows labels
+// (Spearman rank correlation if Spearman is set, else Pearson).
+func (rs *RSA) CorRDMs(a, b *simat.SimMat) float64 {
+	bmap := make(map[string]int, len(b.Rows))
+	for i, lb := range b.Rows {
+		bmap[lb] = i
+	}
+	var ai, bi []int
+	for i, lb := range a.Rows {
+		if j, ok := bmap[lb]; ok {
+			ai = append(ai, i)
+			bi = append(bi, j)
+		}
+	}
+	var av, bv []float64
+	for i := range ai {
+		for j := i + 1; j < len(ai); j++ {
+			av = append(av, a.Mat.FloatVal([]int{ai[i], ai[j]}))
+			bv = append(bv, b.Mat.FloatVal([]int{bi[i], bi[j]}))
+		}
+	}
+	if len(av) < 2 {
+		return 0
+	}
+	if rs.Spearman {
+		av = rsaRanks(av)
+		bv = rsaRanks(bv)
+	}
+	return metric.Correlation64(av, bv)
+}
+
+// rsaRanks returns the ranks of given values, with ties
+// assigned the average rank
+func rsaRanks(vals []float64) []float64 {
+	n := len(vals)
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return vals[idx[i]] < vals[idx[j]] })
+	rnk := make([]float64, n)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && vals[idx[j]] == vals[idx[i]] {
+			j++
+		}
+		r := 0.5 * float64(i+j-1)
+		for k := i; k < j; k++ {
+			rnk[idx[k]] = r
+		}
+		i = j
+	}
+	return rnk
+}
+
+// LogAddRSAItems adds items to given logs for the RSA results
+// for each layer, at given mode and time scale (e.g., Test, Epoch):
+// the correlation with each target RDM (Layer_RSA_Target),
+// and the mean distance (Layer_RSA_Dist).  Targets must be set prior to
+// calling this.  The RSA should be Reset at the start of each epoch,
+// after logging.
+func LogAddRSAItems(lg *elog.Logs, rs *RSA, mode etime.Modes, etm etime.Times) {
+	for _, lnm := range rs.Layers {
+		clnm := lnm
+		lg.AddItem(&elog.Item{
+			Name: clnm + "_RSA_Dist",
+			Type: etensor.FLOAT64,
+			Write: elog.WriteMap{
+				etime.Scope(mode, etm): func(ctx *elog.Context) {
+					ctx.SetFloat64(rs.MeanDist(clnm))
+				}}})
+		for _, tnm := range rs.TargetNames() {
+			ctnm := tnm
+			lg.AddItem(&elog.Item{
+				Name: fmt.Sprintf("%s_RSA_%s", clnm, ctnm),
+				Type: etensor.FLOAT64,
+				Write: elog.WriteMap{
+					etime.Scope(mode, etm): func(ctx *elog.Context) {
+						ctx.SetFloat64(rs.Cor(clnm, ctnm))
+					}}})
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/elog"
+	"github.com/emer/emergent/estats"
+	"github.com/emer/emergent/etime"
+	"github.com/emer/etable/clust"
+	"github.com/emer/etable/metric"
+	"github.com/emer/etable/simat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRSA(t *testing.T) {
+	ctx := NewContext()
+	net := NewNetwork("RSA")
+	net.SetMaxData(ctx, 2)
+	hid := net.AddLayer2D("Hidden", 1, 4, SuperLayer)
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	net.InitWts(ctx)
+
+	// two categories: A, B similar, and C, D similar
+	pats := map[string][]float32{
+		"A": {1, 0.8, 0, 0},
+		"B": {0.8, 1, 0.1, 0},
+		"C": {0, 0.1, 1, 0.7},
+		"D": {0, 0, 0.7, 1},
+	}
+	setPat := func(di int, lb string, noise float32) {
+		for i, v := range pats[lb] {
+			SetNrnV(ctx, hid.NeurStIdx+uint32(i), uint32(di), ActM, v+noise)
+		}
+	}
+	rs := &RSA{}
+	rs.Init("Hidden")
+	cat := &simat.SimMat{}
+	cat.Init()
+	cat.Mat.SetShape([]int{4, 4}, nil, nil)
+	cat.Rows = []string{"D", "C", "B", "A"} // order need not match
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			if i/2 != j/2 {
+				cat.Mat.SetFloat([]int{i, j}, 1)
+			}
+		}
+	}
+	rs.SetTarget("Cat", cat)
+
+	// record each label twice across data indexes, with offsetting noise
+	lbs := []string{"A", "B", "C", "D"}
+	for i := 0; i < 2; i++ {
+		setPat(0, lbs[2*i], 0.05)
+		setPat(1, lbs[2*i+1], 0.05)
+		assert.NoError(t, rs.RecordAll(ctx, net, lbs[2*i:2*i+2]))
+		setPat(0, lbs[2*i+1], -0.05)
+		setPat(1, lbs[2*i], -0.05)
+		assert.NoError(t, rs.RecordAll(ctx, net, []string{lbs[2*i+1], lbs[2*i]}))
+	}
+	assert.Equal(t, lbs, rs.Labels)
+	assert.Equal(t, []int{2, 2, 2, 2}, rs.Counts)
+	rdm := rs.RDM("Hidden", metric.Euclidean)
+	assert.Equal(t, 0.0, rdm.Mat.FloatVal([]int{0, 0}))
+	assert.Less(t, rdm.Mat.FloatVal([]int{0, 1}), rdm.Mat.FloatVal([]int{0, 2}))
+	assert.InDelta(t, 1, rs.Pats["Hidden"].Values[0], 1.0e-6) // noise averages out
+
+	assert.Greater(t, rs.Cor("Hidden", "Cat"), 0.8)
+	assert.Greater(t, rs.MeanDist("Hidden"), 0.0)
+	root := rs.Clust("Hidden", clust.Avg)
+	for len(root.Kids) == 1 {
+		root = root.Kids[0]
+	}
+	assert.Equal(t, 2, len(root.Kids)) // two category clusters
+	for _, kd := range root.Kids {
+		assert.Equal(t, 2, len(kd.Kids))
+		assert.Equal(t, kd.Kids[0].Idx/2, kd.Kids[1].Idx/2)
+	}
+
+	// Pearson on a permuted category target is negative
+	rs.Spearman = false
+	anti := &simat.SimMat{}
+	anti.Init()
+	anti.Mat.SetShape([]int{4, 4}, nil, nil)
+	anti.Rows = []string{"A", "C", "B", "D"}
+	anti.Mat.CopyFrom(cat.Mat)
+	rs.SetTarget("Anti", anti)
+	assert.Less(t, rs.Cor("Hidden", "Anti"), 0.0)
+
+	lg := &elog.Logs{}
+	LogAddRSAItems(lg, rs, etime.Test, etime.Epoch)
+	lg.CreateTables()
+	stats := &estats.Stats{}
+	stats.Init()
+	lg.SetContext(stats, net)
+	lg.LogRow(etime.Test, etime.Epoch, 0)
+	dt := lg.Table(etime.Test, etime.Epoch)
+	assert.InDelta(t, rs.Cor("Hidden", "Anti"), dt.CellFloat("Hidden_RSA_Anti", 0), 1.0e-6)
+	assert.InDelta(t, rs.MeanDist("Hidden"), dt.CellFloat("Hidden_RSA_Dist", 0), 1.0e-6)
+
+	rs.Reset()
+	assert.Equal(t, 0, len(rs.Labels))
+	assert.Nil(t, rs.RDM("Hidden", metric.Euclidean))
+	assert.Equal(t, 2, len(rs.Targets))
+
+	bad := &RSA{}
+	bad.Init("NotALayer")
+	assert.Error(t, bad.Record(net, 0, "A"))
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package seqenv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+// OpenText sets the tokens from given text file: individual characters
+// (including spaces, with newlines converted to spaces) if chars is true,
+// else whitespace-separated words.
+func (ev *SeqEnv) OpenText(fname string, chars bool) error {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+	text := string(data)
+	if chars {
+		text = strings.Join(strings.Fields(text), " ")
+	}
+	ev.SetTokens(SplitText(text, chars))
+	return nil
+}
+
+// OpenSymbols sets the tokens from given symbol file, which has
+// whitespace-separated symbols, and lines starting with # are comments.
+func (ev *SeqEnv) OpenSymbols(fname string) error {
+	fp, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	var toks []string
+	scan := bufio.NewScanner(fp)
+	for scan.Scan() {
+		ln := strings.TrimSpace(scan.Text())
+		if strings.HasPrefix(ln, "#") {
+			continue
+		}
+		toks = append(toks, strings.Fields(ln)...)
+	}
+	if err := scan.Err(); err != nil {
+		return err
+	}
+	ev.SetTokens(toks)
+	return nil
+}
+
+// OpenMIDI sets the tokens from the sequence of notes in given track of
+// a MIDI SMF file, as the note numbers, in the order they are turned on
+// (only one note at a time is supported).  The vocabulary is sorted
+// by note number, so that PopCode encoding reflects pitch.
+func (ev *SeqEnv) OpenMIDI(fname string, track int) error {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+	s, err := smf.ReadFrom(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if track < 0 || track >= len(s.Tracks) {
+		return fmt.Errorf("SeqEnv: %s OpenMIDI: track %d out of range for %d tracks in file: %s", ev.Nm, track, len(s.Tracks), fname)
+	}
+	var toks []string
+	for _, evt := range s.Tracks[track] {
+		var channel, note, vel uint8
+		if evt.Message.GetNoteOn(&channel, &note, &vel) && vel > 0 {
+			toks = append(toks, strconv.Itoa(int(note)))
+		}
+	}
+	if len(toks) == 0 {
+		return fmt.Errorf("SeqEnv: %s OpenMIDI: no notes found in track %d of file: %s", ev.Nm, track, fname)
+	}
+	ev.SetTokens(toks)
+	ev.SortVocabNumeric()
+	return nil
+}
+
+// SortVocabNumeric sorts the vocabulary by numerical value,
+// for tokens that are numbers (e.g., MIDI notes) -- non-numeric
+// tokens sort after the numbers, alphabetically.
+func (ev *SeqEnv) SortVocabNumeric() {
+	vocab := make([]string, len(ev.Vocab))
+	copy(vocab, ev.Vocab)
+	sort.SliceStable(vocab, func(i, j int) bool {
+		vi, erri := strconv.ParseFloat(vocab[i], 64)
+		vj, errj := strconv.ParseFloat(vocab[j], 64)
+		switch {
+		case erri == nil && errj == nil:
+			return vi < vj
+		case erri == nil:
+			return true
+		case errj == nil:
+			return false
+		}
+		return vocab[i] < vocab[j]
+	})
+	ev.SetVocab(vocab)
+}
@@ -0,0 +1,425 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package seqenv provides a general sequence environment that presents
+a stream of tokens from a corpus (text, symbol files, MIDI note sequences)
+one token per trial, for predictive learning models (e.g., deep
+predictive models with Input and Pulvinar layers).
+
+The current token is rendered to the Input elements (the driver layers
+for the Pulvinar layers), and the next token to the Next elements
+(e.g., for TargetLayer outputs), using a Localist, PopCode or
+RandSparse encoding.  For data parallel processing, each data index
+reads from a different offset into the corpus (see ConfigNData and StepDi).
+*/
+package seqenv
+
+//go:generate stringer -type=Encodings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emer/axon/axon"
+	"github.com/emer/emergent/env"
+	"github.com/emer/emergent/erand"
+	"github.com/emer/etable/etensor"
+	"github.com/goki/ki/kit"
+)
+
+var KiT_Encodings = kit.Enums.AddEnum(EncodingsN, kit.NotBitFlag, nil)
+
+// Encodings are the ways that tokens are rendered into patterns.
+type Encodings int32
+
+const (
+	// Localist renders each token as a separate group of UnitsPer active
+	// units, with the default shape: [1, NVocab, UnitsPer, 1]
+	Localist Encodings = iota
+
+	// PopCode renders the vocabulary index of each token as a scalar value
+	// in the 0-1 range, using PopCodeParams over PopUnits units, with the
+	// default shape: [1, PopUnits].  This is appropriate for ordered
+	// tokens such as MIDI notes, where nearby values have similar patterns.
+	PopCode
+
+	// RandSparse renders each token as a fixed random pattern with
+	// SparseOn active units out of SparseUnits, generated from RndSeed,
+	// with the default shape: [1, SparseUnits]
+	RandSparse
+
+	EncodingsN
+)
+
+// SeqEnv presents a sequence of tokens, one per trial, rendered
+// according to the Encoding.  The corpus is set by SetTokens, or one of
+// the Open methods, and Config must be called after that to set up the
+// rendering.  For data parallel, call ConfigNData, then Step once per trial
+// and StepDi for each data index, prior to reading the State.
+type SeqEnv struct {
+
+	// name of this environment
+	Nm string `desc:"name of this environment"`
+
+	// description of this environment
+	Dsc string `desc:"description of this environment"`
+
+	// how to render tokens into patterns
+	Encoding Encodings `desc:"how to render tokens into patterns"`
+
+	// [def: 1] [viewif: Encoding=Localist] number of units per localist token, for redundancy in spiking
+	UnitsPer int `viewif:"Encoding=Localist" def:"1" desc:"number of units per localist token, for redundancy in spiking"`
+
+	// [def: 12] [viewif: Encoding=PopCode] number of units in the population code
+	PopUnits int `viewif:"Encoding=PopCode" def:"12" desc:"number of units in the population code"`
+
+	// [view: inline] [viewif: Encoding=PopCode] population code parameters -- vocabulary index is mapped onto the 0-1 range
+	PopCode axon.PopCodeParams `viewif:"Encoding=PopCode" view:"inline" desc:"population code parameters -- vocabulary index is mapped onto the 0-1 range"`
+
+	// [def: 50] [viewif: Encoding=RandSparse] number of units in the random sparse patterns
+	SparseUnits int `viewif:"Encoding=RandSparse" def:"50" desc:"number of units in the random sparse patterns"`
+
+	// [def: 5] [viewif: Encoding=RandSparse] number of active units in each random sparse pattern
+	SparseOn int `viewif:"Encoding=RandSparse" def:"5" desc:"number of active units in each random sparse pattern"`
+
+	// shape of the rendered patterns -- if empty, the default for the Encoding is used -- otherwise must have the same total number of units
+	Shape []int `desc:"shape of the rendered patterns -- if empty, the default for the Encoding is used -- otherwise must have the same total number of units"`
+
+	// [def: ['Input']] names of State elements that return the current token, e.g., the Input layers that drive the Pulvinar layers
+	InputEls []string `def:"['Input']" desc:"names of State elements that return the current token, e.g., the Input layers that drive the Pulvinar layers"`
+
+	// [def: ['Next']] names of State elements that return the next token in the sequence, e.g., TargetLayer outputs
+	NextEls []string `def:"['Next']" desc:"names of State elements that return the next token in the sequence, e.g., TargetLayer outputs"`
+
+	// random seed for the RandSparse patterns
+	RndSeed int64 `desc:"random seed for the RandSparse patterns"`
+
+	// [view: -] the sequence of tokens, as indexes into Vocab
+	Tokens []int `view:"-" desc:"the sequence of tokens, as indexes into Vocab"`
+
+	// the vocabulary of unique tokens, in order of first appearance unless sorted
+	Vocab []string `desc:"the vocabulary of unique tokens, in order of first appearance unless sorted"`
+
+	// [view: -] map from token to index in Vocab
+	VocabMap map[string]int `view:"-" desc:"map from token to index in Vocab"`
+
+	// offset into the sequence for each data parallel index: len(Tokens) / NData
+	DiOffset int `inactive:"+" desc:"offset into the sequence for each data parallel index: len(Tokens) / NData"`
+
+	// [view: no-inline] rendered pattern for each token in Vocab: outer dimension is vocab, inner is Shape
+	Pats etensor.Float32 `view:"no-inline" desc:"rendered pattern for each token in Vocab: outer dimension is vocab, inner is Shape"`
+
+	// [view: inline] current run of model as provided during Init
+	Run env.Ctr `view:"inline" desc:"current run of model as provided during Init"`
+
+	// [view: inline] number of times through the entire sequence
+	Epoch env.Ctr `view:"inline" desc:"number of times through the entire sequence"`
+
+	// [view: inline] trial is the position in the sequence for data index 0
+	Trial env.Ctr `view:"inline" desc:"trial is the position in the sequence for data index 0"`
+
+	// position in the sequence of the current token, for the last StepDi
+	Pos int `inactive:"+" desc:"position in the sequence of the current token, for the last StepDi"`
+
+	// current token, rendered
+	Cur etensor.Float32 `desc:"current token, rendered"`
+
+	// next token, rendered
+	Next etensor.Float32 `desc:"next token, rendered"`
+
+	// [view: -] random number generator for the env
+	Rand erand.SysRand `view:"-" desc:"random number generator for the env"`
+}
+
+func (ev *SeqEnv) Name() string { return ev.Nm }
+func (ev *SeqEnv) Desc() string { return ev.Dsc }
+
+func (ev *SeqEnv) Defaults() {
+	ev.UnitsPer = 1
+	ev.PopUnits = 12
+	ev.SparseUnits = 50
+	ev.SparseOn = 5
+	ev.InputEls = []string{"Input"}
+	ev.NextEls = []string{"Next"}
+	ev.PopCode.Defaults()
+}
+
+// SetTokens sets the sequence of tokens, and the vocabulary of unique tokens,
+// in order of first appearance.
+func (ev *SeqEnv) SetTokens(toks []string) {
+	ev.Vocab = nil
+	ev.VocabMap = make(map[string]int)
+	ev.Tokens = make([]int, len(toks))
+	for i, tk := range toks {
+		ti, ok := ev.VocabMap[tk]
+		if !ok {
+			ti = len(ev.Vocab)
+			ev.VocabMap[tk] = ti
+			ev.Vocab = append(ev.Vocab, tk)
+		}
+		ev.Tokens[i] = ti
+	}
+}
+
+// SetVocab sets the vocabulary to given list of tokens, in the order given,
+// re-mapping the existing Tokens.  Returns an error if any existing token
+// is not in the new vocabulary.  Use this to establish a consistent vocabulary
+// across different environments (e.g., Train and Test), or a meaningful order
+// for PopCode encoding.
+func (ev *SeqEnv) SetVocab(vocab []string) error {
+	nmap := make(map[string]int, len(vocab))
+	for i, tk := range vocab {
+		nmap[tk] = i
+	}
+	for i, ti := range ev.Tokens {
+		tk := ev.Vocab[ti]
+		ni, ok := nmap[tk]
+		if !ok {
+			return fmt.Errorf("SeqEnv: %s SetVocab: token %q not found in new vocabulary", ev.Nm, tk)
+		}
+		ev.Tokens[i] = ni
+	}
+	ev.Vocab = vocab
+	ev.VocabMap = nmap
+	return nil
+}
+
+// NVocab returns the number of unique tokens in the vocabulary
+func (ev *SeqEnv) NVocab() int {
+	return len(ev.Vocab)
+}
+
+// Config configures the rendered patterns for each token according
+// to the current Encoding and vocabulary.  Must be called after
+// the tokens and vocabulary are set.
+func (ev *SeqEnv) Config() {
+	if ev.UnitsPer == 0 {
+		ev.Defaults()
+	}
+	nv := ev.NVocab()
+	shp := ev.Shape
+	if len(shp) == 0 {
+		switch ev.Encoding {
+		case Localist:
+			shp = []int{1, nv, ev.UnitsPer, 1}
+		case PopCode:
+			shp = []int{1, ev.PopUnits}
+		case RandSparse:
+			shp = []int{1, ev.SparseUnits}
+		}
+	}
+	ev.Cur.SetShape(shp, nil, nil)
+	ev.Next.SetShape(shp, nil, nil)
+	n := ev.Cur.Len()
+	ev.Pats.SetShape([]int{nv, n}, nil, []string{"Vocab", "Units"})
+	ev.Pats.SetZeros()
+	switch ev.Encoding {
+	case Localist:
+		for ti := 0; ti < nv; ti++ {
+			for ui := 0; ui < ev.UnitsPer; ui++ {
+				ev.Pats.Values[ti*n+ti*ev.UnitsPer+ui] = 1
+			}
+		}
+	case PopCode:
+		for ti := 0; ti < nv; ti++ {
+			val := float32(0)
+			if nv > 1 {
+				val = float32(ti) / float32(nv-1)
+			}
+			for ui := 0; ui < n; ui++ {
+				ev.Pats.Values[ti*n+ui] = ev.PopCode.EncodeVal(uint32(ui), uint32(n), val)
+			}
+		}
+	case RandSparse:
+		ev.Rand.NewRand(ev.RndSeed)
+		for ti := 0; ti < nv; ti++ {
+			perm := ev.Rand.Perm(n, -1)
+			for _, ui := range perm[:ev.SparseOn] {
+				ev.Pats.Values[ti*n+ui] = 1
+			}
+		}
+	}
+}
+
+// ConfigNData sets the DiOffset for given number of data parallel indexes,
+// so that each data index reads from a different part of the sequence.
+func (ev *SeqEnv) ConfigNData(ndata int) {
+	ev.DiOffset = len(ev.Tokens) / ndata
+	if ev.DiOffset < 1 {
+		ev.DiOffset = 1
+	}
+}
+
+func (ev *SeqEnv) Validate() error {
+	if len(ev.Tokens) < 2 {
+		return fmt.Errorf("SeqEnv: %s must have at least 2 tokens", ev.Nm)
+	}
+	if ev.Pats.Len() == 0 || ev.Pats.Dim(0) != ev.NVocab() {
+		return fmt.Errorf("SeqEnv: %s Config must be called after setting the tokens", ev.Nm)
+	}
+	if ev.Encoding == Localist && ev.Pats.Dim(1) < ev.NVocab()*ev.UnitsPer {
+		return fmt.Errorf("SeqEnv: %s Shape is too small for Localist encoding of %d tokens x %d UnitsPer", ev.Nm, ev.NVocab(), ev.UnitsPer)
+	}
+	if ev.Encoding == RandSparse && ev.SparseOn > ev.Pats.Dim(1) {
+		return fmt.Errorf("SeqEnv: %s SparseOn is larger than the number of units", ev.Nm)
+	}
+	return nil
+}
+
+func (ev *SeqEnv) Counters() []env.TimeScales {
+	return []env.TimeScales{env.Run, env.Epoch, env.Trial}
+}
+
+func (ev *SeqEnv) States() env.Elements {
+	var els env.Elements
+	for _, nm := range ev.InputEls {
+		els = append(els, env.Element{nm, ev.Cur.Shapes(), nil})
+	}
+	for _, nm := range ev.NextEls {
+		els = append(els, env.Element{nm, ev.Next.Shapes(), nil})
+	}
+	return els
+}
+
+func (ev *SeqEnv) State(element string) etensor.Tensor {
+	for _, nm := range ev.InputEls {
+		if nm == element {
+			return &ev.Cur
+		}
+	}
+	for _, nm := range ev.NextEls {
+		if nm == element {
+			return &ev.Next
+		}
+	}
+	return nil
+}
+
+func (ev *SeqEnv) Actions() env.Elements {
+	return nil
+}
+
+// String returns the current token, and the next one
+func (ev *SeqEnv) String() string {
+	return fmt.Sprintf("%s>%s", ev.TokenAt(ev.Pos), ev.TokenAt(ev.Pos+1))
+}
+
+// TokenAt returns the token at given position in the sequence,
+// which wraps around at the end.
+func (ev *SeqEnv) TokenAt(pos int) string {
+	if len(ev.Tokens) == 0 {
+		return ""
+	}
+	return ev.Vocab[ev.Tokens[pos%len(ev.Tokens)]]
+}
+
+func (ev *SeqEnv) Init(run int) {
+	ev.Run.Scale = env.Run
+	ev.Epoch.Scale = env.Epoch
+	ev.Trial.Scale = env.Trial
+	ev.Run.Init()
+	ev.Epoch.Init()
+	ev.Trial.Init()
+	ev.Run.Cur = run
+	ev.Trial.Max = len(ev.Tokens)
+	ev.Trial.Cur = -1 // init state -- key so that first Step() = 0
+	ev.Pos = 0
+}
+
+// Step advances to the next position in the sequence, for data index 0,
+// and renders the tokens there.  For data parallel, call StepDi for
+// each data index after this.
+func (ev *SeqEnv) Step() bool {
+	ev.Epoch.Same() // good idea to just reset all non-inner-most counters at start
+	if ev.Trial.Incr() {
+		ev.Epoch.Incr()
+	}
+	ev.Render(ev.Trial.Cur)
+	return true
+}
+
+// StepDi renders the tokens for given data parallel index,
+// offset from the current trial position by di * DiOffset.
+func (ev *SeqEnv) StepDi(di int) bool {
+	ev.Render(ev.Trial.Cur + di*ev.DiOffset)
+	return true
+}
+
+// Render renders the tokens at given position in the sequence (wrapping
+// around at the end) into Cur, and the following token into Next.
+func (ev *SeqEnv) Render(pos int) {
+	nt := len(ev.Tokens)
+	ev.Pos = pos % nt
+	ev.RenderToken(&ev.Cur, ev.Tokens[ev.Pos])
+	ev.RenderToken(&ev.Next, ev.Tokens[(ev.Pos+1)%nt])
+}
+
+// RenderToken renders given token vocabulary index into given tensor.
+func (ev *SeqEnv) RenderToken(tsr *etensor.Float32, ti int) {
+	n := ev.Pats.Dim(1)
+	tsr.SetZeros()
+	copy(tsr.Values, ev.Pats.Values[ti*n:(ti+1)*n])
+}
+
+// DecodeToken returns the vocabulary index of the token whose pattern
+// is most similar (highest dot product) to given pattern, e.g., the
+// activity of an output layer, and the corresponding token.
+func (ev *SeqEnv) DecodeToken(pat []float32) (int, string) {
+	n := ev.Pats.Dim(1)
+	if len(pat) < n {
+		n = len(pat)
+	}
+	nu := ev.Pats.Dim(1)
+	best := -1
+	max := float32(-1)
+	for ti := 0; ti < ev.NVocab(); ti++ {
+		tp := ev.Pats.Values[ti*nu : ti*nu+n]
+		dp := float32(0)
+		for i, v := range tp {
+			dp += v * pat[i]
+		}
+		if dp > max {
+			max = dp
+			best = ti
+		}
+	}
+	if best < 0 {
+		return -1, ""
+	}
+	return best, ev.Vocab[best]
+}
+
+func (ev *SeqEnv) Action(element string, input etensor.Tensor) {
+	// nop
+}
+
+func (ev *SeqEnv) Counter(scale env.TimeScales) (cur, prv int, chg bool) {
+	switch scale {
+	case env.Run:
+		return ev.Run.Query()
+	case env.Epoch:
+		return ev.Epoch.Query()
+	case env.Trial:
+		return ev.Trial.Query()
+	}
+	return -1, -1, false
+}
+
+// Compile-time check that implements Env interface
+var _ env.Env = (*SeqEnv)(nil)
+
+// SplitText splits given text into tokens: individual characters
+// (including spaces) if chars is true, else whitespace-separated words.
+func SplitText(text string, chars bool) []string {
+	if !chars {
+		return strings.Fields(text)
+	}
+	rs := []rune(text)
+	toks := make([]string, len(rs))
+	for i, r := range rs {
+		toks[i] = string(r)
+	}
+	return toks
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package seqenv
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/emer/emergent/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEnv(enc Encodings) *SeqEnv {
+	ev := &SeqEnv{Nm: "Train"}
+	ev.Defaults()
+	ev.Encoding = enc
+	ev.SetTokens(SplitText("a b c a b d", false))
+	ev.Config()
+	ev.Init(0)
+	return ev
+}
+
+func TestSeqEnvLocalist(t *testing.T) {
+	ev := newTestEnv(Localist)
+	ev.UnitsPer = 2
+	ev.Config()
+	assert.NoError(t, ev.Validate())
+	assert.Equal(t, []string{"a", "b", "c", "d"}, ev.Vocab)
+	assert.Equal(t, []int{1, 4, 2, 1}, ev.Cur.Shapes())
+
+	ev.Step()
+	assert.Equal(t, "a>b", ev.String())
+	assert.Equal(t, float64(1), ev.State("Input").FloatVal1D(0))
+	assert.Equal(t, float64(1), ev.State("Input").FloatVal1D(1))
+	assert.Equal(t, float64(0), ev.State("Input").FloatVal1D(2))
+	assert.Equal(t, float64(1), ev.State("Next").FloatVal1D(2))
+	ti, tk := ev.DecodeToken(ev.Next.Values)
+	assert.Equal(t, 1, ti)
+	assert.Equal(t, "b", tk)
+	assert.Nil(t, ev.State("Other"))
+
+	// wraps around at end of sequence, incrementing epoch
+	for i := 0; i < 5; i++ {
+		ev.Step()
+	}
+	assert.Equal(t, "d>a", ev.String())
+	ev.Step()
+	assert.Equal(t, "a>b", ev.String())
+	assert.Equal(t, 1, env.CounterCur(ev, env.Epoch))
+	assert.Equal(t, 0, env.CounterCur(ev, env.Trial))
+}
+
+func TestSeqEnvNData(t *testing.T) {
+	ev := newTestEnv(Localist)
+	ev.ConfigNData(3)
+	assert.Equal(t, 2, ev.DiOffset)
+	ev.Step()
+	ev.Step()
+	toks := []string{}
+	for di := 0; di < 3; di++ {
+		ev.StepDi(di)
+		toks = append(toks, ev.TokenAt(ev.Pos))
+		_, tk := ev.DecodeToken(ev.Cur.Values)
+		assert.Equal(t, toks[di], tk)
+	}
+	assert.Equal(t, []string{"b", "a", "d"}, toks)
+	ev.StepDi(2)
+	assert.Equal(t, "d>a", ev.String()) // wraps
+}
+
+func TestSeqEnvPopCode(t *testing.T) {
+	ev := newTestEnv(PopCode)
+	ev.SortVocabNumeric()
+	assert.NoError(t, ev.Validate())
+	assert.Equal(t, []int{1, 12}, ev.Cur.Shapes())
+	for ti := 0; ti < ev.NVocab(); ti++ {
+		ev.RenderToken(&ev.Cur, ti)
+		dti, _ := ev.DecodeToken(ev.Cur.Values)
+		assert.Equal(t, ti, dti)
+	}
+	// neighbors in vocab order overlap more than distant ones
+	n := ev.Pats.Dim(1)
+	dot := func(a, b int) float32 {
+		s := float32(0)
+		for i := 0; i < n; i++ {
+			s += ev.Pats.Values[a*n+i] * ev.Pats.Values[b*n+i]
+		}
+		return s
+	}
+	assert.Greater(t, dot(0, 1), dot(0, 3))
+}
+
+func TestSeqEnvRandSparse(t *testing.T) {
+	ev := newTestEnv(RandSparse)
+	ev.RndSeed = 42
+	ev.Config()
+	assert.NoError(t, ev.Validate())
+	n := ev.Pats.Dim(1)
+	for ti := 0; ti < ev.NVocab(); ti++ {
+		non := 0
+		for _, v := range ev.Pats.Values[ti*n : (ti+1)*n] {
+			if v > 0 {
+				non++
+			}
+		}
+		assert.Equal(t, ev.SparseOn, non)
+	}
+	pats := make([]float32, len(ev.Pats.Values))
+	copy(pats, ev.Pats.Values)
+	ev.Config()
+	assert.Equal(t, pats, ev.Pats.Values)
+	ev.Shape = []int{5, 10}
+	ev.Config()
+	assert.Equal(t, []int{5, 10}, ev.Cur.Shapes())
+}
+
+func TestSeqEnvOpen(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "corpus.txt")
+	assert.NoError(t, os.WriteFile(fn, []byte("the cat\nsat  on the mat\n"), 0644))
+	ev := &SeqEnv{}
+	ev.Defaults()
+	assert.NoError(t, ev.OpenText(fn, false))
+	assert.Equal(t, 6, len(ev.Tokens))
+	assert.Equal(t, 5, ev.NVocab())
+	assert.NoError(t, ev.OpenText(fn, true))
+	assert.Equal(t, len("the cat sat on the mat"), len(ev.Tokens))
+	assert.Equal(t, "t", ev.TokenAt(0))
+
+	fn = filepath.Join(dir, "syms.txt")
+	assert.NoError(t, os.WriteFile(fn, []byte("# reber\nB T S X S E\nB P V V E\n"), 0644))
+	assert.NoError(t, ev.OpenSymbols(fn))
+	assert.Equal(t, 11, len(ev.Tokens))
+	assert.Equal(t, []string{"B", "T", "S", "X", "E", "P", "V"}, ev.Vocab)
+
+	assert.NoError(t, ev.OpenMIDI("../../examples/deep_music/bach_goldberg.mid", 1))
+	assert.Greater(t, len(ev.Tokens), 10)
+	prv := -1.0
+	for _, tk := range ev.Vocab {
+		nt, err := strconv.ParseFloat(tk, 64)
+		assert.NoError(t, err)
+		assert.Greater(t, nt, prv)
+		prv = nt
+	}
+	assert.Error(t, ev.OpenMIDI("../../examples/deep_music/bach_goldberg.mid", 100))
+}
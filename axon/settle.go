@@ -0,0 +1,309 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"fmt"
+
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/looper"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+//go:generate stringer -type=SettleCrits
+
+var KiT_SettleCrits = kit.Enums.AddEnum(SettleCritsN, kit.NotBitFlag, nil)
+
+// SettleCrits are the criteria for determining when the activity
+// in a layer has settled, for computing reaction times (RT).
+type SettleCrits int32
+
+const (
+	// SettleThr: the maximum value of the variable across units
+	// in the pool exceeds Thr, as in the standard LayerVals.RT
+	SettleThr SettleCrits = iota
+
+	// SettleWinner: a localist winner emerges: the unit with the maximum
+	// value exceeds Thr, and is at least Margin above the next highest unit,
+	// and remains the winner for StableCycles cycles.
+	SettleWinner
+
+	// SettleStable: the activity pattern stops changing: the maximum
+	// absolute change of any unit across one cycle is below Tol for
+	// StableCycles cycles, once the maximum value exceeds Thr.
+	SettleStable
+
+	SettleCritsN
+)
+
+// SettleParams are parameters for the SettleMonitor
+type SettleParams struct {
+
+	// criterion for determining when the activity has settled
+	Crit SettleCrits `desc:"criterion for determining when the activity has settled"`
+
+	// [def: CaSpkP] neuron variable to monitor
+	Var string `def:"CaSpkP" desc:"neuron variable to monitor"`
+
+	// [def: 0] pool index to monitor: 0 = entire layer, 1+ = sub-pools in a 4D layer
+	Pool int `def:"0" desc:"pool index to monitor: 0 = entire layer, 1+ = sub-pools in a 4D layer"`
+
+	// [def: 0.5] threshold on the maximum value across units, which must be exceeded to count as settled
+	Thr float32 `def:"0.5" desc:"threshold on the maximum value across units, which must be exceeded to count as settled"`
+
+	// [def: 0.1] [viewif: Crit=SettleWinner] for SettleWinner, minimum difference between the winner and the next highest unit
+	Margin float32 `def:"0.1" viewif:"Crit=SettleWinner" desc:"for SettleWinner, minimum difference between the winner and the next highest unit"`
+
+	// [def: 0.01] [viewif: Crit=SettleStable] for SettleStable, maximum absolute change in any unit per cycle
+	Tol float32 `def:"0.01" viewif:"Crit=SettleStable" desc:"for SettleStable, maximum absolute change in any unit per cycle"`
+
+	// [def: 5] [min: 1] for SettleWinner and SettleStable, number of cycles that the criterion must be met in a row -- the RT is the first of these cycles
+	StableCycles int `def:"5" min:"1" desc:"for SettleWinner and SettleStable, number of cycles that the criterion must be met in a row -- the RT is the first of these cycles"`
+
+	// [def: 10] cycle to start checking the criterion, to allow activity from the prior trial to dissipate -- see also Acts.Dt.MaxCycStart
+	StartCyc int `def:"10" desc:"cycle to start checking the criterion, to allow activity from the prior trial to dissipate -- see also Acts.Dt.MaxCycStart"`
+
+	// [def: true] record the settling curve of the average and maximum values over cycles
+	Curve bool `def:"true" desc:"record the settling curve of the average and maximum values over cycles"`
+
+	// [def: false] record the settling curves for each unit in the pool
+	UnitCurves bool `def:"false" desc:"record the settling curves for each unit in the pool"`
+}
+
+func (sp *SettleParams) Defaults() {
+	sp.Crit = SettleThr
+	sp.Var = "CaSpkP"
+	sp.Pool = 0
+	sp.Thr = 0.5
+	sp.Margin = 0.1
+	sp.Tol = 0.01
+	sp.StableCycles = 5
+	sp.StartCyc = 10
+	sp.Curve = true
+	sp.UnitCurves = false
+}
+
+func (sp *SettleParams) Update() {
+}
+
+// SettleMonitor records when the activity in a given layer (or pool within
+// the layer) settles, according to a settling criterion, for each data
+// parallel index, providing reaction times (RT) that are computed in
+// a consistent way for any layer type.  It also records settling curves
+// of the monitored variable (CaSpkP by default) over cycles.
+// Call NewTrial at the start of each trial, and Cycle after each
+// Network.Cycle (see AddToLooper).  When running on the GPU,
+// GPU.CycleByCycle must be true for the state to be available each cycle.
+type SettleMonitor struct {
+
+	// layer to monitor
+	Layer *Layer `desc:"layer to monitor"`
+
+	// settling parameters
+	Params SettleParams `view:"inline" desc:"settling parameters"`
+
+	// [view: -] reaction time in cycles from the start of the trial for each data index: the cycle at which the activity settled, -1 if not yet settled
+	RT []float32 `view:"-" desc:"reaction time in cycles from the start of the trial for each data index: the cycle at which the activity settled, -1 if not yet settled"`
+
+	// [view: -] unit with the maximum value at the time of settling, as an index within the pool, for each data index, -1 if not yet settled
+	Winner []int `view:"-" desc:"unit with the maximum value at the time of settling, as an index within the pool, for each data index, -1 if not yet settled"`
+
+	// [view: -] settling curve of the average value across units for each data index, one value per cycle: [MaxData][Cycles]
+	CurveAvg [][]float32 `view:"-" desc:"settling curve of the average value across units for each data index, one value per cycle: [MaxData][Cycles]"`
+
+	// [view: -] settling curve of the maximum value across units for each data index, one value per cycle: [MaxData][Cycles]
+	CurveMax [][]float32 `view:"-" desc:"settling curve of the maximum value across units for each data index, one value per cycle: [MaxData][Cycles]"`
+
+	// [view: -] settling curves for each unit for each data index, if UnitCurves: [MaxData][Cycles][Units]
+	Curves [][]float32 `view:"-" desc:"settling curves for each unit for each data index, if UnitCurves: [MaxData][Cycles][Units]"`
+
+	// [view: -] current cycle within the trial, counting calls to Cycle since NewTrial
+	Cyc int `view:"-" desc:"current cycle within the trial, counting calls to Cycle since NewTrial"`
+
+	// [view: -] index of the neuron variable
+	VarIdx int `view:"-" desc:"index of the neuron variable"`
+
+	// [view: -] values on the prior cycle for each data index
+	Prv [][]float32 `view:"-" desc:"values on the prior cycle for each data index"`
+
+	// [view: -] number of cycles in a row that the criterion has been met for each data index
+	NStable []int `view:"-" desc:"number of cycles in a row that the criterion has been met for each data index"`
+
+	// [view: -] cycle on which the current run of cycles meeting the criterion started, for each data index
+	StCyc []int `view:"-" desc:"cycle on which the current run of cycles meeting the criterion started, for each data index"`
+
+	// [view: -] winning unit at the start of the current run, for each data index
+	StWin []int `view:"-" desc:"winning unit at the start of the current run, for each data index"`
+}
+
+// NewSettleMonitor returns a new SettleMonitor for given layer,
+// which must have already been built, with default parameters.
+func NewSettleMonitor(ly *Layer) (*SettleMonitor, error) {
+	sm := &SettleMonitor{}
+	sm.Params.Defaults()
+	if err := sm.Init(ly); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+// Init initializes the monitor for given layer, which must have already
+// been built, using the current Params.  Must be called again if the Var
+// or Pool params are changed.
+func (sm *SettleMonitor) Init(ly *Layer) error {
+	sm.Layer = ly
+	vidx, err := ly.UnitVarIdx(sm.Params.Var)
+	if err != nil {
+		return err
+	}
+	if sm.Params.Pool < 0 || sm.Params.Pool >= int(ly.NPools) {
+		return fmt.Errorf("SettleMonitor: Pool %d out of range for layer %s with %d pools", sm.Params.Pool, ly.Nm, ly.NPools)
+	}
+	sm.VarIdx = vidx
+	nd := int(ly.MaxData)
+	nu := sm.NUnits()
+	sm.RT = make([]float32, nd)
+	sm.Winner = make([]int, nd)
+	sm.CurveAvg = make([][]float32, nd)
+	sm.CurveMax = make([][]float32, nd)
+	sm.Curves = make([][]float32, nd)
+	sm.Prv = make([][]float32, nd)
+	sm.NStable = make([]int, nd)
+	sm.StCyc = make([]int, nd)
+	sm.StWin = make([]int, nd)
+	for di := 0; di < nd; di++ {
+		sm.Prv[di] = make([]float32, nu)
+	}
+	sm.NewTrial()
+	return nil
+}
+
+// NUnits returns the number of units in the monitored pool
+func (sm *SettleMonitor) NUnits() int {
+	return int(sm.Layer.Pool(uint32(sm.Params.Pool), 0).NNeurons())
+}
+
+// NewTrial resets the state for a new trial
+func (sm *SettleMonitor) NewTrial() {
+	sm.Cyc = 0
+	for di := range sm.RT {
+		sm.RT[di] = -1
+		sm.Winner[di] = -1
+		sm.CurveAvg[di] = sm.CurveAvg[di][:0]
+		sm.CurveMax[di] = sm.CurveMax[di][:0]
+		sm.Curves[di] = sm.Curves[di][:0]
+		sm.NStable[di] = 0
+		sm.StCyc[di] = -1
+		sm.StWin[di] = -1
+	}
+}
+
+// Settled returns true if the activity has settled for given data index
+func (sm *SettleMonitor) Settled(di int) bool {
+	return sm.RT[di] >= 0
+}
+
+// Cycle records the current values and updates the settling state,
+// for each data index.  Call after each Network.Cycle.
+func (sm *SettleMonitor) Cycle(ctx *Context) {
+	ly := sm.Layer
+	sp := &sm.Params
+	cyc := sm.Cyc
+	for di := 0; di < int(ctx.NetIdxs.NData); di++ {
+		pl := ly.Pool(uint32(sp.Pool), uint32(di))
+		nu := int(pl.EdIdx - pl.StIdx)
+		prv := sm.Prv[di]
+		sum := float32(0)
+		max := float32(0)
+		max2 := float32(0)
+		win := -1
+		maxDif := float32(0)
+		for ui := 0; ui < nu; ui++ {
+			v := ly.UnitVal1D(sm.VarIdx, int(pl.StIdx)+ui, di)
+			sum += v
+			if win < 0 || v > max {
+				max2 = max
+				max = v
+				win = ui
+			} else if v > max2 {
+				max2 = v
+			}
+			maxDif = mat32.Max(maxDif, mat32.Abs(v-prv[ui]))
+			prv[ui] = v
+			if sp.UnitCurves {
+				sm.Curves[di] = append(sm.Curves[di], v)
+			}
+		}
+		if sp.Curve && nu > 0 {
+			sm.CurveAvg[di] = append(sm.CurveAvg[di], sum/float32(nu))
+			sm.CurveMax[di] = append(sm.CurveMax[di], max)
+		}
+		if sm.Settled(di) || cyc < sp.StartCyc {
+			continue
+		}
+		above := max > sp.Thr
+		met := above
+		nreq := 1
+		switch sp.Crit {
+		case SettleWinner:
+			met = above && (max-max2) >= sp.Margin
+			if met && sm.NStable[di] > 0 && win != sm.StWin[di] { // new winner: restart
+				sm.NStable[di] = 0
+			}
+			nreq = sp.StableCycles
+		case SettleStable:
+			met = above && cyc > 0 && maxDif < sp.Tol
+			nreq = sp.StableCycles
+		}
+		if !met {
+			sm.NStable[di] = 0
+			continue
+		}
+		if sm.NStable[di] == 0 {
+			sm.StCyc[di] = cyc
+			sm.StWin[di] = win
+		}
+		sm.NStable[di]++
+		if sm.NStable[di] >= nreq {
+			sm.RT[di] = float32(sm.StCyc[di])
+			sm.Winner[di] = sm.StWin[di]
+		}
+	}
+	sm.Cyc++
+}
+
+// UnitCurve returns the settling curve for given unit index within the pool,
+// for given data index, if UnitCurves is set.
+func (sm *SettleMonitor) UnitCurve(ui, di int) []float32 {
+	nu := sm.NUnits()
+	ncyc := len(sm.Curves[di]) / nu
+	crv := make([]float32, ncyc)
+	for ci := 0; ci < ncyc; ci++ {
+		crv[ci] = sm.Curves[di][ci*nu+ui]
+	}
+	return crv
+}
+
+// AddToLooper adds NewTrial and Cycle calls to the given looper Manager,
+// for all modes, using given trial-level time scale (etime.Trial by default).
+// Must be called after the network Cycle function has been added to the
+// Cycle loop (e.g., by LooperSimCycleAndLearn).
+func (sm *SettleMonitor) AddToLooper(man *looper.Manager, ctx *Context, trial ...etime.Times) {
+	trl := etime.Trial
+	if len(trial) > 0 {
+		trl = trial[0]
+	}
+	nm := "Settle:" + sm.Layer.Nm
+	for m := range man.Stacks {
+		stack := man.Stacks[m]
+		stack.Loops[trl].OnStart.Add(nm+":NewTrial", func() {
+			sm.NewTrial()
+		})
+		stack.Loops[etime.Cycle].Main.Add(nm, func() {
+			sm.Cycle(ctx)
+		})
+	}
+}
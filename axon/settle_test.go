@@ -0,0 +1,101 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/prjn"
+	"github.com/emer/etable/etensor"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSettleTestNet(t *testing.T) (*Network, *Context) {
+	return newTestNetLayers(t, 2, func(net *Network) {
+		inp := net.AddLayer2D("Input", 1, 4, InputLayer)
+		hid := net.AddLayer2D("Hidden", 1, 4, SuperLayer)
+		net.ConnectLayers(inp, hid, prjn.NewOneToOne(), ForwardPrjn)
+	}, nil)
+}
+
+// runSettleTrial runs one trial with given unit on in the Input for each data index
+func runSettleTrial(net *Network, ctx *Context, ons []int, sms ...*SettleMonitor) {
+	inp := net.AxonLayerByName("Input")
+	net.NewState(ctx)
+	ctx.NewState(etime.Test)
+	net.InitExt(ctx)
+	for di, on := range ons {
+		pat := etensor.NewFloat32([]int{1, 4}, nil, nil)
+		pat.Values[on] = 1
+		inp.ApplyExt(ctx, uint32(di), pat)
+	}
+	net.ApplyExts(ctx)
+	for _, sm := range sms {
+		sm.NewTrial()
+	}
+	for cyc := 0; cyc < 150; cyc++ {
+		net.Cycle(ctx)
+		for _, sm := range sms {
+			sm.Cycle(ctx)
+		}
+		ctx.CycleInc()
+	}
+}
+
+func TestSettleMonitor(t *testing.T) {
+	net, ctx := newSettleTestNet(t)
+	inp := net.AxonLayerByName("Input")
+	thr, err := NewSettleMonitor(inp)
+	assert.NoError(t, err)
+	thr.Params.StartCyc = int(inp.Params.Acts.Dt.MaxCycStart)
+	thr.Params.Thr = inp.Params.Acts.AttnMod.RTThr
+	win, err := NewSettleMonitor(inp)
+	assert.NoError(t, err)
+	win.Params.Crit = SettleWinner
+	win.Params.UnitCurves = true
+	stb, err := NewSettleMonitor(inp)
+	assert.NoError(t, err)
+	stb.Params.Crit = SettleStable
+	stb.Params.Var = "Act"
+	assert.NoError(t, stb.Init(inp))
+
+	ons := []int{1, 3}
+	runSettleTrial(net, ctx, ons, thr, win, stb)
+	for di, on := range ons {
+		rt := thr.RT[di]
+		assert.Greater(t, rt, float32(0))
+		assert.InDelta(t, inp.LayerVals(uint32(di)).RT, rt, 1)
+		assert.Equal(t, on, thr.Winner[di])
+		assert.Equal(t, 150, len(thr.CurveMax[di]))
+		assert.Greater(t, thr.CurveMax[di][int(rt)], thr.Params.Thr)
+		assert.LessOrEqual(t, thr.CurveMax[di][int(rt)-1], thr.Params.Thr)
+		assert.GreaterOrEqual(t, thr.CurveMax[di][100], thr.CurveAvg[di][100])
+
+		assert.True(t, win.Settled(di))
+		assert.Equal(t, on, win.Winner[di])
+		assert.GreaterOrEqual(t, win.RT[di], rt)
+		crv := win.UnitCurve(on, di)
+		assert.Equal(t, 150, len(crv))
+		assert.Equal(t, win.CurveMax[di][120], crv[120])
+
+		assert.True(t, stb.Settled(di))
+		assert.Equal(t, on, stb.Winner[di])
+	}
+
+	// not settled with a high threshold, and reset on new trial
+	thr.Params.Thr = 2
+	runSettleTrial(net, ctx, ons, thr)
+	assert.False(t, thr.Settled(0))
+	assert.Equal(t, -1, thr.Winner[1])
+
+	sm := &SettleMonitor{}
+	sm.Params.Defaults()
+	sm.Params.Var = "NotAVar"
+	assert.Error(t, sm.Init(inp))
+	sm.Params.Var = "CaSpkP"
+	sm.Params.Pool = 1
+	assert.Error(t, sm.Init(inp))
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "github.com/emer/emergent/evec"
+
+// ParamConfig has config parameters related to sim params
+type ParamConfig struct {
+
+	// network parameters
+	Network map[string]any `desc:"network parameters"`
+
+	// sizes of layers by name, for layers whose size is configurable, e.g., hidden layers -- see Sim.LayerSize
+	LayerSizes map[string]evec.Vec2i `desc:"sizes of layers by name, for layers whose size is configurable, e.g., hidden layers -- see Sim.LayerSize"`
+
+	// Extra Param Sheet name(s) to use (space separated if multiple) -- must be valid name as listed in compiled-in params or loaded params
+	Sheet string `desc:"Extra Param Sheet name(s) to use (space separated if multiple) -- must be valid name as listed in compiled-in params or loaded params"`
+
+	// extra tag to add to file names and logs saved from this run
+	Tag string `desc:"extra tag to add to file names and logs saved from this run"`
+
+	// user note -- describe the run params etc -- like a git commit message for the run
+	Note string `desc:"user note -- describe the run params etc -- like a git commit message for the run"`
+
+	// Name of the JSON file to input saved parameters from.
+	File string `nest:"+" desc:"Name of the JSON file to input saved parameters from."`
+
+	// Save a snapshot of all current param and config settings in a directory named params_<datestamp> (or _good if Good is true), then quit -- useful for comparing to later changes and seeing multiple views of current params
+	SaveAll bool `nest:"+" desc:"Save a snapshot of all current param and config settings in a directory named params_<datestamp> (or _good if Good is true), then quit -- useful for comparing to later changes and seeing multiple views of current params"`
+
+	// for SaveAll, save to params_good for a known good params state.  This can be done prior to making a new release after all tests are passing -- add results to git to provide a full diff record of all params over time.
+	Good bool `nest:"+" desc:"for SaveAll, save to params_good for a known good params state.  This can be done prior to making a new release after all tests are passing -- add results to git to provide a full diff record of all params over time."`
+}
+
+// RunConfig has config parameters related to running the sim
+type RunConfig struct {
+
+	// [def: true] use the GPU for computation -- generally faster even for small models if NData ~16
+	GPU bool `def:"true" desc:"use the GPU for computation -- generally faster even for small models if NData ~16"`
+
+	// [def: 16] [min: 1] number of data-parallel items to process in parallel per trial -- works (and is significantly faster) for both CPU and GPU.  Results in an effective mini-batch of learning.
+	NData int `def:"16" min:"1" desc:"number of data-parallel items to process in parallel per trial -- works (and is significantly faster) for both CPU and GPU.  Results in an effective mini-batch of learning."`
+
+	// [def: 0] number of parallel threads for CPU computation -- 0 = use default
+	NThreads int `def:"0" desc:"number of parallel threads for CPU computation -- 0 = use default"`
+
+	// [def: 0] starting run number -- determines the random seed -- runs counts from there -- can do all runs in parallel by launching separate jobs with each run, runs = 1
+	Run int `def:"0" desc:"starting run number -- determines the random seed -- runs counts from there -- can do all runs in parallel by launching separate jobs with each run, runs = 1"`
+
+	// [def: 5] [min: 1] total number of runs to do when running Train
+	NRuns int `def:"5" min:"1" desc:"total number of runs to do when running Train"`
+
+	// [def: 100] total number of epochs per run
+	NEpochs int `def:"100" desc:"total number of epochs per run"`
+
+	// [def: 2] stop run after this number of perfect, zero-error epochs
+	NZero int `def:"2" desc:"stop run after this number of perfect, zero-error epochs"`
+
+	// [def: 32] total number of trials per epoch.  Should be an even multiple of NData.
+	NTrials int `def:"32" desc:"total number of trials per epoch.  Should be an even multiple of NData."`
+
+	// [def: 0] total number of trials per testing epoch -- 0 = same as NTrials.  Should be an even multiple of NData.
+	NTestTrials int `def:"0" desc:"total number of trials per testing epoch -- 0 = same as NTrials.  Should be an even multiple of NData."`
+
+	// [def: 200] total number of cycles per trial
+	NCycles int `def:"200" desc:"total number of cycles per trial"`
+
+	// [def: 50] number of cycles in the plus phase, at the end of the trial
+	PlusCycles int `def:"50" desc:"number of cycles in the plus phase, at the end of the trial"`
+
+	// [def: 5] how often to run through all the test patterns, in terms of training epochs -- can use 0 or -1 for no testing
+	TestInterval int `def:"5" desc:"how often to run through all the test patterns, in terms of training epochs -- can use 0 or -1 for no testing"`
+
+	// [def: 5] how frequently (in epochs) to compute PCA on hidden representations to measure variance?
+	PCAInterval int `def:"5" desc:"how frequently (in epochs) to compute PCA on hidden representations to measure variance?"`
+
+	// if non-empty, is the name of weights file to load at start of first run -- for testing
+	StartWts string `desc:"if non-empty, is the name of weights file to load at start of first run -- for testing"`
+}
+
+// LogConfig has config parameters related to logging data
+type LogConfig struct {
+
+	// if true, save final weights after each run
+	SaveWts bool `desc:"if true, save final weights after each run"`
+
+	// [def: true] if true, save train epoch log to file, as .epc.tsv typically
+	Epoch bool `def:"true" nest:"+" desc:"if true, save train epoch log to file, as .epc.tsv typically"`
+
+	// [def: true] if true, save run log to file, as .run.tsv typically
+	Run bool `def:"true" nest:"+" desc:"if true, save run log to file, as .run.tsv typically"`
+
+	// [def: false] if true, save train trial log to file, as .trl.tsv typically. May be large.
+	Trial bool `def:"false" nest:"+" desc:"if true, save train trial log to file, as .trl.tsv typically. May be large."`
+
+	// [def: false] if true, save testing epoch log to file, as .tst_epc.tsv typically.  In general it is better to copy testing items over to the training epoch log and record there.
+	TestEpoch bool `def:"false" nest:"+" desc:"if true, save testing epoch log to file, as .tst_epc.tsv typically.  In general it is better to copy testing items over to the training epoch log and record there."`
+
+	// [def: false] if true, save testing trial log to file, as .tst_trl.tsv typically. May be large.
+	TestTrial bool `def:"false" nest:"+" desc:"if true, save testing trial log to file, as .tst_trl.tsv typically. May be large."`
+
+	// if true, save network activation etc data from testing trials, for later viewing in netview
+	NetData bool `desc:"if true, save network activation etc data from testing trials, for later viewing in netview"`
+}
+
+// Config is the standard Sim config, set by .toml config file and / or args
+type Config struct {
+
+	// specify include files here, and after configuration, it contains list of include files added
+	Includes []string `desc:"specify include files here, and after configuration, it contains list of include files added"`
+
+	// [def: true] open the GUI -- does not automatically run -- if false, then runs automatically and quits
+	GUI bool `def:"true" desc:"open the GUI -- does not automatically run -- if false, then runs automatically and quits"`
+
+	// log debugging information
+	Debug bool `desc:"log debugging information"`
+
+	// [view: add-fields] parameter related configuration options
+	Params ParamConfig `view:"add-fields" desc:"parameter related configuration options"`
+
+	// [view: add-fields] sim running related configuration options
+	Run RunConfig `view:"add-fields" desc:"sim running related configuration options"`
+
+	// [view: add-fields] data logging related configuration options
+	Log LogConfig `view:"add-fields" desc:"data logging related configuration options"`
+}
+
+func (cfg *Config) IncludesPtr() *[]string { return &cfg.Includes }
@@ -0,0 +1,163 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"github.com/emer/emergent/egui"
+	"github.com/emer/emergent/elog"
+	"github.com/emer/emergent/etime"
+	"github.com/emer/empi/mpi"
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gimain"
+	"github.com/goki/mat32"
+)
+
+// Run runs the sim with the GUI if Config.GUI is set, else RunNoGUI.
+// ConfigAll must have been called first.  If Config.Params.SaveAll is set,
+// it instead saves a snapshot of all the params and returns, without running.
+func (ss *Sim) Run() error {
+	if ss.Config.Params.SaveAll {
+		ss.Config.Params.SaveAll = false
+		return ss.Net.SaveParamsSnapshot(&ss.Params.Params, &ss.Config, ss.Config.Params.Good)
+	}
+	if ss.Config.GUI {
+		gimain.Main(ss.RunGUI)
+	} else {
+		ss.RunNoGUI()
+	}
+	return nil
+}
+
+// ConfigGui configures the GoGi gui interface for this simulation,
+// using the Title, About and ReadMe info.
+func (ss *Sim) ConfigGui() *gi.Window {
+	title := ss.Title
+	if title == "" {
+		title = "Axon " + ss.Name
+	}
+	ss.GUI.MakeWindow(ss, ss.Name, title, ss.About)
+	ss.GUI.CycleUpdateInterval = 10
+
+	nv := ss.GUI.AddNetView("NetView")
+	nv.Params.MaxRecs = 300
+	nv.SetNet(ss.Net)
+	ss.ViewUpdt.Config(nv, etime.Phase, etime.Phase)
+	ss.GUI.ViewUpdt = &ss.ViewUpdt
+
+	nv.Scene().Camera.Pose.Pos.Set(0, 1, 2.75) // more "head on" than default which is more "top down"
+	nv.Scene().Camera.LookAt(mat32.Vec3{0, 0, 0}, mat32.Vec3{0, 1, 0})
+
+	ss.GUI.AddPlots(title, &ss.Logs)
+
+	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "Init", Icon: "update",
+		Tooltip: "Initialize everything including network weights, and start over.  Also applies current params.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.Init()
+			ss.GUI.UpdateWindow()
+		},
+	})
+
+	ss.GUI.AddLooperCtrl(ss.Loops, []etime.Modes{etime.Train, etime.Test})
+
+	////////////////////////////////////////////////
+	ss.GUI.ToolBar.AddSeparator("log")
+	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "Reset RunLog",
+		Icon:    "reset",
+		Tooltip: "Reset the accumulated log of all Runs, which are tagged with the ParamSet used",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			ss.Logs.ResetLog(etime.Train, etime.Run)
+			ss.GUI.UpdatePlot(etime.Train, etime.Run)
+		},
+	})
+	////////////////////////////////////////////////
+	ss.GUI.ToolBar.AddSeparator("misc")
+	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "New Seed",
+		Icon:    "new",
+		Tooltip: "Generate a new initial random seed to get different results.  By default, Init re-establishes the same initial seed every time.",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			ss.RndSeeds.NewSeeds()
+		},
+	})
+	if ss.ReadMe != "" {
+		ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "README",
+			Icon:    "file-markdown",
+			Tooltip: "Opens your browser on the README file that contains instructions for how to run this model.",
+			Active:  egui.ActiveAlways,
+			Func: func() {
+				gi.OpenURL(ss.ReadMe)
+			},
+		})
+	}
+	ss.GUI.FinalizeGUI(false)
+	if ss.Config.Run.GPU {
+		ss.Net.ConfigGPUwithGUI(&ss.Context) // must happen after gui or no gui
+		gi.SetQuitCleanFunc(func() {
+			ss.Net.GPU.Destroy()
+		})
+	}
+	return ss.GUI.Win
+}
+
+// RunGUI initializes the sim, opens the standard GUI and starts the event loop.
+func (ss *Sim) RunGUI() {
+	ss.Init()
+	win := ss.ConfigGui()
+	win.StartEventLoop()
+}
+
+// RunNoGUI runs the configured number of training runs without the GUI,
+// saving logs according to Config.Log settings.
+func (ss *Sim) RunNoGUI() {
+	if ss.Config.Params.Note != "" {
+		mpi.Printf("Note: %s\n", ss.Config.Params.Note)
+	}
+	if ss.Config.Log.SaveWts {
+		mpi.Printf("Saving final weights per run\n")
+	}
+	runName := ss.Params.RunName(ss.Config.Run.Run)
+	ss.Stats.SetString("RunName", runName) // used for naming logs, stats, etc
+	netName := ss.Net.Name()
+
+	elog.SetLogFile(&ss.Logs, ss.Config.Log.Trial, etime.Train, etime.Trial, "trl", netName, runName)
+	elog.SetLogFile(&ss.Logs, ss.Config.Log.Epoch, etime.Train, etime.Epoch, "epc", netName, runName)
+	elog.SetLogFile(&ss.Logs, ss.Config.Log.Run, etime.Train, etime.Run, "run", netName, runName)
+	elog.SetLogFile(&ss.Logs, ss.Config.Log.TestEpoch, etime.Test, etime.Epoch, "tst_epc", netName, runName)
+	elog.SetLogFile(&ss.Logs, ss.Config.Log.TestTrial, etime.Test, etime.Trial, "tst_trl", netName, runName)
+
+	netdata := ss.Config.Log.NetData
+	if netdata {
+		mpi.Printf("Saving NetView data from testing\n")
+		ss.GUI.InitNetData(ss.Net, 200)
+	}
+
+	ss.Init()
+
+	mpi.Printf("Running %d Runs starting at %d\n", ss.Config.Run.NRuns, ss.Config.Run.Run)
+	ss.Loops.GetLoop(etime.Train, etime.Run).Counter.SetCurMaxPlusN(ss.Config.Run.Run, ss.Config.Run.NRuns)
+
+	if ss.Config.Run.StartWts != "" { // this is just for testing -- not usually needed
+		ss.Loops.Step(etime.Train, 1, etime.Trial) // get past NewRun
+		ss.Net.OpenWtsJSON(gi.FileName(ss.Config.Run.StartWts))
+		mpi.Printf("Starting with initial weights from: %s\n", ss.Config.Run.StartWts)
+	}
+
+	if ss.Config.Run.GPU {
+		ss.Net.ConfigGPUnoGUI(&ss.Context)
+	}
+	mpi.Printf("Set NThreads to: %d\n", ss.Net.NThreads)
+
+	ss.Loops.Run(etime.Train)
+
+	ss.Logs.CloseLogFiles()
+
+	if netdata {
+		ss.GUI.SaveNetData(ss.Stats.String("RunName"))
+	}
+
+	ss.Net.GPU.Destroy() // safe even if no GPU
+}
@@ -0,0 +1,574 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package sim provides a standard, data-parallel-aware simulation driver for
+axon models, implementing the Sim boilerplate (loops, inputs, stats, logs,
+GUI and nogui running) that is otherwise repeated across the examples.
+
+A model supplies its network builder (ConfigNetFunc), environment factory
+(NewEnv), and optionally the input / output layer mapping, extra stats
+(TrialStatsFunc), log items (ConfigLogsFunc) and loop functions (ConfigLoopsFunc):
+
+	ss := sim.New("RA25", ParamSets)
+	ss.ConfigNetFunc = func(ss *sim.Sim, net *axon.Network) { ... add layers, prjns ... }
+	ss.NewEnv = sim.FixedTableEnvs(pats)
+	ss.LoadConfig("config.toml")
+	if err := ss.ConfigAll(); err != nil {
+		log.Fatal(err)
+	}
+	if err := ss.Run(); err != nil {
+		log.Fatal(err)
+	}
+*/
+package sim
+
+import (
+	"fmt"
+
+	"github.com/emer/axon/axon"
+	"github.com/emer/emergent/econfig"
+	"github.com/emer/emergent/egui"
+	"github.com/emer/emergent/elog"
+	"github.com/emer/emergent/emer"
+	"github.com/emer/emergent/env"
+	"github.com/emer/emergent/erand"
+	"github.com/emer/emergent/estats"
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/evec"
+	"github.com/emer/emergent/looper"
+	"github.com/emer/emergent/netparams"
+	"github.com/emer/emergent/netview"
+	"github.com/emer/empi/mpi"
+	"github.com/emer/etable/etable"
+	"github.com/goki/mat32"
+)
+
+// Sim encapsulates the entire simulation model, including the standard
+// state and the model-specific functions that configure it.
+// The functions must be set prior to calling ConfigAll.
+type Sim struct {
+
+	// name of the sim, used for the network and in file names
+	Name string `desc:"name of the sim, used for the network and in file names"`
+
+	// window title for the GUI -- defaults to Axon + Name
+	Title string `desc:"window title for the GUI -- defaults to Axon + Name"`
+
+	// description of the model shown in the GUI, can use html
+	About string `desc:"description of the model shown in the GUI, can use html"`
+
+	// url of the README file for the model, opened from the GUI toolbar if set
+	ReadMe string `desc:"url of the README file for the model, opened from the GUI toolbar if set"`
+
+	// simulation configuration parameters -- set by .toml config file and / or args
+	Config Config `desc:"simulation configuration parameters -- set by .toml config file and / or args"`
+
+	// [view: no-inline] the network -- click to view / edit parameters for layers, prjns, etc
+	Net *axon.Network `view:"no-inline" desc:"the network -- click to view / edit parameters for layers, prjns, etc"`
+
+	// [view: inline] network parameter management
+	Params emer.NetParams `view:"inline" desc:"network parameter management"`
+
+	// [view: no-inline] contains looper control loops for running sim
+	Loops *looper.Manager `view:"no-inline" desc:"contains looper control loops for running sim"`
+
+	// contains computed statistic values
+	Stats estats.Stats `desc:"contains computed statistic values"`
+
+	// Contains all the logs and information about the logs.'
+	Logs elog.Logs `desc:"Contains all the logs and information about the logs.'"`
+
+	// [view: no-inline] Environments
+	Envs env.Envs `view:"no-inline" desc:"Environments"`
+
+	// axon timing parameters and state
+	Context axon.Context `desc:"axon timing parameters and state"`
+
+	// [view: inline] netview update parameters
+	ViewUpdt netview.ViewUpdt `view:"inline" desc:"netview update parameters"`
+
+	// [view: -] names of the layers that receive external input from the environment, using the layer name as the env State element name -- defaults to all InputLayer and TargetLayer layers
+	Inputs []string `view:"-" desc:"names of the layers that receive external input from the environment, using the layer name as the env State element name -- defaults to all InputLayer and TargetLayer layers"`
+
+	// [view: -] names of the output layers used for the standard UnitErr, CorSim and TrlErr stats, averaged across layers -- defaults to all TargetLayer layers
+	Outputs []string `view:"-" desc:"names of the output layers used for the standard UnitErr, CorSim and TrlErr stats, averaged across layers -- defaults to all TargetLayer layers"`
+
+	// [view: -] adds the layers and projections to the network -- required.  The network is then built, and params applied, by ConfigNet.
+	ConfigNetFunc func(ss *Sim, net *axon.Network) `view:"-" desc:"adds the layers and projections to the network -- required.  The network is then built, and params applied, by ConfigNet."`
+
+	// [view: -] returns a new environment for given mode (Train, Test) -- required.  The environment Name() must be the mode String().
+	NewEnv func(ss *Sim, mode etime.Modes) env.Env `view:"-" desc:"returns a new environment for given mode (Train, Test) -- required.  The environment Name() must be the mode String()."`
+
+	// [view: -] computes additional trial-level statistics for given data index, after the standard ones
+	TrialStatsFunc func(ss *Sim, di int) `view:"-" desc:"computes additional trial-level statistics for given data index, after the standard ones"`
+
+	// [view: -] initializes additional statistics at the start of each run
+	InitStatsFunc func(ss *Sim) `view:"-" desc:"initializes additional statistics at the start of each run"`
+
+	// [view: -] adds additional log items, before the log tables are created
+	ConfigLogsFunc func(ss *Sim) `view:"-" desc:"adds additional log items, before the log tables are created"`
+
+	// [view: -] adds additional functions to the looper, after the standard ones
+	ConfigLoopsFunc func(ss *Sim, man *looper.Manager) `view:"-" desc:"adds additional functions to the looper, after the standard ones"`
+
+	// [view: -] compiled-in param sets
+	ParamSets netparams.Sets `view:"-" desc:"compiled-in param sets"`
+
+	// [view: -] manages all the gui elements
+	GUI egui.GUI `view:"-" desc:"manages all the gui elements"`
+
+	// [view: -] a list of random seeds to use for each run
+	RndSeeds erand.Seeds `view:"-" desc:"a list of random seeds to use for each run"`
+}
+
+// New returns a new Sim with given name and compiled-in param sets,
+// with Config initialized from the default values.
+func New(name string, paramSets netparams.Sets) *Sim {
+	ss := &Sim{}
+	ss.Name = name
+	ss.ParamSets = paramSets
+	econfig.SetFromDefaults(&ss.Config)
+	ss.Net = &axon.Network{}
+	ss.Stats.Init()
+	ss.RndSeeds.Init(100) // max 100 runs
+	ss.InitRndSeed(0)
+	ss.Context.Defaults()
+	return ss
+}
+
+// LoadConfig sets the Config from given default config file (e.g., config.toml)
+// and command line args, using econfig.Config.
+func (ss *Sim) LoadConfig(defaultFile ...string) {
+	econfig.Config(&ss.Config, defaultFile...)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////
+// 		Configs
+
+// ConfigAll configures all the elements using the standard functions,
+// returning any error from building the network.
+func (ss *Sim) ConfigAll() error {
+	ss.Params.Config(ss.ParamSets, ss.Config.Params.Sheet, ss.Config.Params.Tag, ss.Net)
+	ss.ConfigEnv()
+	if err := ss.ConfigNet(ss.Net); err != nil {
+		return err
+	}
+	ss.ConfigLogs()
+	ss.ConfigLoops()
+	return nil
+}
+
+// ConfigEnv creates the Train and Test environments using NewEnv.
+// Can be called multiple times -- replaces existing envs.
+func (ss *Sim) ConfigEnv() {
+	ss.Envs.Init()
+	for _, mode := range []etime.Modes{etime.Train, etime.Test} {
+		ev := ss.NewEnv(ss, mode)
+		if err := ev.Validate(); err != nil {
+			mpi.Println(err)
+		}
+		ev.Init(0)
+		ss.Envs.Add(ev)
+	}
+}
+
+// ConfigNet configures the network using ConfigNetFunc, and then builds it,
+// applies params, and initializes the weights.  Also sets the default
+// Inputs and Outputs if not otherwise specified.
+// Returns an error if the network fails to build.
+func (ss *Sim) ConfigNet(net *axon.Network) error {
+	ctx := &ss.Context
+	net.InitName(net, ss.Name)
+	net.SetMaxData(ctx, ss.Config.Run.NData)
+	net.SetRndSeed(ss.RndSeeds[0]) // init new separate random seed, using run = 0
+
+	ss.ConfigNetFunc(ss, net)
+
+	if err := net.Build(ctx); err != nil {
+		return err
+	}
+	net.Defaults()
+	net.SetNThreads(ss.Config.Run.NThreads)
+	ss.ApplyParams()
+	net.InitWts(ctx)
+
+	if ss.Inputs == nil {
+		ss.Inputs = net.LayersByType(axon.InputLayer, axon.TargetLayer)
+	}
+	if ss.Outputs == nil {
+		ss.Outputs = net.LayersByType(axon.TargetLayer)
+	}
+	return nil
+}
+
+// LayerSize returns the size of the layer of given name from
+// Config.Params.LayerSizes, or the given default size if not set there,
+// for use in ConfigNetFunc for layers whose size is configurable.
+func (ss *Sim) LayerSize(name string, def evec.Vec2i) evec.Vec2i {
+	if sz, ok := ss.Config.Params.LayerSizes[name]; ok {
+		return sz
+	}
+	return def
+}
+
+func (ss *Sim) ApplyParams() {
+	ss.Params.SetAll()
+	if ss.Config.Params.Network != nil {
+		ss.Params.SetNetworkMap(ss.Net, ss.Config.Params.Network)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// 	    Init, utils
+
+// Init restarts the run, and initializes everything, including network weights
+// and resets the epoch log table
+func (ss *Sim) Init() {
+	if ss.Config.GUI {
+		ss.Stats.SetString("RunName", ss.Params.RunName(0)) // in case user interactively changes tag
+	}
+	ss.Loops.ResetCounters()
+	ss.InitRndSeed(0)
+	ss.GUI.StopNow = false
+	ss.ApplyParams()
+	ss.Net.GPU.SyncParamsToGPU()
+	ss.NewRun()
+	ss.ViewUpdt.Update()
+	ss.ViewUpdt.RecordSyns()
+}
+
+// InitRndSeed initializes the random seed based on current training run number
+func (ss *Sim) InitRndSeed(run int) {
+	ss.RndSeeds.Set(run)
+	ss.RndSeeds.Set(run, &ss.Net.Rand)
+}
+
+// ConfigLoops configures the control loops: Training, Testing
+func (ss *Sim) ConfigLoops() {
+	man := looper.NewManager()
+
+	rc := &ss.Config.Run
+	trls := int(mat32.IntMultipleGE(float32(rc.NTrials), float32(rc.NData)))
+	tstTrls := trls
+	if rc.NTestTrials > 0 {
+		tstTrls = int(mat32.IntMultipleGE(float32(rc.NTestTrials), float32(rc.NData)))
+	}
+
+	man.AddStack(etime.Train).
+		AddTime(etime.Run, rc.NRuns).
+		AddTime(etime.Epoch, rc.NEpochs).
+		AddTimeIncr(etime.Trial, trls, rc.NData).
+		AddTime(etime.Cycle, rc.NCycles)
+
+	man.AddStack(etime.Test).
+		AddTime(etime.Epoch, 1).
+		AddTimeIncr(etime.Trial, tstTrls, rc.NData).
+		AddTime(etime.Cycle, rc.NCycles)
+
+	axon.LooperStdPhases(man, &ss.Context, ss.Net, rc.NCycles-rc.PlusCycles, rc.NCycles-1) // plus phase timing
+	axon.LooperSimCycleAndLearn(man, ss.Net, &ss.Context, &ss.ViewUpdt)                    // std algo code
+
+	for m, _ := range man.Stacks {
+		mode := m // For closures
+		stack := man.Stacks[mode]
+		stack.Loops[etime.Trial].OnStart.Add("ApplyInputs", func() {
+			ss.ApplyInputs()
+		})
+	}
+
+	man.GetLoop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
+
+	// Train stop early condition
+	man.GetLoop(etime.Train, etime.Epoch).IsDone["NZeroStop"] = func() bool {
+		// This is calculated in TrialStats
+		stopNz := ss.Config.Run.NZero
+		if stopNz <= 0 {
+			stopNz = 2
+		}
+		curNZero := ss.Stats.Int("NZero")
+		stop := curNZero >= stopNz
+		return stop
+	}
+
+	// Add Testing
+	trainEpoch := man.GetLoop(etime.Train, etime.Epoch)
+	trainEpoch.OnStart.Add("TestAtInterval", func() {
+		if (ss.Config.Run.TestInterval > 0) && ((trainEpoch.Counter.Cur+1)%ss.Config.Run.TestInterval == 0) {
+			// Note the +1 so that it doesn't occur at the 0th timestep.
+			ss.TestAll()
+		}
+	})
+
+	/////////////////////////////////////////////
+	// Logging
+
+	man.GetLoop(etime.Test, etime.Epoch).OnEnd.Add("LogTestErrors", func() {
+		axon.LogTestErrors(&ss.Logs)
+	})
+	man.GetLoop(etime.Train, etime.Epoch).OnEnd.Add("PCAStats", func() {
+		trnEpc := man.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
+		if ss.Config.Run.PCAInterval > 0 && trnEpc%ss.Config.Run.PCAInterval == 0 {
+			axon.PCAStats(ss.Net, &ss.Logs, &ss.Stats)
+			ss.Logs.ResetLog(etime.Analyze, etime.Trial)
+		}
+	})
+
+	man.AddOnEndToAll("Log", ss.Log)
+	axon.LooperResetLogBelow(man, &ss.Logs)
+
+	man.GetLoop(etime.Train, etime.Trial).OnEnd.Add("LogAnalyze", func() {
+		trnEpc := man.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
+		if (ss.Config.Run.PCAInterval > 0) && (trnEpc%ss.Config.Run.PCAInterval == 0) {
+			ss.Log(etime.Analyze, etime.Trial)
+		}
+	})
+
+	man.GetLoop(etime.Train, etime.Run).OnEnd.Add("RunStats", func() {
+		ss.Logs.RunStats("PctCor", "FirstZero", "LastZero")
+	})
+
+	// Save weights to file, to look at later
+	man.GetLoop(etime.Train, etime.Run).OnEnd.Add("SaveWeights", func() {
+		ctrString := ss.Stats.PrintVals([]string{"Run", "Epoch"}, []string{"%03d", "%05d"}, "_")
+		axon.SaveWeightsIfConfigSet(ss.Net, ss.Config.Log.SaveWts, ctrString, ss.Stats.String("RunName"))
+	})
+
+	if ss.ConfigLoopsFunc != nil {
+		ss.ConfigLoopsFunc(ss, man)
+	}
+
+	////////////////////////////////////////////
+	// GUI
+
+	if !ss.Config.GUI {
+		if ss.Config.Log.NetData {
+			man.GetLoop(etime.Test, etime.Trial).Main.Add("NetDataRecord", func() {
+				ss.GUI.NetDataRecord(ss.ViewUpdt.Text)
+			})
+		}
+	} else {
+		axon.LooperUpdtNetView(man, &ss.ViewUpdt, ss.Net, ss.NetViewCounters)
+		axon.LooperUpdtPlots(man, &ss.GUI)
+	}
+
+	if ss.Config.Debug {
+		mpi.Println(man.DocString())
+	}
+	ss.Loops = man
+}
+
+// ApplyInputs applies input patterns from the environment for the current mode
+// to the Inputs layers, stepping the environment once per data index.
+func (ss *Sim) ApplyInputs() {
+	ctx := &ss.Context
+	net := ss.Net
+	ev := ss.Envs.ByMode(ctx.Mode)
+	net.InitExt(ctx)
+	for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
+		ev.Step()
+		// note: must save env state for logging / stats due to data parallel re-use of same env
+		ss.Stats.SetStringDi("TrialName", int(di), EnvTrialName(ev))
+		for _, lnm := range ss.Inputs {
+			ly := net.AxonLayerByName(lnm)
+			pats := ev.State(ly.Nm)
+			if pats != nil {
+				ly.ApplyExt(ctx, di, pats)
+			}
+		}
+	}
+	net.ApplyExts(ctx) // now required for GPU mode
+}
+
+// EnvTrialName returns the current trial name for given environment:
+// the TrialName for an env.FixedTable, or the String() value
+// if it implements fmt.Stringer, else an empty string.
+func EnvTrialName(ev env.Env) string {
+	switch et := ev.(type) {
+	case *env.FixedTable:
+		return et.TrialName.Cur
+	case fmt.Stringer:
+		return et.String()
+	}
+	return ""
+}
+
+// NewRun intializes a new run of the model, using the TrainEnv.Run counter
+// for the new run value
+func (ss *Sim) NewRun() {
+	ctx := &ss.Context
+	ss.InitRndSeed(ss.Loops.GetLoop(etime.Train, etime.Run).Counter.Cur)
+	ss.Envs.ByMode(etime.Train).Init(0)
+	ss.Envs.ByMode(etime.Test).Init(0)
+	ctx.Reset()
+	ctx.Mode = etime.Train
+	ss.Net.InitWts(ctx)
+	ss.InitStats()
+	ss.StatCounters(0)
+	ss.Logs.ResetLog(etime.Train, etime.Epoch)
+	ss.Logs.ResetLog(etime.Test, etime.Epoch)
+}
+
+// TestAll runs through the full set of testing items
+func (ss *Sim) TestAll() {
+	ss.Envs.ByMode(etime.Test).Init(0)
+	ss.Loops.ResetAndRun(etime.Test)
+	ss.Loops.Mode = etime.Train // Important to reset Mode back to Train because this is called from within the Train Run.
+}
+
+// FixedTableEnvs returns a NewEnv function that creates env.FixedTable
+// environments over given table of patterns, with permuted order
+// for Train and sequential order for Test.  The table must have
+// columns named for the Inputs layers.
+func FixedTableEnvs(pats *etable.Table) func(ss *Sim, mode etime.Modes) env.Env {
+	return func(ss *Sim, mode etime.Modes) env.Env {
+		ev := &env.FixedTable{}
+		ev.Nm = mode.String()
+		ev.Dsc = mode.String() + " params and state"
+		ev.Config(etable.NewIdxView(pats))
+		ev.Sequential = mode != etime.Train
+		return ev
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////
+// 		Stats
+
+// InitStats initializes all the statistics.
+// called at start of new run
+func (ss *Sim) InitStats() {
+	ss.Stats.SetFloat("UnitErr", 0.0)
+	ss.Stats.SetFloat("CorSim", 0.0)
+	ss.Stats.SetString("TrialName", "")
+	ss.Logs.InitErrStats() // inits TrlErr, FirstZero, LastZero, NZero
+	if ss.InitStatsFunc != nil {
+		ss.InitStatsFunc(ss)
+	}
+}
+
+// StatCounters saves current counters to Stats, so they are available for logging etc
+// Also saves a string rep of them for ViewUpdt.Text
+func (ss *Sim) StatCounters(di int) {
+	ctx := &ss.Context
+	mode := ctx.Mode
+	ss.Loops.Stacks[mode].CtrsToStats(&ss.Stats)
+	// always use training epoch..
+	trnEpc := ss.Loops.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
+	ss.Stats.SetInt("Epoch", trnEpc)
+	trl := ss.Stats.Int("Trial")
+	ss.Stats.SetInt("Trial", trl+di)
+	ss.Stats.SetInt("Di", di)
+	ss.Stats.SetInt("Cycle", int(ctx.Cycle))
+	ss.Stats.SetString("TrialName", ss.Stats.StringDi("TrialName", di))
+}
+
+func (ss *Sim) NetViewCounters(tm etime.Times) {
+	if ss.ViewUpdt.View == nil {
+		return
+	}
+	di := ss.ViewUpdt.View.Di
+	if tm == etime.Trial {
+		ss.TrialStats(di) // get trial stats for current di
+	}
+	ss.StatCounters(di)
+	ss.ViewUpdt.Text = ss.Stats.Print([]string{"Run", "Epoch", "Trial", "Di", "TrialName", "Cycle", "UnitErr", "TrlErr", "CorSim"})
+}
+
+// TrialStats computes the trial-level statistics, averaging UnitErr and
+// CorSim across the Outputs layers, and then calls TrialStatsFunc if set.
+// Aggregation is done directly from log data.
+func (ss *Sim) TrialStats(di int) {
+	ctx := &ss.Context
+	unitErr := 0.0
+	corSim := 0.0
+	if nout := len(ss.Outputs); nout > 0 {
+		for _, lnm := range ss.Outputs {
+			ly := ss.Net.AxonLayerByName(lnm)
+			corSim += float64(ly.Vals[di].CorSim.Cor)
+			unitErr += ly.PctUnitErr(ctx)[di]
+		}
+		corSim /= float64(nout)
+		unitErr /= float64(nout)
+	}
+	ss.Stats.SetFloat("CorSim", corSim)
+	ss.Stats.SetFloat("UnitErr", unitErr)
+
+	if unitErr > 0 {
+		ss.Stats.SetFloat("TrlErr", 1)
+	} else {
+		ss.Stats.SetFloat("TrlErr", 0)
+	}
+	if ss.TrialStatsFunc != nil {
+		ss.TrialStatsFunc(ss, di)
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////////
+// 		Logging
+
+func (ss *Sim) ConfigLogs() {
+	ss.Stats.SetString("RunName", ss.Params.RunName(0)) // used for naming logs, stats, etc
+
+	ss.Logs.AddCounterItems(etime.Run, etime.Epoch, etime.Trial, etime.Cycle)
+	ss.Logs.AddStatIntNoAggItem(etime.AllModes, etime.Trial, "Di")
+	ss.Logs.AddStatStringItem(etime.AllModes, etime.AllTimes, "RunName")
+	ss.Logs.AddStatStringItem(etime.AllModes, etime.Trial, "TrialName")
+
+	ss.Logs.AddStatAggItem("CorSim", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatAggItem("UnitErr", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddErrStatAggItems("TrlErr", etime.Run, etime.Epoch, etime.Trial)
+
+	ss.Logs.AddCopyFromFloatItems(etime.Train, []etime.Times{etime.Epoch, etime.Run}, etime.Test, etime.Epoch, "Tst", "CorSim", "UnitErr", "PctCor", "PctErr")
+
+	ss.Logs.AddPerTrlMSec("PerTrlMSec", etime.Run, etime.Epoch, etime.Trial)
+
+	layers := ss.Net.LayersByType(axon.SuperLayer, axon.CTLayer, axon.TargetLayer)
+	axon.LogAddDiagnosticItems(&ss.Logs, layers, etime.Train, etime.Epoch, etime.Trial)
+	axon.LogInputLayer(&ss.Logs, ss.Net, etime.Train)
+
+	axon.LogAddPCAItems(&ss.Logs, ss.Net, etime.Train, etime.Run, etime.Epoch, etime.Trial)
+
+	ss.Logs.AddLayerTensorItems(ss.Net, "Act", etime.Test, etime.Trial, "InputLayer", "TargetLayer")
+
+	if ss.ConfigLogsFunc != nil {
+		ss.ConfigLogsFunc(ss)
+	}
+
+	ss.Logs.PlotItems("CorSim", "PctCor", "FirstZero", "LastZero")
+
+	ss.Logs.CreateTables()
+	ss.Logs.SetContext(&ss.Stats, ss.Net)
+	// don't plot certain combinations we don't use
+	ss.Logs.NoPlot(etime.Train, etime.Cycle)
+	ss.Logs.NoPlot(etime.Test, etime.Run)
+	// note: Analyze not plotted by default
+	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
+}
+
+// Log is the main logging function, handles special things for different scopes
+func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
+	ctx := &ss.Context
+	if mode != etime.Analyze {
+		ctx.Mode = mode // Also set specifically in a Loop callback.
+	}
+	dt := ss.Logs.Table(mode, time)
+	if dt == nil {
+		return
+	}
+	row := dt.Rows
+
+	switch {
+	case time == etime.Cycle:
+		return
+	case time == etime.Trial:
+		for di := 0; di < int(ctx.NetIdxs.NData); di++ {
+			ss.TrialStats(di)
+			ss.StatCounters(di)
+			ss.Logs.LogRowDi(mode, time, row, di)
+		}
+		return // don't do reg below
+	}
+
+	ss.Logs.LogRow(mode, time, row) // also logs to file, etc
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"testing"
+
+	"github.com/emer/axon/axon"
+	"github.com/emer/emergent/etime"
+	"github.com/emer/emergent/netparams"
+	"github.com/emer/emergent/params"
+	"github.com/emer/emergent/patgen"
+	"github.com/emer/emergent/prjn"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/stretchr/testify/assert"
+)
+
+var testParamSets = netparams.Sets{
+	"Base": {
+		{Sel: "Layer", Desc: "all defaults",
+			Params: params.Params{
+				"Layer.Inhib.ActAvg.Nominal": "0.2",
+			}},
+	},
+}
+
+func newTestSim(t *testing.T) *Sim {
+	pats := &etable.Table{}
+	pats.SetFromSchema(etable.Schema{
+		{"Name", etensor.STRING, nil, nil},
+		{"Input", etensor.FLOAT32, []int{4, 4}, []string{"Y", "X"}},
+		{"Output", etensor.FLOAT32, []int{4, 4}, []string{"Y", "X"}},
+	}, 4)
+	patgen.PermutedBinaryRows(pats.Cols[1], 3, 1, 0)
+	patgen.PermutedBinaryRows(pats.Cols[2], 3, 1, 0)
+	for i := 0; i < 4; i++ {
+		pats.SetCellString("Name", i, string(rune('A'+i)))
+	}
+
+	ss := New("SimTest", testParamSets)
+	assert.Equal(t, 200, ss.Config.Run.NCycles)
+	assert.True(t, ss.Config.Log.Epoch)
+	ss.Config.GUI = false
+	ss.Config.Run.GPU = false
+	ss.Config.Run.NData = 2
+	ss.Config.Run.NRuns = 1
+	ss.Config.Run.NEpochs = 3
+	ss.Config.Run.NTrials = 4
+	ss.Config.Run.TestInterval = 2
+	ss.Config.Run.PCAInterval = 2
+	ss.Config.Log.Epoch = false
+	ss.Config.Log.Run = false
+
+	ss.ConfigNetFunc = func(ss *Sim, net *axon.Network) {
+		inp := net.AddLayer2D("Input", 4, 4, axon.InputLayer)
+		hid := net.AddLayer2D("Hidden", 6, 6, axon.SuperLayer)
+		out := net.AddLayer2D("Output", 4, 4, axon.TargetLayer)
+		full := prjn.NewFull()
+		net.ConnectLayers(inp, hid, full, axon.ForwardPrjn)
+		net.BidirConnectLayers(hid, out, full)
+	}
+	ss.NewEnv = FixedTableEnvs(pats)
+	return ss
+}
+
+func TestSimRun(t *testing.T) {
+	ss := newTestSim(t)
+	nstats := 0
+	ss.TrialStatsFunc = func(ss *Sim, di int) {
+		nstats++
+		ss.Stats.SetFloat("Extra", float64(di))
+	}
+	ss.ConfigLogsFunc = func(ss *Sim) {
+		ss.Logs.AddStatAggItem("Extra", etime.Run, etime.Epoch, etime.Trial)
+	}
+	assert.NoError(t, ss.ConfigAll())
+	assert.Equal(t, []string{"Input", "Output"}, ss.Inputs)
+	assert.Equal(t, []string{"Output"}, ss.Outputs)
+	assert.Equal(t, etime.Train.String(), ss.Envs.ByMode(etime.Train).Name())
+
+	ss.RunNoGUI()
+
+	epc := ss.Logs.Table(etime.Train, etime.Epoch)
+	assert.Equal(t, 3, epc.Rows)
+	assert.NotNil(t, epc.ColByName("Extra"))
+	assert.NotNil(t, epc.ColByName("TstPctErr"))
+	assert.Greater(t, nstats, 0)
+
+	trl := ss.Logs.Table(etime.Test, etime.Trial)
+	assert.Equal(t, 4, trl.Rows)
+	names := map[string]bool{}
+	for i := 0; i < trl.Rows; i++ {
+		names[trl.CellString("TrialName", i)] = true
+		assert.Equal(t, float64(i%2), trl.CellFloat("Extra", i))
+	}
+	assert.Equal(t, 4, len(names))
+	assert.Equal(t, 1, ss.Logs.Table(etime.Train, etime.Run).Rows)
+}
+
+func TestSimBuildErr(t *testing.T) {
+	ss := newTestSim(t)
+	cfgNet := ss.ConfigNetFunc
+	ss.ConfigNetFunc = func(ss *Sim, net *axon.Network) {
+		cfgNet(ss, net)
+		net.AddLayer2D("Empty", 0, 0, axon.SuperLayer)
+	}
+	assert.Error(t, ss.ConfigAll())
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/params"
+	"github.com/emer/emergent/prjn"
+	"github.com/stretchr/testify/assert"
+)
+
+// testnet_test.go has the test networks shared across tests,
+// which are not behind any build tags.
+
+// Note: subsequent params applied after Base
+var ParamSets = params.Sets{
+	"Base": {Desc: "base testing", Sheets: params.Sheets{
+		"Network": &params.Sheet{
+			{Sel: "Layer", Desc: "layer defaults",
+				Params: params.Params{
+					"Layer.Acts.Gbar.L":     "0.2",
+					"Layer.Learn.RLRate.On": "false",
+					"Layer.Inhib.Layer.FB":  "0.5",
+				}},
+			{Sel: "Prjn", Desc: "for reproducibility, identical weights",
+				Params: params.Params{
+					"Prjn.SWts.Init.Var": "0",
+				}},
+			{Sel: ".BackPrjn", Desc: "top-down back-projections MUST have lower relative weight scale, otherwise network hallucinates",
+				Params: params.Params{
+					"Prjn.PrjnScale.Rel": "0.2",
+				}},
+		},
+		"InhibOff": &params.Sheet{
+			{Sel: "Layer", Desc: "layer defaults",
+				Params: params.Params{
+					"Layer.Acts.Gbar.L":    "0.2",
+					"Layer.Inhib.Layer.On": "false",
+				}},
+			{Sel: ".InhibPrjn", Desc: "weaker inhib",
+				Params: params.Params{
+					"Prjn.PrjnScale.Abs": "0.1",
+				}},
+		},
+	}},
+	"FullDecay": {Desc: "decay state completely for ndata testing", Sheets: params.Sheets{
+		"Network": &params.Sheet{
+			{Sel: "Layer", Desc: "layer defaults",
+				Params: params.Params{
+					"Layer.Acts.Decay.Act":   "1",
+					"Layer.Acts.Decay.Glong": "1",
+					"Layer.Acts.Decay.AHP":   "1",
+				}},
+		},
+	}},
+	"SubMean": {Desc: "submean on Prjn dwt", Sheets: params.Sheets{
+		"Network": &params.Sheet{
+			{Sel: "Prjn", Desc: "submean used in some models but not by default",
+				Params: params.Params{
+					"Prjn.Learn.Trace.SubMean": "1",
+				}},
+		},
+	}},
+}
+
+func newTestNet(ctx *Context, nData int) *Network {
+	var testNet Network
+	testNet.InitName(&testNet, "testNet")
+	testNet.SetRndSeed(42) // critical for ActAvg values
+	testNet.MaxData = uint32(nData)
+
+	inLay := testNet.AddLayer("Input", []int{4, 1}, InputLayer)
+	hidLay := testNet.AddLayer("Hidden", []int{4, 1}, SuperLayer)
+	outLay := testNet.AddLayer("Output", []int{4, 1}, TargetLayer)
+
+	_ = inLay
+	testNet.ConnectLayers(inLay, hidLay, prjn.NewOneToOne(), ForwardPrjn)
+	testNet.ConnectLayers(hidLay, outLay, prjn.NewOneToOne(), ForwardPrjn)
+	testNet.ConnectLayers(outLay, hidLay, prjn.NewOneToOne(), BackPrjn)
+
+	testNet.PVLV.SetNUSs(ctx, 4, 3)
+	testNet.PVLV.Defaults()
+
+	testNet.Build(ctx)
+	ctx.NetIdxs.NData = uint32(nData)
+	testNet.Defaults()
+	testNet.ApplyParams(ParamSets["Base"].Sheets["Network"], false) // false) // true) // no msg
+	testNet.InitWts(ctx)                                            // get GScale here
+	testNet.NewState(ctx)
+	return &testNet
+}
+
+// full connectivity
+func newTestNetFull(ctx *Context, nData int) *Network {
+	var testNet Network
+	testNet.InitName(&testNet, "testNet")
+	testNet.SetRndSeed(42) // critical for ActAvg values
+	testNet.MaxData = uint32(nData)
+
+	inLay := testNet.AddLayer("Input", []int{4, 1}, InputLayer)
+	hidLay := testNet.AddLayer("Hidden", []int{4, 1}, SuperLayer)
+	outLay := testNet.AddLayer("Output", []int{4, 1}, TargetLayer)
+
+	_ = inLay
+	full := prjn.NewFull()
+	testNet.ConnectLayers(inLay, hidLay, full, ForwardPrjn)
+	testNet.ConnectLayers(hidLay, outLay, full, ForwardPrjn)
+	testNet.ConnectLayers(outLay, hidLay, full, BackPrjn)
+
+	testNet.Build(ctx)
+	ctx.NetIdxs.NData = uint32(nData)
+	testNet.Defaults()
+	testNet.ApplyParams(ParamSets["Base"].Sheets["Network"], false) // false) // true) // no msg
+	testNet.InitWts(ctx)                                            // get GScale here
+	testNet.NewState(ctx)
+	return &testNet
+}
+
+// newTestNetLayers returns a new network with given number of data
+// parallel items, with layers and projections added by the config function,
+// that is then built, with Defaults, any params set by the params function
+// (can be nil), and InitWts, using the same random seed as newTestNet.
+func newTestNetLayers(t *testing.T, nData int, config, params func(net *Network)) (*Network, *Context) {
+	ctx := NewContext()
+	net := NewNetwork("testNet")
+	net.SetMaxData(ctx, nData)
+	net.SetRndSeed(42)
+	config(net)
+	assert.NoError(t, net.Build(ctx))
+	net.Defaults()
+	if params != nil {
+		params(net)
+	}
+	net.InitWts(ctx)
+	return net, ctx
+}
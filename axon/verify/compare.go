@@ -0,0 +1,243 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package verify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goki/mat32"
+)
+
+// Divergence records the location and values of a difference
+// between a reference and a comparison state.
+type Divergence struct {
+
+	// trial on which the difference occurred
+	Trial int `desc:"trial on which the difference occurred"`
+
+	// name of the layer or projection where the difference occurred (empty for Globals)
+	Unit string `desc:"name of the layer or projection where the difference occurred (empty for Globals)"`
+
+	// index of the neuron within the layer, or synapse within the projection
+	Idx int `desc:"index of the neuron within the layer, or synapse within the projection"`
+
+	// data index
+	Di int `desc:"data index"`
+
+	// reference value
+	Ref float32 `desc:"reference value"`
+
+	// comparison value
+	Val float32 `desc:"comparison value"`
+}
+
+func (dv *Divergence) String() string {
+	unit := dv.Unit
+	if unit == "" {
+		unit = "Global"
+	}
+	return fmt.Sprintf("Trial: %d  %s[%d]  Di: %d  Ref: %g  Val: %g", dv.Trial, unit, dv.Idx, dv.Di, dv.Ref, dv.Val)
+}
+
+// UnitDiff records the differences for one variable within one
+// layer or projection.
+type UnitDiff struct {
+
+	// name of the layer or projection (empty for Globals)
+	Unit string `desc:"name of the layer or projection (empty for Globals)"`
+
+	// total number of values that differ, across all trials
+	NDiffs int `desc:"total number of values that differ, across all trials"`
+
+	// maximum absolute difference
+	MaxDiff float32 `desc:"maximum absolute difference"`
+}
+
+func (ud *UnitDiff) String() string {
+	unit := ud.Unit
+	if unit == "" {
+		unit = "Global"
+	}
+	return fmt.Sprintf("%s  N: %d  Max: %g", unit, ud.NDiffs, ud.MaxDiff)
+}
+
+// VarDiff records the differences for one variable
+type VarDiff struct {
+
+	// kind of variable
+	Kind Kinds `desc:"kind of variable"`
+
+	// name of the variable
+	Name string `desc:"name of the variable"`
+
+	// total number of values that differ, across all trials
+	NDiffs int `desc:"total number of values that differ, across all trials"`
+
+	// maximum absolute difference
+	MaxDiff float32 `desc:"maximum absolute difference"`
+
+	// first divergence, in order of trial, data index, and value index
+	First Divergence `desc:"first divergence, in order of trial, data index, and value index"`
+
+	// differences for each layer or projection, in order found
+	Units []*UnitDiff `desc:"differences for each layer or projection, in order found"`
+}
+
+// UnitDiff returns the differences for given layer or projection, or nil if none
+func (vd *VarDiff) UnitDiff(unit string) *UnitDiff {
+	for _, ud := range vd.Units {
+		if ud.Unit == unit {
+			return ud
+		}
+	}
+	return nil
+}
+
+func (vd *VarDiff) String() string {
+	return fmt.Sprintf("%s:%s  N: %d  Max: %g  First: %s", vd.Kind, vd.Name, vd.NDiffs, vd.MaxDiff, vd.First.String())
+}
+
+// Tols are the tolerances for comparing variables: absolute
+// differences greater than the tolerance are counted as differences.
+type Tols struct {
+
+	// default tolerance for all variables -- 0 = exact
+	Tol float32 `desc:"default tolerance for all variables -- 0 = exact"`
+
+	// tolerances for specific variables, by Kind:Name Key (e.g., Neurons:Vm) or just Name (e.g., Vm), which override Tol
+	Vars map[string]float32 `desc:"tolerances for specific variables, by Kind:Name Key (e.g., Neurons:Vm) or just Name (e.g., Vm), which override Tol"`
+}
+
+// SetVarTol sets the tolerance for given variable Key (Kind:Name) or Name
+func (tl *Tols) SetVarTol(key string, tol float32) {
+	if tl.Vars == nil {
+		tl.Vars = make(map[string]float32)
+	}
+	tl.Vars[key] = tol
+}
+
+// VarTol returns the tolerance for given variable
+func (tl *Tols) VarTol(vr *Var) float32 {
+	if tol, ok := tl.Vars[vr.Key()]; ok {
+		return tol
+	}
+	if tol, ok := tl.Vars[vr.Name]; ok {
+		return tol
+	}
+	return tl.Tol
+}
+
+// Diffs accumulates the differences between a sequence of reference
+// and comparison states, for each variable.
+type Diffs struct {
+
+	// tolerances for comparing variables
+	Tols `view:"inline" desc:"tolerances for comparing variables"`
+
+	// differences for each variable that differs, in order found
+	Vars []*VarDiff `desc:"differences for each variable that differs, in order found"`
+
+	// map of Vars by Var Key
+	VarMap map[string]*VarDiff `view:"-" desc:"map of Vars by Var Key"`
+}
+
+// Equal returns true if there are no differences
+func (df *Diffs) Equal() bool {
+	return len(df.Vars) == 0
+}
+
+// First returns the first variable difference, by trial, in order of Kinds
+// for the same trial, or nil if none.
+func (df *Diffs) First() *VarDiff {
+	var first *VarDiff
+	for _, vd := range df.Vars {
+		if first == nil || vd.First.Trial < first.First.Trial || (vd.First.Trial == first.First.Trial && vd.Kind < first.Kind) {
+			first = vd
+		}
+	}
+	return first
+}
+
+// Compare compares given comparison state against reference state, for
+// given trial, accumulating any differences greater than the tolerance.
+// If the number of data-parallel items differs, each data index in the
+// comparison state is compared with data index di % ref.NData in the reference,
+// and the NeuronAvgs and Synapses variables, which integrate across data
+// items, are skipped.  Variables that are not present in both states are skipped.
+func (df *Diffs) Compare(trial int, ref, cmp *State) {
+	if df.VarMap == nil {
+		df.VarMap = make(map[string]*VarDiff)
+	}
+	for _, cv := range cmp.Vars {
+		if ref.NData != cmp.NData && !cv.Kind.IsData() {
+			continue
+		}
+		rv := ref.VarByKey(cv.Key())
+		if rv == nil || rv.N != cv.N {
+			continue
+		}
+		tol := df.VarTol(cv)
+		for di := 0; di < cv.NData; di++ {
+			rdi := di % rv.NData
+			for i := 0; i < cv.N; i++ {
+				r := rv.Val(i, rdi)
+				v := cv.Val(i, di)
+				if r == v || (mat32.IsNaN(r) && mat32.IsNaN(v)) {
+					continue
+				}
+				d := mat32.Abs(r - v)
+				if d <= tol { // false if only one is NaN
+					continue
+				}
+				df.add(trial, ref, cv, i, di, r, v, d)
+			}
+		}
+	}
+}
+
+// add adds a difference
+func (df *Diffs) add(trial int, st *State, vr *Var, idx, di int, r, v, d float32) {
+	unit, ui := st.Unit(vr, idx)
+	vd, ok := df.VarMap[vr.Key()]
+	if !ok {
+		vd = &VarDiff{Kind: vr.Kind, Name: vr.Name}
+		vd.First = Divergence{Trial: trial, Unit: unit, Idx: ui, Di: di, Ref: r, Val: v}
+		df.Vars = append(df.Vars, vd)
+		df.VarMap[vr.Key()] = vd
+	}
+	ud := vd.UnitDiff(unit)
+	if ud == nil {
+		ud = &UnitDiff{Unit: unit}
+		vd.Units = append(vd.Units, ud)
+	}
+	vd.NDiffs++
+	ud.NDiffs++
+	if d > vd.MaxDiff || mat32.IsNaN(d) {
+		vd.MaxDiff = d
+	}
+	if d > ud.MaxDiff || mat32.IsNaN(d) {
+		ud.MaxDiff = d
+	}
+}
+
+// String returns a report of the differences, one line per variable,
+// followed by one line for each layer or projection where it differs.
+func (df *Diffs) String() string {
+	if df.Equal() {
+		return "no differences\n"
+	}
+	var b strings.Builder
+	if fd := df.First(); fd != nil {
+		fmt.Fprintf(&b, "first divergence: %s\n", fd.String())
+	}
+	for _, vd := range df.Vars {
+		fmt.Fprintf(&b, "\t%s\n", vd.String())
+		for _, ud := range vd.Units {
+			fmt.Fprintf(&b, "\t\t%s\n", ud.String())
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package verify
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/emer/axon/axon"
+)
+
+// Snapshots is an ordered list of network States captured at named points
+// during processing (e.g., "0:MinusPhase", "0:DWt"), which can be saved to
+// a compact file and compared against stored golden Snapshots, to detect
+// changes in network dynamics.
+type Snapshots struct {
+
+	// names of the snapshots, in the order captured
+	Names []string `desc:"names of the snapshots, in the order captured"`
+
+	// the network states, one per name
+	States []*State `desc:"the network states, one per name"`
+}
+
+// Capture captures the current state of given network under given name,
+// replacing any existing snapshot with the same name.
+func (sn *Snapshots) Capture(name string, net *axon.Network) *State {
+	st := NewState(net)
+	for i, nm := range sn.Names {
+		if nm == name {
+			sn.States[i] = st
+			return st
+		}
+	}
+	sn.Names = append(sn.Names, name)
+	sn.States = append(sn.States, st)
+	return st
+}
+
+// State returns the snapshot state with given name, or nil if not found
+func (sn *Snapshots) State(name string) *State {
+	for i, nm := range sn.Names {
+		if nm == name {
+			return sn.States[i]
+		}
+	}
+	return nil
+}
+
+// Save saves the snapshots to given file, in gob format,
+// compressed with gzip if the file name ends in .gz
+func (sn *Snapshots) Save(fname string) error {
+	fp, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	var w io.Writer = fp
+	if strings.HasSuffix(fname, ".gz") {
+		gzw := gzip.NewWriter(fp)
+		defer gzw.Close()
+		w = gzw
+	}
+	return gob.NewEncoder(w).Encode(sn)
+}
+
+// Open opens snapshots from given file, as saved by Save
+func (sn *Snapshots) Open(fname string) error {
+	fp, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	var r io.Reader = fp
+	if strings.HasSuffix(fname, ".gz") {
+		gzr, err := gzip.NewReader(fp)
+		if err != nil {
+			return err
+		}
+		defer gzr.Close()
+		r = gzr
+	}
+	*sn = Snapshots{}
+	return gob.NewDecoder(r).Decode(sn)
+}
+
+// Report has the results of comparing Snapshots against reference Snapshots
+type Report struct {
+
+	// names of the snapshots compared
+	Names []string `desc:"names of the snapshots compared"`
+
+	// differences for each snapshot compared
+	Diffs []*Diffs `desc:"differences for each snapshot compared"`
+
+	// names of snapshots present in only one of the reference or comparison
+	Missing []string `desc:"names of snapshots present in only one of the reference or comparison"`
+}
+
+// Equal returns true if there are no differences or missing snapshots
+func (rp *Report) Equal() bool {
+	if len(rp.Missing) > 0 {
+		return false
+	}
+	for _, df := range rp.Diffs {
+		if !df.Equal() {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns the differences for snapshot of given name, or nil if not compared
+func (rp *Report) Diff(name string) *Diffs {
+	for i, nm := range rp.Names {
+		if nm == name {
+			return rp.Diffs[i]
+		}
+	}
+	return nil
+}
+
+// String returns a report of the snapshots that differ, listing
+// the variables that differ and the layers or projections where they differ.
+func (rp *Report) String() string {
+	if rp.Equal() {
+		return "no differences\n"
+	}
+	var b strings.Builder
+	for i, nm := range rp.Names {
+		df := rp.Diffs[i]
+		if df.Equal() {
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s", nm, df.String())
+	}
+	if len(rp.Missing) > 0 {
+		fmt.Fprintf(&b, "missing: %s\n", strings.Join(rp.Missing, ", "))
+	}
+	return b.String()
+}
+
+// Compare compares these snapshots against given reference snapshots
+// (e.g., stored goldens), using given tolerances, matching by name.
+func (sn *Snapshots) Compare(ref *Snapshots, tols Tols) *Report {
+	rp := &Report{}
+	for i, nm := range sn.Names {
+		rst := ref.State(nm)
+		if rst == nil {
+			rp.Missing = append(rp.Missing, nm)
+			continue
+		}
+		df := &Diffs{Tols: tols}
+		df.Compare(0, rst, sn.States[i])
+		rp.Names = append(rp.Names, nm)
+		rp.Diffs = append(rp.Diffs, df)
+	}
+	for _, nm := range ref.Names {
+		if sn.State(nm) == nil {
+			rp.Missing = append(rp.Missing, nm)
+		}
+	}
+	return rp
+}
+
+// CompareGolden compares these snapshots against the golden snapshots
+// stored in given file, using given tolerances. If update is true, these
+// snapshots are saved to the file instead (creating the directory if
+// needed), and a nil Report is returned.  If the file does not exist and
+// update is false, an error wrapping fs.ErrNotExist is returned, so that
+// a missing golden file fails the test instead of silently passing.
+// Typical usage in a test, with an -update flag to regenerate goldens:
+//
+//	rp, err := sn.CompareGolden("testdata/mynet.snap.gz", *update, tols)
+//	if rp != nil && !rp.Equal() {
+//		t.Error(rp.String())
+//	}
+func (sn *Snapshots) CompareGolden(fname string, update bool, tols Tols) (*Report, error) {
+	if update {
+		if err := os.MkdirAll(filepath.Dir(fname), 0755); err != nil {
+			return nil, err
+		}
+		return nil, sn.Save(fname)
+	}
+	if _, err := os.Stat(fname); errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("verify.CompareGolden: golden file %s does not exist -- run with update to create it: %w", fname, err)
+	}
+	ref := &Snapshots{}
+	if err := ref.Open(fname); err != nil {
+		return nil, err
+	}
+	return sn.Compare(ref, tols), nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package verify
+
+import (
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/emer/axon/axon"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden snapshot files in testdata")
+
+func TestSnapshotsSaveOpen(t *testing.T) {
+	vf := newTestVerify(t)
+	vf.NTrials = 1
+	sn, err := vf.RunSnapshots(Layout{NData: 2, GPUOrder: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0:MinusPhase", "0:PlusPhase", "0:DWt"}, sn.Names)
+	assert.NotNil(t, sn.State("0:DWt").VarByKey("Pools:AvgMax.CaSpkP.Plus.Max"))
+
+	fn := filepath.Join(t.TempDir(), "snap.gz")
+	assert.NoError(t, sn.Save(fn))
+	osn := &Snapshots{}
+	assert.NoError(t, osn.Open(fn))
+	assert.Equal(t, sn.Names, osn.Names)
+	rp := osn.Compare(sn, Tols{})
+	assert.True(t, rp.Equal(), rp.String())
+
+	// drift in hidden layer dynamics is reported per variable and layer
+	vf.ApplyParams = func(net *axon.Network) {
+		net.AxonLayerByName("Hidden").Params.Acts.Dt.VmTau = 3
+	}
+	dsn, err := vf.RunSnapshots(Layout{NData: 2, GPUOrder: true})
+	assert.NoError(t, err)
+	tols := Tols{Tol: 1e-6}
+	tols.SetVarTol("Neurons:Spike", 1)
+	rp = dsn.Compare(sn, tols)
+	assert.False(t, rp.Equal())
+	df := rp.Diff("0:MinusPhase")
+	vm := df.VarMap["Neurons:Vm"]
+	assert.NotNil(t, vm)
+	assert.Equal(t, "Hidden", vm.First.Unit)
+	assert.NotNil(t, vm.UnitDiff("Hidden"))
+	assert.Nil(t, vm.UnitDiff("Input"))
+	assert.Nil(t, df.VarMap["Neurons:Spike"])
+	assert.Contains(t, rp.String(), "0:MinusPhase: first divergence")
+
+	dsn.Names = dsn.Names[:2]
+	dsn.States = dsn.States[:2]
+	rp = dsn.Compare(sn, tols)
+	assert.Equal(t, []string{"0:DWt"}, rp.Missing)
+}
+
+// TestGolden checks the network dynamics against stored golden snapshots.
+// Run with -update to regenerate after intentional changes.
+func TestGolden(t *testing.T) {
+	vf := newTestVerify(t)
+	vf.NTrials = 2
+	sn, err := vf.RunSnapshots(Layout{NData: 1, GPUOrder: true})
+	assert.NoError(t, err)
+	rp, err := sn.CompareGolden("testdata/golden.snap.gz", *update, Tols{Tol: 1e-4})
+	assert.NoError(t, err)
+	if rp != nil && !rp.Equal() {
+		t.Error(rp.String())
+	}
+
+	// a missing golden file is an error unless updating
+	fnm := filepath.Join(t.TempDir(), "missing.snap.gz")
+	_, err = sn.CompareGolden(fnm, false, Tols{})
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = os.Stat(fnm)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -0,0 +1,340 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package verify
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/emer/axon/axon"
+	"github.com/goki/ki/kit"
+)
+
+//go:generate stringer -type=Kinds
+
+var KiT_Kinds = kit.Enums.AddEnum(KindsN, kit.NotBitFlag, nil)
+
+// Kinds are the different kinds of network state variables
+type Kinds int32
+
+const (
+	// Neurons are the data-parallel neuron variables (axon.NeuronVars)
+	Neurons Kinds = iota
+
+	// NeuronAvgs are the neuron variables shared across data (axon.NeuronAvgVars)
+	NeuronAvgs
+
+	// Synapses are the synapse variables shared across data (axon.SynapseVars)
+	Synapses
+
+	// SynapseCas are the data-parallel synapse variables (axon.SynapseCaVars)
+	SynapseCas
+
+	// LayVals are the float32 fields of the per-layer, per-data axon.LayerVals
+	LayVals
+
+	// Globals are the per-data global variables (axon.GlobalVars)
+	Globals
+
+	// Pools are the float32 and int32 fields of the per-pool, per-data axon.Pool
+	Pools
+
+	KindsN
+)
+
+// IsData returns true if this kind of variable has separate values
+// for each data-parallel item.
+func (kd Kinds) IsData() bool {
+	return kd != NeuronAvgs && kd != Synapses
+}
+
+// Var has the values of one network state variable, in a canonical order
+// that does not depend on the memory layout of the network:
+// Vals[di * N + i] where i is the network-wide neuron or synapse index,
+// the layer index for LayVals, the network-wide pool index for Pools
+// (in layer order, excluding layers that are off), and 0 for Globals (N = 1).
+// For variables that are not data-parallel, NData is 1.
+type Var struct {
+
+	// kind of variable
+	Kind Kinds `desc:"kind of variable"`
+
+	// name of the variable
+	Name string `desc:"name of the variable"`
+
+	// number of data-parallel items
+	NData int `desc:"number of data-parallel items"`
+
+	// number of values per data item
+	N int `desc:"number of values per data item"`
+
+	// the values, in [NData][N] order
+	Vals []float32 `desc:"the values, in [NData][N] order"`
+}
+
+// Key returns the unique key for this variable: Kind:Name
+func (vr *Var) Key() string {
+	return vr.Kind.String() + ":" + vr.Name
+}
+
+// Val returns the value for given index and data index
+func (vr *Var) Val(i, di int) float32 {
+	return vr.Vals[di*vr.N+i]
+}
+
+// State is a copy of the full state of a network, organized by variable
+// in a canonical order that is independent of the memory layout
+// (UseGPUOrder), number of threads, and MaxData, so that states from
+// networks with different layouts can be compared directly.
+type State struct {
+
+	// number of data-parallel items
+	NData int `desc:"number of data-parallel items"`
+
+	// names of the layers, in network order, excluding layers that are off
+	Layers []string `desc:"names of the layers, in network order, excluding layers that are off"`
+
+	// starting network-wide neuron index for each layer
+	LayNeurSt []int `desc:"starting network-wide neuron index for each layer"`
+
+	// names of the projections (sending layer:receiving layer), in synapse memory order
+	Prjns []string `desc:"names of the projections (sending layer:receiving layer), in synapse memory order"`
+
+	// starting network-wide synapse index for each projection
+	PrjnSynSt []int `desc:"starting network-wide synapse index for each projection"`
+
+	// starting pool index for each layer, in the Pools variables
+	LayPoolSt []int `desc:"starting pool index for each layer, in the Pools variables"`
+
+	// all the variables, in Kinds order
+	Vars []*Var `desc:"all the variables, in Kinds order"`
+
+	// map of variables by Key -- not saved
+	varMap map[string]*Var
+}
+
+// NewState returns a new State copied from the current state of given network.
+func NewState(net *axon.Network) *State {
+	st := &State{}
+	st.Capture(net)
+	return st
+}
+
+// Capture copies the current state of given network into this State.
+func (st *State) Capture(net *axon.Network) {
+	ctx := &net.Ctx
+	nd := int(net.MaxData)
+	st.NData = nd
+	st.Layers = nil
+	st.LayNeurSt = nil
+	st.Prjns = nil
+	st.PrjnSynSt = nil
+	st.LayPoolSt = nil
+	st.Vars = nil
+	st.varMap = make(map[string]*Var)
+	np := 0
+	for _, ly := range net.Layers {
+		if ly.IsOff() {
+			continue
+		}
+		st.Layers = append(st.Layers, ly.Nm)
+		st.LayNeurSt = append(st.LayNeurSt, int(ly.NeurStIdx))
+		st.LayPoolSt = append(st.LayPoolSt, np)
+		np += int(ly.NPools)
+		for _, pj := range ly.SndPrjns {
+			st.Prjns = append(st.Prjns, pj.Name())
+			st.PrjnSynSt = append(st.PrjnSynSt, int(pj.SynStIdx))
+		}
+	}
+	nn := int(net.NNeurons)
+	ns := int(net.NSyns)
+	for vi := axon.NeuronVars(0); vi < axon.NeuronVarsN; vi++ {
+		vr := st.AddVar(Neurons, vi.String(), nd, nn)
+		for di := 0; di < nd; di++ {
+			for ni := 0; ni < nn; ni++ {
+				vr.Vals[di*nn+ni] = axon.NrnV(ctx, uint32(ni), uint32(di), vi)
+			}
+		}
+	}
+	for vi := axon.NeuronAvgVars(0); vi < axon.NeuronAvgVarsN; vi++ {
+		vr := st.AddVar(NeuronAvgs, vi.String(), 1, nn)
+		for ni := 0; ni < nn; ni++ {
+			vr.Vals[ni] = axon.NrnAvgV(ctx, uint32(ni), vi)
+		}
+	}
+	for vi := axon.SynapseVars(0); vi < axon.SynapseVarsN; vi++ {
+		vr := st.AddVar(Synapses, vi.String(), 1, ns)
+		for si := 0; si < ns; si++ {
+			vr.Vals[si] = axon.SynV(ctx, uint32(si), vi)
+		}
+	}
+	for vi := axon.SynapseCaVars(0); vi < axon.SynapseCaVarsN; vi++ {
+		vr := st.AddVar(SynapseCas, vi.String(), nd, ns)
+		for di := 0; di < nd; di++ {
+			for si := 0; si < ns; si++ {
+				vr.Vals[di*ns+si] = axon.SynCaV(ctx, uint32(si), uint32(di), vi)
+			}
+		}
+	}
+	st.captureLayVals(net)
+	st.captureGlobals(net)
+	st.capturePools(net, np)
+}
+
+// captureGlobals captures all the global variables, with separate variables
+// for each US index for the USneg and USpos (Drives etc) variables.
+func (st *State) captureGlobals(net *axon.Network) {
+	ctx := &net.Ctx
+	nd := int(net.MaxData)
+	for vv := axon.GvRew; vv < axon.GvUSneg; vv++ {
+		vr := st.AddVar(Globals, vv.String(), nd, 1)
+		for di := 0; di < nd; di++ {
+			vr.Vals[di] = axon.GlbV(ctx, uint32(di), vv)
+		}
+	}
+	for vv := axon.GvUSneg; vv <= axon.GvUSnegRaw; vv++ {
+		for ui := uint32(0); ui < ctx.NetIdxs.PVLVNNegUSs; ui++ {
+			vr := st.AddVar(Globals, fmt.Sprintf("%s[%d]", vv.String(), ui), nd, 1)
+			for di := 0; di < nd; di++ {
+				vr.Vals[di] = axon.GlbUSneg(ctx, uint32(di), vv, ui)
+			}
+		}
+	}
+	for vv := axon.GvDrives; vv < axon.GlobalVarsN; vv++ {
+		for ui := uint32(0); ui < ctx.NetIdxs.PVLVNPosUSs; ui++ {
+			vr := st.AddVar(Globals, fmt.Sprintf("%s[%d]", vv.String(), ui), nd, 1)
+			for di := 0; di < nd; di++ {
+				vr.Vals[di] = axon.GlbUSposV(ctx, uint32(di), vv, ui)
+			}
+		}
+	}
+}
+
+// captureLayVals captures all the float32 fields of LayerVals
+func (st *State) captureLayVals(net *axon.Network) {
+	nd := int(net.MaxData)
+	nl := len(st.Layers)
+	li := 0
+	for _, ly := range net.Layers {
+		if ly.IsOff() {
+			continue
+		}
+		for di := 0; di < nd; di++ {
+			lv := ly.LayerVals(uint32(di))
+			structFields(reflect.ValueOf(lv).Elem(), "", func(nm string, val float32) {
+				vr, ok := st.varMap[LayVals.String()+":"+nm]
+				if !ok {
+					vr = st.AddVar(LayVals, nm, nd, nl)
+				}
+				vr.Vals[di*nl+li] = val
+			})
+		}
+		li++
+	}
+}
+
+// capturePools captures all the float32 and int32 fields of the Pools,
+// for given total number of pools
+func (st *State) capturePools(net *axon.Network, np int) {
+	nd := int(net.MaxData)
+	li := 0
+	for _, ly := range net.Layers {
+		if ly.IsOff() {
+			continue
+		}
+		for pi := 0; pi < int(ly.NPools); pi++ {
+			idx := st.LayPoolSt[li] + pi
+			for di := 0; di < nd; di++ {
+				pl := ly.Pool(uint32(pi), uint32(di))
+				structFields(reflect.ValueOf(pl).Elem(), "", func(nm string, val float32) {
+					vr, ok := st.varMap[Pools.String()+":"+nm]
+					if !ok {
+						vr = st.AddVar(Pools, nm, nd, np)
+					}
+					vr.Vals[di*np+idx] = val
+				})
+			}
+		}
+		li++
+	}
+}
+
+// structFields calls fun for each exported float32 and int32 field in given
+// struct value, recursively, with the path name of the field.
+// uint32 fields, which are used for indexes, are skipped.
+func structFields(sv reflect.Value, path string, fun func(nm string, val float32)) {
+	typ := sv.Type()
+	for fi := 0; fi < typ.NumField(); fi++ {
+		ft := typ.Field(fi)
+		if !ft.IsExported() {
+			continue
+		}
+		nm := ft.Name
+		if path != "" {
+			nm = path + "." + nm
+		}
+		fv := sv.Field(fi)
+		switch fv.Kind() {
+		case reflect.Float32:
+			fun(nm, float32(fv.Float()))
+		case reflect.Int32:
+			fun(nm, float32(fv.Int()))
+		case reflect.Struct:
+			structFields(fv, nm, fun)
+		}
+	}
+}
+
+// AddVar adds a new variable with given kind, name and size
+func (st *State) AddVar(kind Kinds, name string, ndata, n int) *Var {
+	vr := &Var{Kind: kind, Name: name, NData: ndata, N: n, Vals: make([]float32, ndata*n)}
+	st.Vars = append(st.Vars, vr)
+	if st.varMap == nil {
+		st.varMap = make(map[string]*Var)
+	}
+	st.varMap[vr.Key()] = vr
+	return vr
+}
+
+// VarByKey returns the variable with given Key (Kind:Name), or nil if not found
+func (st *State) VarByKey(key string) *Var {
+	if len(st.varMap) != len(st.Vars) {
+		st.varMap = make(map[string]*Var, len(st.Vars))
+		for _, vr := range st.Vars {
+			st.varMap[vr.Key()] = vr
+		}
+	}
+	return st.varMap[key]
+}
+
+// Unit returns the name of the layer or projection that holds the given
+// value index for given variable, and the index of the value within it
+// (the layer-relative neuron index, projection-relative synapse index,
+// or layer-relative pool index).
+// Returns an empty name for Globals.
+func (st *State) Unit(vr *Var, idx int) (string, int) {
+	switch vr.Kind {
+	case Neurons, NeuronAvgs:
+		return findStart(st.Layers, st.LayNeurSt, idx)
+	case Synapses, SynapseCas:
+		return findStart(st.Prjns, st.PrjnSynSt, idx)
+	case LayVals:
+		return st.Layers[idx], 0
+	case Pools:
+		return findStart(st.Layers, st.LayPoolSt, idx)
+	}
+	return "", idx
+}
+
+// findStart returns the name and relative index of the last item
+// with a starting index <= idx
+func findStart(names []string, starts []int, idx int) (string, int) {
+	for i := len(starts) - 1; i >= 0; i-- {
+		if starts[i] <= idx {
+			return names[i], idx - starts[i]
+		}
+	}
+	return "", idx
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package verify provides tools for verifying that a network computes the
+same results regardless of the parallel layout used to run it: the number
+of CPU threads (NThreads), the number of data-parallel items (NData),
+and the memory order of the neuron and synapse variables (UseGPUOrder).
+
+A Verify runs a user-supplied network configuration for NTrials trials
+under each of a list of Layouts, capturing the full network State
+(neurons, synapses, LayerVals, and Globals) after every trial, in a
+canonical order that does not depend on the layout. Each layout is
+compared against the first (reference) layout, and the result reports
+the number of differences and maximum difference for each variable,
+along with the location of the first divergence.
+
+	vf := &verify.Verify{}
+	vf.Defaults()
+	vf.ConfigNet = func(net *axon.Network) {
+		inp := net.AddLayer2D("Input", 4, 4, axon.InputLayer)
+		hid := net.AddLayer2D("Hidden", 4, 4, MyLayer)
+		net.ConnectLayers(inp, hid, prjn.NewFull(), axon.ForwardPrjn)
+	}
+	vf.ApplyInputs = func(ctx *axon.Context, net *axon.Network, trial, di int) {
+		net.AxonLayerByName("Input").ApplyExt(ctx, uint32(di), pats.SubSpace([]int{trial % npats}))
+	}
+	vf.Layouts = verify.ThreadLayouts(4)
+	rss, err := vf.Run()
+	for _, rs := range rss {
+		fmt.Println(rs.String())
+	}
+
+In comparing layouts with different NData, each data index is compared
+with data index di % NData of the reference, so ApplyInputs should present
+the same input to corresponding data items.  The NeuronAvgs and Synapses
+variables, which integrate across data items, are not compared.
+Because weight changes are summed across data-parallel items, such
+comparisons are only expected to match when Learn is false.
+
+Snapshots capture the network State at named points, e.g., after the
+MinusPhase and DWt steps of each trial (see RunSnapshots), and can be saved
+to a compact gzipped file and compared against stored golden Snapshots,
+with per-variable tolerances (Tols), reporting which variables drifted in
+which layers.  This can be used in any model's tests to detect changes in
+dynamics:
+
+	sn, err := vf.RunSnapshots(verify.Layout{NData: 1, GPUOrder: true})
+	rp, err := sn.CompareGolden("testdata/mynet.snap.gz", *update, verify.Tols{Tol: 1e-4})
+	if rp != nil && !rp.Equal() {
+		t.Error(rp.String())
+	}
+*/
+package verify
+
+import (
+	"fmt"
+
+	"github.com/emer/axon/axon"
+	"github.com/emer/emergent/etime"
+	"github.com/goki/ki/ints"
+)
+
+// Layout specifies a parallel layout for running a network
+type Layout struct {
+
+	// number of CPU threads to use -- 0 = default based on network size
+	NThreads int `desc:"number of CPU threads to use -- 0 = default based on network size"`
+
+	// [min: 1] number of data-parallel items
+	NData int `min:"1" desc:"number of data-parallel items"`
+
+	// use the GPU-optimized memory order for neuron and synapse variables (UseGPUOrder), otherwise CPU-optimized
+	GPUOrder bool `desc:"use the GPU-optimized memory order for neuron and synapse variables (UseGPUOrder), otherwise CPU-optimized"`
+}
+
+func (lo *Layout) String() string {
+	return fmt.Sprintf("NThreads: %d  NData: %d  GPUOrder: %v", lo.NThreads, lo.NData, lo.GPUOrder)
+}
+
+// ThreadLayouts returns a standard set of layouts for comparing
+// different numbers of threads, all with NData = 1:
+// a single-threaded reference, followed by 2 and maxThreads threads,
+// all in the GPU memory order.
+func ThreadLayouts(maxThreads int) []Layout {
+	return []Layout{
+		{NThreads: 1, NData: 1, GPUOrder: true},
+		{NThreads: 2, NData: 1, GPUOrder: true},
+		{NThreads: maxThreads, NData: 1, GPUOrder: true},
+	}
+}
+
+// OrderLayouts returns a standard set of layouts for comparing
+// the GPU and CPU memory orders, single-threaded with given NData:
+// a GPU order reference, followed by the CPU order.
+func OrderLayouts(nData int) []Layout {
+	return []Layout{
+		{NThreads: 1, NData: nData, GPUOrder: true},
+		{NThreads: 1, NData: nData, GPUOrder: false},
+	}
+}
+
+// Result has the results of comparing one layout against the reference
+type Result struct {
+
+	// layout that was compared
+	Layout Layout `desc:"layout that was compared"`
+
+	// differences from the reference layout
+	Diffs Diffs `desc:"differences from the reference layout"`
+}
+
+// Equal returns true if there are no differences from the reference
+func (rs *Result) Equal() bool {
+	return rs.Diffs.Equal()
+}
+
+func (rs *Result) String() string {
+	return rs.Layout.String() + ": " + rs.Diffs.String()
+}
+
+// Verify runs a network under different parallel Layouts and
+// compares the resulting network states against the first layout.
+type Verify struct {
+
+	// function that configures the network layers and projections, prior to Build -- must be set
+	ConfigNet func(net *axon.Network) `view:"-" desc:"function that configures the network layers and projections, prior to Build -- must be set"`
+
+	// optional function that applies parameters to the network, after Defaults and prior to InitWts
+	ApplyParams func(net *axon.Network) `view:"-" desc:"optional function that applies parameters to the network, after Defaults and prior to InitWts"`
+
+	// function that applies the external inputs for given trial and data index -- InitExt and ApplyExts are called automatically -- must be set
+	ApplyInputs func(ctx *axon.Context, net *axon.Network, trial, di int) `view:"-" desc:"function that applies the external inputs for given trial and data index -- InitExt and ApplyExts are called automatically -- must be set"`
+
+	// layouts to run -- the first is the reference that the others are compared against
+	Layouts []Layout `desc:"layouts to run -- the first is the reference that the others are compared against"`
+
+	// [def: 10] number of trials to run
+	NTrials int `def:"10" desc:"number of trials to run"`
+
+	// [def: 200] total number of cycles per trial
+	NCycles int `def:"200" desc:"total number of cycles per trial"`
+
+	// [def: 50] number of plus phase cycles per trial
+	PlusCycles int `def:"50" desc:"number of plus phase cycles per trial"`
+
+	// [def: true] run in Train mode, with DWt and WtFmDWt after each trial -- otherwise run in Test mode
+	Learn bool `def:"true" desc:"run in Train mode, with DWt and WtFmDWt after each trial -- otherwise run in Test mode"`
+
+	// [def: 1] random seed for the network Rand, set prior to building the network, and before running trials
+	Seed int64 `def:"1" desc:"random seed for the network Rand, set prior to building the network, and before running trials"`
+
+	// [def: 0] tolerance: absolute differences greater than this are reported -- 0 = exact
+	Tol float32 `def:"0" desc:"tolerance: absolute differences greater than this are reported -- 0 = exact"`
+}
+
+func (vf *Verify) Defaults() {
+	vf.NTrials = 10
+	vf.NCycles = 200
+	vf.PlusCycles = 50
+	vf.Learn = true
+	vf.Seed = 1
+	vf.Tol = 0
+}
+
+// Run runs all the Layouts and returns the results of comparing each
+// layout after the first against the first (reference) layout.
+func (vf *Verify) Run() ([]*Result, error) {
+	if len(vf.Layouts) < 2 {
+		return nil, fmt.Errorf("verify.Run: at least 2 Layouts are required, have: %d", len(vf.Layouts))
+	}
+	ref, err := vf.RunLayout(vf.Layouts[0])
+	if err != nil {
+		return nil, err
+	}
+	var rss []*Result
+	for _, lo := range vf.Layouts[1:] {
+		sts, err := vf.RunLayout(lo)
+		if err != nil {
+			return rss, err
+		}
+		rs := &Result{Layout: lo}
+		rs.Diffs.Tol = vf.Tol
+		for ti := range sts {
+			rs.Diffs.Compare(ti, ref[ti], sts[ti])
+		}
+		rss = append(rss, rs)
+	}
+	return rss, nil
+}
+
+// NewNetwork configures and builds a new network for given layout,
+// returning the network and its context.
+func (vf *Verify) NewNetwork(lo Layout) (*axon.Network, *axon.Context, error) {
+	ctx := axon.NewContext()
+	net := axon.NewNetwork("Verify")
+	net.UseGPUOrder = lo.GPUOrder
+	net.SetMaxData(ctx, ints.MaxInt(lo.NData, 1))
+	net.SetRndSeed(vf.Seed)
+	vf.ConfigNet(net)
+	if err := net.Build(ctx); err != nil {
+		return nil, nil, err
+	}
+	net.Defaults()
+	if vf.ApplyParams != nil {
+		vf.ApplyParams(net)
+	}
+	if lo.NThreads > 0 {
+		net.SetNThreads(lo.NThreads)
+	}
+	net.InitWts(ctx)
+	return net, ctx, nil
+}
+
+// RunLayout runs the network under given layout for NTrials,
+// returning the network State after each trial.
+func (vf *Verify) RunLayout(lo Layout) ([]*State, error) {
+	net, ctx, err := vf.NewNetwork(lo)
+	if err != nil {
+		return nil, err
+	}
+	net.ResetRndSeed()
+	sts := make([]*State, vf.NTrials)
+	for ti := 0; ti < vf.NTrials; ti++ {
+		vf.RunTrial(net, ctx, ti, nil)
+		sts[ti] = NewState(net)
+	}
+	return sts, nil
+}
+
+// RunSnapshots runs the network under given layout for NTrials,
+// returning Snapshots captured within each trial, as in RunTrial.
+func (vf *Verify) RunSnapshots(lo Layout) (*Snapshots, error) {
+	net, ctx, err := vf.NewNetwork(lo)
+	if err != nil {
+		return nil, err
+	}
+	net.ResetRndSeed()
+	sn := &Snapshots{}
+	for ti := 0; ti < vf.NTrials; ti++ {
+		vf.RunTrial(net, ctx, ti, sn)
+	}
+	return sn, nil
+}
+
+// RunTrial runs one trial of processing for given trial number.
+// If sn is non-nil, snapshots are captured after the MinusPhase,
+// PlusPhase, and (if Learn) WtFmDWt steps, named trial:step,
+// e.g., 0:MinusPhase, 0:PlusPhase, 0:DWt.
+func (vf *Verify) RunTrial(net *axon.Network, ctx *axon.Context, trial int, sn *Snapshots) {
+	mode := etime.Train
+	if !vf.Learn {
+		mode = etime.Test
+	}
+	capture := func(step string) {
+		if sn != nil {
+			sn.Capture(fmt.Sprintf("%d:%s", trial, step), net)
+		}
+	}
+	minusCycles := vf.NCycles - vf.PlusCycles
+	net.NewState(ctx)
+	ctx.NewState(mode)
+	net.InitExt(ctx)
+	for di := 0; di < int(ctx.NetIdxs.NData); di++ {
+		vf.ApplyInputs(ctx, net, trial, di)
+	}
+	net.ApplyExts(ctx)
+	for cyc := 0; cyc < vf.NCycles; cyc++ {
+		if cyc == minusCycles {
+			net.MinusPhase(ctx)
+			capture("MinusPhase")
+			ctx.NewPhase(true)
+			net.PlusPhaseStart(ctx)
+		}
+		net.Cycle(ctx)
+		ctx.CycleInc()
+	}
+	net.PlusPhase(ctx)
+	capture("PlusPhase")
+	if vf.Learn {
+		net.DWt(ctx)
+		net.WtFmDWt(ctx)
+		capture("DWt")
+	}
+}
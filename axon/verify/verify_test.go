@@ -0,0 +1,120 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package verify
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/emer/axon/axon"
+	"github.com/emer/emergent/patgen"
+	"github.com/emer/emergent/prjn"
+	"github.com/emer/etable/etensor"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestVerify(t *testing.T) *Verify {
+	rand.Seed(1)
+	pats := etensor.NewFloat32([]int{4, 4, 4}, nil, nil)
+	patgen.PermutedBinaryRows(pats, 4, 1, 0)
+	vf := &Verify{}
+	vf.Defaults()
+	vf.NTrials = 3
+	vf.ConfigNet = func(net *axon.Network) {
+		inp := net.AddLayer2D("Input", 4, 4, axon.InputLayer)
+		hid := net.AddLayer2D("Hidden", 4, 4, axon.SuperLayer)
+		out := net.AddLayer2D("Output", 4, 4, axon.TargetLayer)
+		net.ConnectLayers(inp, hid, prjn.NewFull(), axon.ForwardPrjn)
+		net.BidirConnectLayers(hid, out, prjn.NewFull())
+	}
+	vf.ApplyInputs = func(ctx *axon.Context, net *axon.Network, trial, di int) {
+		pat := pats.SubSpace([]int{trial % 4})
+		net.AxonLayerByName("Input").ApplyExt(ctx, uint32(di), pat)
+		net.AxonLayerByName("Output").ApplyExt(ctx, uint32(di), pat)
+	}
+	return vf
+}
+
+func TestVerifyThreads(t *testing.T) {
+	vf := newTestVerify(t)
+	vf.Layouts = ThreadLayouts(4)
+	rss, err := vf.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rss))
+	for _, rs := range rss {
+		assert.True(t, rs.Equal(), rs.String())
+	}
+}
+
+func TestVerifyOrder(t *testing.T) {
+	vf := newTestVerify(t)
+	vf.Layouts = OrderLayouts(2)
+	rss, err := vf.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rss))
+	for _, rs := range rss {
+		assert.True(t, rs.Equal(), rs.String())
+	}
+}
+
+func TestVerifyNData(t *testing.T) {
+	vf := newTestVerify(t)
+	vf.Learn = false
+	vf.Layouts = []Layout{{NThreads: 1, NData: 1, GPUOrder: true}, {NThreads: 2, NData: 3, GPUOrder: true}, {NThreads: 1, NData: 2, GPUOrder: true}}
+	rss, err := vf.Run()
+	assert.NoError(t, err)
+	for _, rs := range rss {
+		assert.True(t, rs.Equal(), rs.String())
+	}
+
+	// different input on data index 1 is detected on the first trial, in Input
+	apply := vf.ApplyInputs
+	vf.ApplyInputs = func(ctx *axon.Context, net *axon.Network, trial, di int) {
+		apply(ctx, net, trial+di, di)
+	}
+	rss, err = vf.Run()
+	assert.NoError(t, err)
+	rs := rss[0]
+	assert.False(t, rs.Equal())
+	ext := rs.Diffs.VarMap["Neurons:Ext"]
+	assert.NotNil(t, ext)
+	assert.Equal(t, 0, ext.First.Trial)
+	assert.Equal(t, "Input", ext.First.Unit)
+	assert.Equal(t, 1, ext.First.Di)
+	assert.Equal(t, 0, rs.Diffs.First().First.Trial)
+	assert.True(t, strings.Contains(rs.String(), "first divergence"))
+	_, ok := rs.Diffs.VarMap["Synapses:Wt"]
+	assert.False(t, ok) // no learning
+
+	vf.Layouts = vf.Layouts[:1]
+	_, err = vf.Run()
+	assert.Error(t, err)
+}
+
+func TestStateUnit(t *testing.T) {
+	vf := newTestVerify(t)
+	cnet, _, err := vf.NewNetwork(Layout{NData: 2, GPUOrder: false})
+	assert.NoError(t, err)
+	assert.False(t, cnet.UseGPUOrder)
+	net, _, err := vf.NewNetwork(Layout{NData: 2, GPUOrder: true})
+	assert.NoError(t, err)
+	assert.True(t, net.UseGPUOrder)
+	st := NewState(net)
+	assert.Equal(t, 2, st.NData)
+	assert.Equal(t, []string{"Input", "Hidden", "Output"}, st.Layers)
+	vr := st.VarByKey("Neurons:Act")
+	assert.Equal(t, 48, vr.N)
+	nm, idx := st.Unit(vr, 20)
+	assert.Equal(t, "Hidden", nm)
+	assert.Equal(t, 4, idx)
+	vr = st.VarByKey("Synapses:Wt")
+	nm, idx = st.Unit(vr, 256+3)
+	assert.Equal(t, st.Prjns[1], nm)
+	assert.Equal(t, 3, idx)
+	assert.NotNil(t, st.VarByKey("LayVals:ActAvg.ActMAvg"))
+	assert.NotNil(t, st.VarByKey("Globals:GvRew"))
+	assert.NotNil(t, st.VarByKey("Globals:GvDrives[0]"))
+}
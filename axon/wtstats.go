@@ -0,0 +1,324 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/emer/emergent/elog"
+	"github.com/emer/emergent/etime"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/etable/minmax"
+)
+
+// WtStatsParams are parameters for the WtStats monitor
+type WtStatsParams struct {
+
+	// [def: 1] [min: 1] compute the stats every Interval calls to Step (e.g., every Interval trials or epochs, depending on where Step is called)
+	Interval int `def:"1" min:"1" desc:"compute the stats every Interval calls to Step (e.g., every Interval trials or epochs, depending on where Step is called)"`
+
+	// [def: 0.01] tolerance for counting an LWt value as being at its 0 or 1 bound
+	BoundTol float32 `def:"0.01" desc:"tolerance for counting an LWt value as being at its 0 or 1 bound"`
+
+	// [def: 0.5] warn that a projection is saturated when the fraction of LWt values at their bounds exceeds this value
+	SatThr float32 `def:"0.5" desc:"warn that a projection is saturated when the fraction of LWt values at their bounds exceeds this value"`
+
+	// [def: 1e-6] warn that a projection is silent when WtChangeRMS, the RMS change in Wt since the previous computation, is below this value
+	SilentThr float32 `def:"1e-6" desc:"warn that a projection is silent when WtChangeRMS, the RMS change in Wt since the previous computation, is below this value"`
+
+	// [def: true] print warnings to the console -- they are always recorded in Warnings
+	Warn bool `def:"true" desc:"print warnings to the console -- they are always recorded in Warnings"`
+}
+
+func (wp *WtStatsParams) Defaults() {
+	wp.Interval = 1
+	wp.BoundTol = 0.01
+	wp.SatThr = 0.5
+	wp.SilentThr = 1e-6
+	wp.Warn = true
+}
+
+func (wp *WtStatsParams) Update() {
+}
+
+// WtVarStats are summary statistics for one synaptic variable
+type WtVarStats struct {
+
+	// mean across synapses
+	Mean float32 `desc:"mean across synapses"`
+
+	// standard deviation across synapses
+	Std float32 `desc:"standard deviation across synapses"`
+
+	// minimum across synapses
+	Min float32 `desc:"minimum across synapses"`
+
+	// maximum across synapses
+	Max float32 `desc:"maximum across synapses"`
+}
+
+// wtVarAccum accumulates values for WtVarStats
+type wtVarAccum struct {
+	Sum, SumSq float64
+	MinMax     minmax.F32
+}
+
+func (wa *wtVarAccum) Init() {
+	wa.Sum = 0
+	wa.SumSq = 0
+	wa.MinMax.SetInfinity()
+}
+
+func (wa *wtVarAccum) Add(v float32) {
+	wa.Sum += float64(v)
+	wa.SumSq += float64(v) * float64(v)
+	wa.MinMax.FitValInRange(v)
+}
+
+func (wa *wtVarAccum) Stats(ws *WtVarStats, n int) {
+	if n == 0 {
+		*ws = WtVarStats{}
+		return
+	}
+	mean := wa.Sum / float64(n)
+	vr := wa.SumSq/float64(n) - mean*mean
+	ws.Mean = float32(mean)
+	ws.Std = float32(math.Sqrt(math.Max(vr, 0)))
+	ws.Min = wa.MinMax.Min
+	ws.Max = wa.MinMax.Max
+}
+
+// PrjnWtStats are the weight statistics for one projection
+type PrjnWtStats struct {
+
+	// name of the projection
+	Name string `desc:"name of the projection"`
+
+	// statistics for the effective weight Wt
+	Wt WtVarStats `desc:"statistics for the effective weight Wt"`
+
+	// statistics for the slowly adapting structural weight SWt
+	SWt WtVarStats `desc:"statistics for the slowly adapting structural weight SWt"`
+
+	// statistics for the linear learning weight LWt
+	LWt WtVarStats `desc:"statistics for the linear learning weight LWt"`
+
+	// fraction of LWt values within BoundTol of their 0 or 1 bounds
+	FracBound float32 `desc:"fraction of LWt values within BoundTol of their 0 or 1 bounds"`
+
+	// root-mean-square change in Wt since the previous computation, reflecting the net weight changes over the Interval -- this is not DWt, which is zeroed by WtFmDWt
+	WtChangeRMS float32 `desc:"root-mean-square change in Wt since the previous computation, reflecting the net weight changes over the Interval -- this is not DWt, which is zeroed by WtFmDWt"`
+
+	// mean absolute change in SWt since the weights were initialized
+	SWtDrift float32 `desc:"mean absolute change in SWt since the weights were initialized"`
+
+	// FracBound exceeded SatThr on the last computation
+	Saturated bool `desc:"FracBound exceeded SatThr on the last computation"`
+
+	// WtChangeRMS was below SilentThr on the last computation
+	Silent bool `desc:"WtChangeRMS was below SilentThr on the last computation"`
+
+	// [view: -] projection
+	Prjn *Prjn `view:"-" desc:"projection"`
+
+	// [view: -] Wt values as of the previous computation
+	PrvWt []float32 `view:"-" desc:"Wt values as of the previous computation"`
+
+	// [view: -] SWt values at initialization
+	InitSWt []float32 `view:"-" desc:"SWt values at initialization"`
+}
+
+// Init records the initial weight values
+func (ps *PrjnWtStats) Init(ctx *Context) {
+	pj := ps.Prjn
+	ns := int(pj.NSyns)
+	ps.PrvWt = make([]float32, ns)
+	ps.InitSWt = make([]float32, ns)
+	for syi := 0; syi < ns; syi++ {
+		syni := pj.SynStIdx + uint32(syi)
+		ps.PrvWt[syi] = SynV(ctx, syni, Wt)
+		ps.InitSWt[syi] = SynV(ctx, syni, SWt)
+	}
+}
+
+// Compute computes the stats from the current weights
+func (ps *PrjnWtStats) Compute(ctx *Context, wp *WtStatsParams) {
+	pj := ps.Prjn
+	ns := int(pj.NSyns)
+	var wa, swa, lwa wtVarAccum
+	wa.Init()
+	swa.Init()
+	lwa.Init()
+	nbound := 0
+	dwss := 0.0
+	drift := 0.0
+	for syi := 0; syi < ns; syi++ {
+		syni := pj.SynStIdx + uint32(syi)
+		wt := SynV(ctx, syni, Wt)
+		swt := SynV(ctx, syni, SWt)
+		lwt := SynV(ctx, syni, LWt)
+		wa.Add(wt)
+		swa.Add(swt)
+		lwa.Add(lwt)
+		if lwt <= wp.BoundTol || lwt >= 1-wp.BoundTol {
+			nbound++
+		}
+		dw := float64(wt - ps.PrvWt[syi])
+		dwss += dw * dw
+		ps.PrvWt[syi] = wt
+		drift += math.Abs(float64(swt - ps.InitSWt[syi]))
+	}
+	wa.Stats(&ps.Wt, ns)
+	swa.Stats(&ps.SWt, ns)
+	lwa.Stats(&ps.LWt, ns)
+	if ns > 0 {
+		ps.FracBound = float32(nbound) / float32(ns)
+		ps.WtChangeRMS = float32(math.Sqrt(dwss / float64(ns)))
+		ps.SWtDrift = float32(drift / float64(ns))
+	}
+	ps.Saturated = ps.FracBound > wp.SatThr
+	ps.Silent = ps.WtChangeRMS < wp.SilentThr
+}
+
+// WtStats monitors the health of the weights in all projections during
+// training, computing PrjnWtStats for each projection on the CPU at a
+// configurable Interval, with warnings when a projection saturates
+// (too many learning weights at their bounds) or goes silent (no
+// weight changes).  Call Init after the weights are initialized,
+// and Step after each WtFmDWt (e.g., at the end of each trial),
+// or at the end of each epoch.  When running on the GPU, Step syncs the
+// synapses back to the CPU before computing.
+// See LogAddWtStatsItems for logging.
+type WtStats struct {
+
+	// parameters
+	Params WtStatsParams `view:"inline" desc:"parameters"`
+
+	// stats for each projection
+	Prjns []*PrjnWtStats `desc:"stats for each projection"`
+
+	// [view: -] number of calls to Step since Init
+	Ctr int `view:"-" desc:"number of calls to Step since Init"`
+
+	// [view: -] number of times the stats have been computed since Init
+	NComputed int `view:"-" desc:"number of times the stats have been computed since Init"`
+
+	// warnings from the last computation
+	Warnings []string `desc:"warnings from the last computation"`
+}
+
+// NewWtStats returns a new WtStats monitor for all the projections
+// in the network, with default parameters, initialized from the
+// current weights.
+func (nt *Network) NewWtStats() *WtStats {
+	ws := &WtStats{}
+	ws.Params.Defaults()
+	ws.Init(nt)
+	return ws
+}
+
+// Init configures the stats for all the projections in given network,
+// and records the current weights as the initial weights.
+// Call after InitWts.
+func (ws *WtStats) Init(net *Network) {
+	ctx := &net.Ctx
+	ws.Prjns = nil
+	for _, ly := range net.Layers {
+		for _, pj := range ly.RcvPrjns {
+			ps := &PrjnWtStats{Name: pj.Name(), Prjn: pj}
+			ps.Init(ctx)
+			ws.Prjns = append(ws.Prjns, ps)
+		}
+	}
+	ws.Ctr = 0
+	ws.NComputed = 0
+	ws.Warnings = nil
+}
+
+// PrjnStats returns the stats for given projection name, nil if not found
+func (ws *WtStats) PrjnStats(name string) *PrjnWtStats {
+	for _, ps := range ws.Prjns {
+		if ps.Name == name {
+			return ps
+		}
+	}
+	return nil
+}
+
+// Step increments the counter and computes the stats if the Interval
+// has been reached, returning true if they were computed.
+func (ws *WtStats) Step(net *Network) bool {
+	ws.Ctr++
+	if ws.Ctr%ws.Params.Interval != 0 {
+		return false
+	}
+	if net.GPU.On {
+		net.GPU.SyncSynapsesFmGPU()
+	}
+	ws.Compute(net)
+	return true
+}
+
+// Compute computes the stats for all projections from the current
+// weights, and records (and prints, if Params.Warn) warnings for
+// any projections that are saturated or silent.
+func (ws *WtStats) Compute(net *Network) {
+	ctx := &net.Ctx
+	ws.Warnings = nil
+	for _, ps := range ws.Prjns {
+		ps.Compute(ctx, &ws.Params)
+		if ps.Prjn.IsOff() {
+			continue
+		}
+		if ps.Saturated {
+			ws.Warnings = append(ws.Warnings, fmt.Sprintf("WtStats: projection %s is saturated: %g of LWt values are at their bounds", ps.Name, ps.FracBound))
+		}
+		if ps.Silent {
+			ws.Warnings = append(ws.Warnings, fmt.Sprintf("WtStats: projection %s is silent: RMS weight change: %g", ps.Name, ps.WtChangeRMS))
+		}
+	}
+	ws.NComputed++
+	if ws.Params.Warn {
+		for _, w := range ws.Warnings {
+			fmt.Println(w)
+		}
+	}
+}
+
+// LogAddWtStatsItems adds items to given logs for the weight stats
+// of each projection, at given mode and time scale (e.g., Train, Epoch):
+// ProjName_Wt_Mean, _Std, _Min, _Max (likewise for SWt and LWt),
+// and ProjName_FracBound, _WtChangeRMS, _SWtDrift.  The values are those from
+// the most recent computation (see WtStats.Step).
+func LogAddWtStatsItems(lg *elog.Logs, ws *WtStats, mode etime.Modes, etm etime.Times) {
+	addItem := func(name string, fun func() float32) {
+		lg.AddItem(&elog.Item{
+			Name: name,
+			Type: etensor.FLOAT64,
+			Write: elog.WriteMap{
+				etime.Scope(mode, etm): func(ctx *elog.Context) {
+					ctx.SetFloat32(fun())
+				}}})
+	}
+	for _, ps := range ws.Prjns {
+		cps := ps
+		vars := []struct {
+			nm string
+			vs *WtVarStats
+		}{{"Wt", &cps.Wt}, {"SWt", &cps.SWt}, {"LWt", &cps.LWt}}
+		for _, vr := range vars {
+			vs := vr.vs
+			pfx := cps.Name + "_" + vr.nm + "_"
+			addItem(pfx+"Mean", func() float32 { return vs.Mean })
+			addItem(pfx+"Std", func() float32 { return vs.Std })
+			addItem(pfx+"Min", func() float32 { return vs.Min })
+			addItem(pfx+"Max", func() float32 { return vs.Max })
+		}
+		addItem(cps.Name+"_FracBound", func() float32 { return cps.FracBound })
+		addItem(cps.Name+"_WtChangeRMS", func() float32 { return cps.WtChangeRMS })
+		addItem(cps.Name+"_SWtDrift", func() float32 { return cps.SWtDrift })
+	}
+}
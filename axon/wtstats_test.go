@@ -0,0 +1,74 @@
+// Copyright (c) 2023, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package axon
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/elog"
+	"github.com/emer/emergent/estats"
+	"github.com/emer/emergent/etime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWtStats(t *testing.T) {
+	net, ctx := newSettleTestNet(t)
+	ws := net.NewWtStats()
+	ws.Params.Interval = 2
+	ws.Params.Warn = false
+	assert.Equal(t, 1, len(ws.Prjns))
+	ps := ws.PrjnStats("InputToHidden")
+	assert.NotNil(t, ps)
+	pj := ps.Prjn
+
+	assert.False(t, ws.Step(net))
+	assert.True(t, ws.Step(net))
+	assert.Equal(t, 1, ws.NComputed)
+	assert.Greater(t, ps.Wt.Mean, float32(0))
+	assert.InDelta(t, 0.5, ps.LWt.Mean, 0.05)
+	assert.LessOrEqual(t, ps.Wt.Min, ps.Wt.Mean)
+	assert.GreaterOrEqual(t, ps.Wt.Max, ps.Wt.Mean)
+	assert.Equal(t, float32(0), ps.FracBound)
+	assert.Equal(t, float32(0), ps.WtChangeRMS)
+	assert.True(t, ps.Silent)
+	assert.False(t, ps.Saturated)
+	assert.Equal(t, 1, len(ws.Warnings))
+
+	// weight changes
+	for syi := uint32(0); syi < pj.NSyns; syi++ {
+		AddSynV(ctx, pj.SynStIdx+syi, Wt, 0.01)
+	}
+	ws.Compute(net)
+	assert.InDelta(t, 0.01, ps.WtChangeRMS, 1.0e-6)
+	assert.False(t, ps.Silent)
+	assert.Equal(t, 0, len(ws.Warnings))
+
+	// saturate half of the learning weights, and drift SWt
+	for syi := uint32(0); syi < pj.NSyns; syi++ {
+		syni := pj.SynStIdx + syi
+		if syi%2 == 0 {
+			SetSynV(ctx, syni, LWt, 1)
+		}
+		AddSynV(ctx, syni, SWt, 0.1)
+	}
+	ws.Params.SatThr = 0.4
+	ws.Compute(net)
+	assert.Equal(t, float32(0.5), ps.FracBound)
+	assert.InDelta(t, 0.1, ps.SWtDrift, 1.0e-6)
+	assert.Equal(t, float32(1), ps.LWt.Max)
+	assert.True(t, ps.Saturated)
+	assert.True(t, ps.Silent) // Wt not changed
+	assert.Equal(t, 2, len(ws.Warnings))
+
+	lg := &elog.Logs{}
+	LogAddWtStatsItems(lg, ws, etime.Train, etime.Epoch)
+	lg.CreateTables()
+	lg.SetContext(&estats.Stats{}, net)
+	lg.LogRow(etime.Train, etime.Epoch, 0)
+	dt := lg.Table(etime.Train, etime.Epoch)
+	assert.Equal(t, 15, dt.NumCols())
+	assert.InDelta(t, ps.LWt.Mean, dt.CellFloat("InputToHidden_LWt_Mean", 0), 1.0e-6)
+	assert.InDelta(t, 0.5, dt.CellFloat("InputToHidden_FracBound", 0), 1.0e-6)
+}
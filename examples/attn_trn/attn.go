@@ -2,8 +2,6 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-//go:build this_is_broken_we_should_fix_or_delete
-
 /*
 attn_trn: test of trn-based attention in basic V1, V2, LIP localist network with gabor inputs.
 
@@ -20,7 +18,9 @@ import (
 	"github.com/emer/axon/axon"
 	"github.com/emer/emergent/emer"
 	"github.com/emer/emergent/env"
+	"github.com/emer/emergent/etime"
 	"github.com/emer/emergent/evec"
+	"github.com/emer/emergent/netparams"
 	"github.com/emer/emergent/netview"
 	"github.com/emer/emergent/params"
 	"github.com/emer/emergent/prjn"
@@ -75,138 +75,101 @@ const (
 
 // ParamSets is the default set of parameters -- Base is always applied, and others can be optionally
 // selected to apply on top of that
-var ParamSets = params.Sets{
-	{Name: "Base", Desc: "these are the best params", Sheets: params.Sheets{
-		"Network": &params.Sheet{
-			{Sel: "Prjn", Desc: "no learning",
-				Params: params.Params{
-					"Prjn.Learn.Learn":    "false",
-					"Prjn.SWts.Init.Mean": "0.8",
-					"Prjn.SWts.Init.Var":  "0",
-					"Prjn.SWts.Init.Sym":  "false", // for lesions, just in case
-				}},
-			{Sel: "Layer", Desc: "pool etc",
-				Params: params.Params{
-					"Layer.Inhib.Pool.Gi":        "1.0",
-					"Layer.Inhib.Pool.On":        "true",
-					"Layer.Inhib.Pool.FFEx0":     "0.18",
-					"Layer.Inhib.Pool.FFEx":      "0.1",
-					"Layer.Inhib.Layer.On":       "false", // TRC drives layer-level
-					"Layer.Inhib.ActAvg.Nominal": "0.01",
-					"Layer.Inhib.Layer.FFEx0":    "0.18",
-					"Layer.Inhib.Layer.FFEx":     "0.1",
-					"Layer.Acts.Decay.Act":       "1",
-					"Layer.Acts.Decay.Glong":     "1",
-					"Layer.Acts.Decay.KNa":       "1",
-					"Layer.Acts.KNa.On":          "false", // turn off by default
-					"Layer.Acts.Noise.Dist":      "Gaussian",
-					"Layer.Acts.Noise.Var":       "0.002",
-					"Layer.Acts.Noise.Type":      "NoNoise", // "GeNoise",
-				}},
-			{Sel: "SuperLayer", Desc: "pool etc",
-				Params: params.Params{
-					"Layer.Inhib.Layer.On":       "true",
-					"Layer.Inhib.Layer.Gi":       "1.2",
-					"Layer.Inhib.Layer.FFEx0":    "0.01", // must be < FF0
-					"Layer.Inhib.Layer.FFEx":     "0",    // some effect randomly
-					"Layer.Inhib.Layer.FF0":      "0.01", // doesn't have any effect until < .02
-					"Layer.Inhib.Pool.Gi":        "1.5",
-					"Layer.Inhib.Pool.On":        "true",
-					"Layer.Inhib.Pool.FFEx0":     "0.18",
-					"Layer.Inhib.Pool.FFEx":      "0", // 10? no big effects
-					"Layer.Inhib.ActAvg.Nominal": "0.05",
-					"Layer.Acts.Attn.On":         "true",
-					"Layer.Acts.Attn.Min":        "0.2", // 0.5
-					"Layer.Inhib.Topo.On":        "true",
-					"Layer.Inhib.Topo.Width":     "4",
-					"Layer.Inhib.Topo.Sigma":     "1.0",
-					"Layer.Inhib.Topo.Gi":        "0.05",
-					"Layer.Inhib.Topo.FF0":       "0.15",
-					"Layer.Acts.Noise.Dist":      "Gaussian",
-					"Layer.Acts.Noise.Var":       "0.02",    // .02
-					"Layer.Acts.Noise.Type":      "GeNoise", // "GeNoise",
-				}},
-			{Sel: "TRCALayer", Desc: "topo etc pool etc",
-				Params: params.Params{
-					"Layer.Inhib.Pool.On":        "false",
-					"Layer.Inhib.Layer.On":       "true",
-					"Layer.Inhib.Layer.Gi":       "1.2",
-					"Layer.Inhib.Layer.FFEx0":    "0.18",
-					"Layer.Inhib.Layer.FFEx":     "0",
-					"Layer.Inhib.ActAvg.Nominal": "0.2",
-					"Layer.Inhib.Topo.On":        "true",
-					"Layer.Inhib.Topo.Width":     "4",
-					"Layer.Inhib.Topo.Sigma":     "1.0",
-					"Layer.Inhib.Topo.Gi":        "0.03",
-					"Layer.Inhib.Topo.FF0":       "0.18",
-					"Layer.SendAttn.Thr":         "0.1",
-				}},
-			{Sel: "#V2CTA", Desc: "topo etc pool etc",
-				Params: params.Params{
-					"Layer.Inhib.Pool.On":        "false",
-					"Layer.Inhib.Layer.On":       "true",
-					"Layer.Inhib.Layer.Gi":       "1.0",
-					"Layer.Inhib.Layer.FFEx0":    "0.15",
-					"Layer.Inhib.Layer.FFEx":     "20",
-					"Layer.Inhib.ActAvg.Nominal": "0.3",
-				}},
-			{Sel: "#LIP", Desc: "pool etc",
-				Params: params.Params{
-					"Layer.Inhib.Pool.On":        "false",
-					"Layer.Inhib.Layer.Gi":       "1.5",
-					"Layer.Inhib.Layer.On":       "true", // TRN drives all layer-level
-					"Layer.Inhib.ActAvg.Nominal": "0.3",
-				}},
-			{Sel: "TRNLayer", Desc: "trn just does whole layer",
-				Params: params.Params{
-					"Layer.Inhib.Pool.On":        "false",
-					"Layer.Inhib.Layer.On":       "true",
-					"Layer.Inhib.Layer.Gi":       "2.0",
-					"Layer.Inhib.ActAvg.Nominal": ".03",
-					"Layer.Acts.Dt.GTau":         "3",
-				}},
-			{Sel: ".BackPrjn", Desc: "weaker output",
-				Params: params.Params{
-					"Prjn.PrjnScale.Rel": "0.1",
-				}},
-			{Sel: "#V2ToV2CTA", Desc: "",
-				Params: params.Params{
-					"Prjn.PrjnScale.Rel": "0.2", // 0.8
-				}},
-			{Sel: "#LIPToV2CTA", Desc: "",
-				Params: params.Params{
-					"Prjn.PrjnScale.Rel": "0.5", // 0.5
-				}},
-			{Sel: ".Inhib", Desc: "",
-				Params: params.Params{
-					"Prjn.PrjnScale.Abs": "1",
-				}},
-			{Sel: "#LIPToV2TA", Desc: "",
-				Params: params.Params{
-					"Prjn.PrjnScale.Rel": "0.3", // 0.3
-				}},
-			{Sel: "#LIPToV2", Desc: "",
-				Params: params.Params{
-					"Prjn.PrjnScale.Rel": "0.0",
-				}},
-			{Sel: "#V2CTAToV2CTA", Desc: "lateral within V2CTA",
-				Params: params.Params{
-					"Prjn.PrjnScale.Rel": "0.0", // 0.4
-				}},
-			{Sel: "#V1ToV2", Desc: "",
-				Params: params.Params{
-					"Prjn.PrjnScale.Abs": "1.2",
-				}},
-		},
-	}},
-	{Name: "KNaAdapt", Desc: "Turn on KNa adaptation", Sheets: params.Sheets{
-		"Network": &params.Sheet{
-			{Sel: "Layer", Desc: "KNa adapt on",
-				Params: params.Params{
-					"Layer.Acts.KNa.On": "true",
-				}},
-		},
-	}},
+var ParamSets = netparams.Sets{
+	"Base": {
+		{Sel: "Prjn", Desc: "no learning",
+			Params: params.Params{
+				"Prjn.Learn.Learn":    "false",
+				"Prjn.SWts.Init.Mean": "0.8",
+				"Prjn.SWts.Init.Var":  "0",
+				"Prjn.SWts.Init.Sym":  "false", // for lesions, just in case
+			}},
+		{Sel: "Layer", Desc: "pool etc",
+			Params: params.Params{
+				"Layer.Inhib.Pool.Gi":        "1.0",
+				"Layer.Inhib.Pool.On":        "true",
+				"Layer.Inhib.Layer.On":       "false", // attention layer drives layer-level
+				"Layer.Inhib.ActAvg.Nominal": "0.01",
+				"Layer.Acts.Decay.Act":       "1",
+				"Layer.Acts.Decay.Glong":     "1",
+				"Layer.Acts.KNa.On":          "false", // turn off by default
+				"Layer.Acts.Noise.On":        "false",
+			}},
+		{Sel: ".SuperLayer", Desc: "pool etc",
+			Params: params.Params{
+				"Layer.Inhib.Layer.On":       "true",
+				"Layer.Inhib.Layer.Gi":       "1.2",
+				"Layer.Inhib.Pool.Gi":        "1.5",
+				"Layer.Inhib.Pool.On":        "true",
+				"Layer.Inhib.ActAvg.Nominal": "0.05",
+				"Layer.Acts.AttnMod.On":      "true",
+				"Layer.Acts.AttnMod.Min":     "0.2", // 0.5
+				"Layer.Inhib.Topo.On":        "true",
+				"Layer.Inhib.Topo.Width":     "4",
+				"Layer.Inhib.Topo.Sigma":     "1.0",
+				"Layer.Inhib.Topo.Gi":        "0.05",
+				"Layer.Inhib.Topo.FF0":       "0.15",
+				"Layer.Acts.Noise.On":        "true",
+				"Layer.Acts.Noise.Ge":        "0.002",
+				"Layer.Acts.Noise.Gi":        "0.002",
+			}},
+		{Sel: "#V2", Desc: "attention from V2TA",
+			Params: params.Params{
+				"Layer.AttnSrc.Thr": "0.1",
+			}},
+		{Sel: ".AttnLayer", Desc: "topo etc pool etc",
+			Params: params.Params{
+				"Layer.Inhib.Pool.On":        "false",
+				"Layer.Inhib.Layer.On":       "true",
+				"Layer.Inhib.Layer.Gi":       "1.2",
+				"Layer.Inhib.ActAvg.Nominal": "0.2",
+				"Layer.Inhib.Topo.On":        "true",
+				"Layer.Inhib.Topo.Width":     "4",
+				"Layer.Inhib.Topo.Sigma":     "1.0",
+				"Layer.Inhib.Topo.Gi":        "0.03",
+				"Layer.Inhib.Topo.FF0":       "0.18",
+			}},
+		{Sel: "#V2CTA", Desc: "topo etc pool etc",
+			Params: params.Params{
+				"Layer.Inhib.Pool.On":        "false",
+				"Layer.Inhib.Layer.On":       "true",
+				"Layer.Inhib.Layer.Gi":       "1.0",
+				"Layer.Inhib.ActAvg.Nominal": "0.3",
+			}},
+		{Sel: "#LIP", Desc: "pool etc",
+			Params: params.Params{
+				"Layer.Inhib.Pool.On":        "false",
+				"Layer.Inhib.Layer.Gi":       "1.5",
+				"Layer.Inhib.Layer.On":       "true",
+				"Layer.Inhib.ActAvg.Nominal": "0.3",
+			}},
+		{Sel: ".BackPrjn", Desc: "weaker output",
+			Params: params.Params{
+				"Prjn.PrjnScale.Rel": "0.1",
+			}},
+		{Sel: "#LIPToV2CTA", Desc: "",
+			Params: params.Params{
+				"Prjn.PrjnScale.Rel": "0.5", // 0.5
+			}},
+		{Sel: "#LIPToV2TA", Desc: "",
+			Params: params.Params{
+				"Prjn.PrjnScale.Rel": "0.3", // 0.3
+			}},
+		{Sel: "#LIPToV2", Desc: "",
+			Params: params.Params{
+				"Prjn.PrjnScale.Rel": "0.0",
+			}},
+		{Sel: "#V1ToV2", Desc: "",
+			Params: params.Params{
+				"Prjn.PrjnScale.Abs": "1.2",
+			}},
+	},
+	"KNaAdapt": {
+		{Sel: "Layer", Desc: "KNa adapt on",
+			Params: params.Params{
+				"Layer.Acts.KNa.On": "true",
+			}},
+	},
 }
 
 // Sim encapsulates the entire simulation model, and we define all the
@@ -246,8 +209,8 @@ type Sim struct {
 	// [view: no-inline] aggregate stats on testing data
 	TstStats *etable.Table `view:"no-inline" desc:"aggregate stats on testing data"`
 
-	// [view: no-inline] full collection of param sets -- not really interesting for this model
-	Params params.Sets `view:"no-inline" desc:"full collection of param sets -- not really interesting for this model"`
+	// [view: inline] all parameter management
+	Params emer.NetParams `view:"inline" desc:"all parameter management"`
 
 	// Testing environment -- manages iterating over testing
 	TestEnv AttnEnv `desc:"Testing environment -- manages iterating over testing"`
@@ -259,7 +222,7 @@ type Sim struct {
 	ViewOn bool `desc:"whether to update the network view while running"`
 
 	// at what time scale to update the display during testing?  Change to AlphaCyc to make display updating go faster
-	ViewUpdt axon.TimeScales `desc:"at what time scale to update the display during testing?  Change to AlphaCyc to make display updating go faster"`
+	ViewUpdt etime.Times `desc:"at what time scale to update the display during testing?  Change to AlphaCyc to make display updating go faster"`
 
 	// layer to measure attentional effects on
 	AttnLay string `desc:"layer to measure attentional effects on"`
@@ -276,6 +239,9 @@ type Sim struct {
 	// percent modulation = (S1Act - S2Act) / S1Act
 	PctMod float32 `desc:"percent modulation = (S1Act - S2Act) / S1Act"`
 
+	// settling monitor for the AttnLay layer, measuring reaction time (RT) as the first cycle where a localist winner emerges
+	Settle *axon.SettleMonitor `desc:"settling monitor for the AttnLay layer, measuring reaction time (RT) as the first cycle where a localist winner emerges"`
+
 	// [view: -] main GUI window
 	Win *gi.Window `view:"-" desc:"main GUI window"`
 
@@ -316,9 +282,9 @@ func (ss *Sim) New() {
 	ss.TstTrlLog = &etable.Table{}
 	ss.TstRunLog = &etable.Table{}
 	ss.TstStats = &etable.Table{}
-	ss.Params = ParamSets
+	ss.Params.Config(ParamSets, "", "", ss.Net)
 	ss.ViewOn = true
-	ss.ViewUpdt = axon.AlphaCycle // axon.Cycle // axon.FastSpike
+	ss.ViewUpdt = etime.AlphaCycle // etime.Cycle // etime.FastSpike
 	ss.TstRecLays = []string{"V2"}
 
 	ss.Prjn3x3Skp1 = prjn.NewPoolTile()
@@ -383,16 +349,19 @@ func (ss *Sim) UpdateEnv() {
 }
 
 func (ss *Sim) ConfigNet(net *axon.Network) {
+	ctx := &ss.Context
 	net.InitName(net, "AttnNet")
+	net.SetMaxData(ctx, 1)
 	psz := ss.TestEnv.V1Pools
 	fsz := ss.TestEnv.V1Feats
 	v1 := net.AddLayer4D("V1", psz.Y, psz.X, fsz.Y, fsz.X, axon.InputLayer)
 	v2 := net.AddSuperLayer4D("V2", psz.Y, psz.X, fsz.Y, fsz.X)
 	lip := net.AddLayer4D("LIP", psz.Y, psz.X, 1, 1, axon.InputLayer)
 	v2cta := net.AddLayer4D("V2CTA", psz.Y, psz.X, 1, 1, axon.SuperLayer)
-	v2ta := net.AddTRCALayer4D("V2TA", psz.Y, psz.X, 1, 1)
+	v2ta := net.AddLayer4D("V2TA", psz.Y, psz.X, 1, 1, axon.SuperLayer)
+	v2ta.SetClass("AttnLayer")
 
-	v2ta.SendAttn.ToLays.Add("V2")
+	v2.SetBuildConfig("AttnLayName", "V2TA") // V2TA attention map drives V2 Attn
 
 	v2ta.SetRelPos(relpos.Rel{Rel: relpos.RightOf, Other: "V2", YAlign: relpos.Front, Space: 1})
 	v2cta.SetRelPos(relpos.Rel{Rel: relpos.Behind, Other: "V2TA", XAlign: relpos.Left, Space: 2})
@@ -405,32 +374,39 @@ func (ss *Sim) ConfigNet(net *axon.Network) {
 	circle.TopoWts = true
 	circle.Sigma = 1
 
-	// net.ConnectLayers(v2ct, v2p, one2one, emer.Forward)
+	// net.ConnectLayers(v2ct, v2p, one2one, axon.ForwardPrjn)
 
-	net.ConnectLayers(v1, v2, one2one, emer.Forward)
-	net.ConnectLayers(v2, v2ta, ss.Prjn5x5Skp1, emer.Forward) // or v2cta
-	// net.ConnectLayers(v2, v2, ss.Prjn5x5Skp1, emer.Inhib)
-	// net.ConnectLayers(v2ta, v2ta, circle, emer.Inhib)
-	// net.ConnectLayers(v2cta, v2cta, circle, emer.Lateral)
-	// net.ConnectLayers(v2cta, v2ta, ss.Prjn5x5Skp1, emer.Forward)
-	net.ConnectLayers(lip, v2, pone2one, emer.Back)
-	net.ConnectLayers(lip, v2cta, pone2one, emer.Back) // ss.Prjn5x5Skp1
-	net.ConnectLayers(lip, v2ta, pone2one, emer.Back)  // ss.Prjn5x5Skp1 was ponetoone
+	net.ConnectLayers(v1, v2, one2one, axon.ForwardPrjn)
+	net.ConnectLayers(v2, v2ta, ss.Prjn5x5Skp1, axon.ForwardPrjn) // or v2cta
+	// net.ConnectLayers(v2, v2, ss.Prjn5x5Skp1, axon.InhibPrjn)
+	// net.ConnectLayers(v2ta, v2ta, circle, axon.InhibPrjn)
+	// net.ConnectLayers(v2cta, v2cta, circle, axon.LateralPrjn)
+	// net.ConnectLayers(v2cta, v2ta, ss.Prjn5x5Skp1, axon.ForwardPrjn)
+	net.ConnectLayers(lip, v2, pone2one, axon.BackPrjn)
+	net.ConnectLayers(lip, v2cta, pone2one, axon.BackPrjn) // ss.Prjn5x5Skp1
+	net.ConnectLayers(lip, v2ta, pone2one, axon.BackPrjn)  // ss.Prjn5x5Skp1 was ponetoone
 
-	err := net.Build()
+	err := net.Build(ctx)
 	if err != nil {
 		log.Println(err)
 		return
 	}
 	net.Defaults()
-	ss.SetParams("Network", false) // only set Network params
+	ss.SetParams()
 	ss.InitWts()
+
+	ss.Settle, err = axon.NewSettleMonitor(net.AxonLayerByName(ss.AttnLay))
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	ss.Settle.Params.Crit = axon.SettleWinner
 }
 
 // InitWts initialize weights
 func (ss *Sim) InitWts() {
 	net := ss.Net
-	net.InitWts()
+	net.InitWts(&ss.Context)
 	net.InitTopoSWts() //  sets all wt scales
 }
 
@@ -446,7 +422,7 @@ func (ss *Sim) Init() {
 	// ss.Context.CycPerQtr = 55 // 220 total
 	ss.InitWts()
 	ss.StopNow = false
-	ss.SetParams("", false) // all sheets
+	ss.SetParams()
 	ss.TstTrlLog.SetNumRows(0)
 	ss.UpdateView(false)
 }
@@ -461,25 +437,25 @@ func (ss *Sim) Counters() string {
 
 func (ss *Sim) UpdateView(train bool) {
 	if ss.NetView != nil && ss.NetView.IsVisible() {
-		ss.NetView.Record(ss.Counters())
+		ss.NetView.Record(ss.Counters(), int(ss.Context.Cycle))
 		// note: essential to use Go version of update when called from another goroutine
 		ss.NetView.GoUpdate() // note: using counters is significantly slower..
 	}
 }
 
-func (ss *Sim) UpdateViewTime(train bool, viewUpdt axon.TimeScales) {
+func (ss *Sim) UpdateViewTime(train bool, viewUpdt etime.Times) {
 	switch viewUpdt {
-	case axon.Cycle:
+	case etime.Cycle:
 		ss.UpdateView(train)
-	case axon.FastSpike:
+	case etime.FastSpike:
 		if ss.Context.Cycle%10 == 0 {
 			ss.UpdateView(train)
 		}
-	case axon.GammaCycle:
+	case etime.GammaCycle:
 		if ss.Context.Cycle%25 == 0 {
 			ss.UpdateView(train)
 		}
-	case axon.AlphaCycle:
+	case etime.AlphaCycle:
 		if ss.Context.Cycle%100 == 0 {
 			ss.UpdateView(train)
 		}
@@ -496,47 +472,52 @@ func (ss *Sim) UpdateViewTime(train bool, viewUpdt axon.TimeScales) {
 // Handles netview updating within scope, and calls TrainStats()
 func (ss *Sim) ThetaCyc(train bool) {
 	// ss.Win.PollEvents() // this can be used instead of running in a separate goroutine
+	ctx := &ss.Context
 	viewUpdt := ss.ViewUpdt
 
 	plusCyc := 50
 	minusCyc := ss.Cycles - plusCyc
 
-	ss.Net.NewState()
-	ss.Context.NewState()
+	ss.Net.NewState(ctx)
+	ctx.NewState(etime.Test)
+	ss.Settle.NewTrial()
 	for cyc := 0; cyc < minusCyc; cyc++ { // do the minus phase
-		ss.Net.Cycle(&ss.Context)
+		ss.Net.Cycle(ctx)
+		ss.Settle.Cycle(ctx)
 		// ss.LogTstCyc(ss.TstCycLog, ss.Context.Cycle)
-		ss.Context.CycleInc()
-		switch ss.Context.Cycle { // save states at beta-frequency -- not used computationally
-		case 75:
-			ss.Net.ActSt1(&ss.Context)
+		ctx.CycleInc()
+		switch ctx.Cycle { // save states at beta-frequency -- not used computationally
+		case 50:
+			ss.Net.SpkSt1(ctx)
 		case 100:
-			ss.Net.ActSt2(&ss.Context)
+			ss.Net.SpkSt2(ctx)
 		}
 		if cyc == minusCyc-1 { // do before view update
-			ss.Net.MinusPhase(&ss.Context)
+			ss.Net.MinusPhase(ctx)
 		}
 		if ss.ViewOn {
 			ss.UpdateViewTime(train, viewUpdt)
 		}
 	}
-	ss.Context.NewPhase()
-	if viewUpdt == axon.Phase {
+	ctx.PlusPhase.SetBool(true)
+	ctx.NewPhase(true)
+	ss.Net.PlusPhaseStart(ctx)
+	if viewUpdt == etime.Phase {
 		ss.UpdateView(train)
 	}
 	for cyc := 0; cyc < plusCyc; cyc++ { // do the plus phase
-		ss.Net.Cycle(&ss.Context)
+		ss.Net.Cycle(ctx)
+		ss.Settle.Cycle(ctx)
 		// ss.LogTstCyc(ss.TstCycLog, ss.Context.Cycle)
-		ss.Context.CycleInc()
+		ctx.CycleInc()
 		if cyc == plusCyc-1 { // do before view update
-			ss.Net.PlusPhase(&ss.Context)
-			// ss.Net.CTCtxt(&ss.Context) // update context at end
+			ss.Net.PlusPhase(ctx)
 		}
 		if ss.ViewOn {
 			ss.UpdateViewTime(train, viewUpdt)
 		}
 	}
-	if viewUpdt == axon.Phase || viewUpdt == axon.AlphaCycle || viewUpdt == axon.ThetaCycle {
+	if viewUpdt == etime.Phase || viewUpdt == etime.AlphaCycle || viewUpdt == etime.ThetaCycle {
 		ss.UpdateView(train)
 	}
 
@@ -550,7 +531,8 @@ func (ss *Sim) ThetaCyc(train bool) {
 // args so that it can be used for various different contexts
 // (training, testing, etc).
 func (ss *Sim) ApplyInputs(en env.Env) {
-	ss.Net.InitExt() // clear any existing inputs -- not strictly necessary if always
+	ctx := &ss.Context
+	ss.Net.InitExt(ctx) // clear any existing inputs -- not strictly necessary if always
 	// going to the same layers, but good practice and cheap anyway
 
 	lays := []string{"V1", "LIP"}
@@ -558,9 +540,10 @@ func (ss *Sim) ApplyInputs(en env.Env) {
 		ly := ss.Net.AxonLayerByName(lnm)
 		pats := en.State(ly.Nm)
 		if pats != nil {
-			ly.ApplyExt(pats)
+			ly.ApplyExt(ctx, 0, pats)
 		}
 	}
+	ss.Net.ApplyExts(ctx)
 }
 
 func (ss *Sim) StimMaxAct(stm *Stim, lnm string) float32 {
@@ -581,8 +564,8 @@ func (ss *Sim) StimMaxAct(stm *Stim, lnm string) float32 {
 				continue
 			}
 			pi := y*sz.X + x
-			pl := &ly.Pools[pi+1]
-			max = mat32.Max(max, pl.Inhib.Act.Max)
+			pl := ly.Pool(uint32(pi+1), 0)
+			max = mat32.Max(max, pl.AvgMax.Act.Cycle.Max)
 		}
 	}
 	return max
@@ -613,12 +596,12 @@ func (ss *Sim) StimAvgAct(stm *Stim, lnm string) float32 {
 				continue
 			}
 			pi := y*sz.X + x
-			pl := &ly.Pools[pi+1]
-			for ni := pl.StIdx; ni < pl.EdIdx; ni++ {
-				nrn := &ly.Neurons[ni]
-				if nrn.Act >= thr {
-					// avg += nrn.Attn
-					avg += nrn.Act
+			pl := ly.Pool(uint32(pi+1), 0)
+			for lni := pl.StIdx; lni < pl.EdIdx; lni++ {
+				act := axon.NrnV(&ss.Context, ly.NeurStIdx+lni, 0, axon.Act)
+				if act >= thr {
+					// avg += axon.NrnV(&ss.Context, ly.NeurStIdx+lni, 0, axon.Attn)
+					avg += act
 				}
 			}
 		}
@@ -673,13 +656,13 @@ func (ss *Sim) TestTrial() {
 	// Query counters FIRST
 	_, _, chg := ss.TestEnv.Counter(env.Epoch)
 	if chg {
-		if ss.ViewUpdt > axon.AlphaCycle {
+		if ss.ViewUpdt > etime.AlphaCycle {
 			ss.UpdateView(false)
 		}
 		return
 	}
 
-	ss.Net.InitActs()
+	ss.Net.InitActs(&ss.Context)
 	ss.ApplyInputs(&ss.TestEnv)
 	ss.ThetaCyc(false)
 	// ss.ThetaCyc(false) // 2x
@@ -695,7 +678,7 @@ func (ss *Sim) TestTrialGUI() {
 
 // TestAll runs through the full set of testing items
 func (ss *Sim) TestAll() {
-	ss.SetParams("", false) // in case params were changed
+	ss.SetParams() // in case params were changed
 	ss.UpdateEnv()
 	ss.TestEnv.Init(0)
 	for {
@@ -716,7 +699,7 @@ func (ss *Sim) RunTestAll() {
 
 // TestRuns runs through the full set of testing items
 func (ss *Sim) TestRuns() {
-	ss.SetParams("", false) // in case params were changed
+	ss.SetParams() // in case params were changed
 	ss.UpdateEnv()
 	ss.TestEnv.Init(0)
 	for {
@@ -745,53 +728,13 @@ func (ss *Sim) RunTestRuns() {
 /////////////////////////////////////////////////////////////////////////
 //   Params setting
 
-// SetParams sets the params for "Base" and then current ParamSet.
-// If sheet is empty, then it applies all avail sheets (e.g., Network, Sim)
-// otherwise just the named sheet
-// if setMsg = true then we output a message for each param that was set.
-func (ss *Sim) SetParams(sheet string, setMsg bool) error {
-	if sheet == "" {
-		// this is important for catching typos and ensuring that all sheets can be used
-		ss.Params.ValidateSheets([]string{"Network", "Sim"})
-	}
-
-	// spo := ss.Params.SetByName("Base").SheetByName("Network").SelByName(".SpatToObj")
-	// spo.Params.SetParamByName("Prjn.PrjnScale.Rel", fmt.Sprintf("%g", ss.SpatToObj))
-	// vsp := ss.Params.SetByName("Base").SheetByName("Network").SelByName("#V1ToSpat1")
-	// vsp.Params.SetParamByName("Prjn.PrjnScale.Rel", fmt.Sprintf("%g", ss.V1ToSpat1))
-
-	err := ss.SetParamsSet("Base", sheet, setMsg)
-
+// SetParams sets the params for "Base" and then the KNaAdapt
+// sheet if KNaAdapt is on.
+func (ss *Sim) SetParams() error {
+	err := ss.Params.SetAll()
 	if ss.KNaAdapt {
-		err = ss.SetParamsSet("KNaAdapt", sheet, setMsg)
-	}
-	return err
-}
-
-// SetParamsSet sets the params for given params.Set name.
-// If sheet is empty, then it applies all avail sheets (e.g., Network, Sim)
-// otherwise just the named sheet
-// if setMsg = true then we output a message for each param that was set.
-func (ss *Sim) SetParamsSet(setNm string, sheet string, setMsg bool) error {
-	pset, err := ss.Params.SetByNameTry(setNm)
-	if err != nil {
-		return err
-	}
-	if sheet == "" || sheet == "Network" {
-		netp, ok := pset.Sheets["Network"]
-		if ok {
-			ss.Net.ApplyParams(netp, setMsg)
-		}
+		err = ss.Params.SetAllSheet("KNaAdapt")
 	}
-
-	if sheet == "" || sheet == "Sim" {
-		simp, ok := pset.Sheets["Sim"]
-		if ok {
-			simp.Apply(ss, setMsg)
-		}
-	}
-	// note: if you have more complex environments with parameters, definitely add
-	// sheets for them, e.g., "TrainEnv", "TestEnv" etc
 	return err
 }
 
@@ -829,16 +772,19 @@ func (ss *Sim) LogTstTrl(dt *etable.Table) {
 	dt.SetCellFloat("S1Act", row, float64(ss.S1Act))
 	dt.SetCellFloat("S2Act", row, float64(ss.S2Act))
 	dt.SetCellFloat("PctMod", row, float64(ss.PctMod))
+	dt.SetCellFloat("RT", row, float64(ss.Settle.RT[0]))
 
 	for _, lnm := range ss.TstRecLays {
 		tsr := ss.ValsTsr(lnm)
 		ly := ss.Net.AxonLayerByName(lnm)
-		ly.UnitValsTensor(tsr, "Act")
+		ly.UnitValsTensor(tsr, "Act", 0)
 		dt.SetCellTensor(lnm, row, tsr)
 	}
 
 	// note: essential to use Go version of update when called from another goroutine
-	ss.TstTrlPlot.GoUpdate()
+	if ss.TstTrlPlot != nil {
+		ss.TstTrlPlot.GoUpdate()
+	}
 }
 
 func (ss *Sim) ConfigTstTrlLog(dt *etable.Table) {
@@ -855,6 +801,7 @@ func (ss *Sim) ConfigTstTrlLog(dt *etable.Table) {
 		{"S1Act", etensor.FLOAT64, nil, nil},
 		{"S2Act", etensor.FLOAT64, nil, nil},
 		{"PctMod", etensor.FLOAT64, nil, nil},
+		{"RT", etensor.FLOAT64, nil, nil},
 	}
 	for _, lnm := range ss.TstRecLays {
 		ly := ss.Net.AxonLayerByName(lnm)
@@ -876,6 +823,7 @@ func (ss *Sim) ConfigTstTrlPlot(plt *eplot.Plot2D, dt *etable.Table) *eplot.Plot
 	plt.SetColParams("S1Act", eplot.On, eplot.FixMin, 0, eplot.FixMax, 1)
 	plt.SetColParams("S2Act", eplot.On, eplot.FixMin, 0, eplot.FixMax, 1)
 	plt.SetColParams("PctMod", eplot.On, eplot.FixMin, 0, eplot.FixMax, 1)
+	plt.SetColParams("RT", eplot.Off, eplot.FixMin, 0, eplot.FixMax, 200)
 
 	for _, lnm := range ss.TstRecLays {
 		cp := plt.SetColParams(lnm, eplot.Off, eplot.FixMin, 0, eplot.FixMax, 1)
@@ -892,9 +840,12 @@ func (ss *Sim) TestStats() {
 	split.Agg(spl, "S1Act", agg.AggMean)
 	split.Agg(spl, "S2Act", agg.AggMean)
 	split.Agg(spl, "PctMod", agg.AggMean)
+	split.Agg(spl, "RT", agg.AggMean)
 	ss.TstStats = spl.AggsToTable(etable.ColNameOnly)
 	ss.TstRunLog = ss.TstStats.Clone()
-	ss.TstRunPlot.SetTable(ss.TstRunLog)
+	if ss.TstRunPlot != nil {
+		ss.TstRunPlot.SetTable(ss.TstRunLog)
+	}
 }
 
 func (ss *Sim) ConfigTstRunLog(dt *etable.Table) {
@@ -909,6 +860,7 @@ func (ss *Sim) ConfigTstRunLog(dt *etable.Table) {
 		{"S1Act", etensor.FLOAT64, nil, nil},
 		{"S2Act", etensor.FLOAT64, nil, nil},
 		{"PctMod", etensor.FLOAT64, nil, nil},
+		{"RT", etensor.FLOAT64, nil, nil},
 	}
 	dt.SetFromSchema(sch, 0)
 }
@@ -924,6 +876,7 @@ func (ss *Sim) ConfigTstRunPlot(plt *eplot.Plot2D, dt *etable.Table) *eplot.Plot
 	plt.SetColParams("S1Act", eplot.On, eplot.FixMin, 0, eplot.FixMax, 1)
 	plt.SetColParams("S2Act", eplot.On, eplot.FixMin, 0, eplot.FixMax, 1)
 	plt.SetColParams("PctMod", eplot.On, eplot.FixMin, 0, eplot.FixMax, 1)
+	plt.SetColParams("RT", eplot.Off, eplot.FixMin, 0, eplot.FixMax, 200)
 	return plt
 }
 
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestAttnSize tests the key Reynolds & Heeger (2009) pattern: a small
+// attentional spotlight on a large stimulus produces an attentional effect
+// that grows with contrast (response gain), while a large spotlight on a small
+// stimulus only has an effect at low contrast (contrast gain).
+func TestAttnSize(t *testing.T) {
+	if os.Getenv("TEST_LONG") != "true" {
+		t.Skip("Set TEST_LONG=true env var to run longer-running tests")
+	}
+	sim := &Sim{}
+
+	sim.New()
+	sim.ViewOn = false
+	sim.Config()
+	sim.Init()
+	sim.TestAll()
+
+	dt := sim.TstTrlLog
+	if dt.Rows != len(StimAttnSizeAll) {
+		t.Fatalf("expected %d test trials, got %d", len(StimAttnSizeAll), dt.Rows)
+	}
+	// average PctMod across high contrast levels C6..C9
+	hiMod := func(cond string) float64 {
+		sum := 0.0
+		for _, c := range []string{"C6", "C7", "C8", "C9"} {
+			for i := 0; i < dt.Rows; i++ {
+				if dt.CellString("TrialName", i) == cond+"_"+c {
+					sum += dt.CellFloat("PctMod", i)
+				}
+			}
+		}
+		return sum / 4
+	}
+	respGain := hiMod("InL_AtS")
+	contGain := hiMod("InS_AtL")
+	t.Logf("high contrast PctMod: InL_AtS: %g  InS_AtL: %g", respGain, contGain)
+	if respGain < 0.1 {
+		t.Errorf("InL_AtS high contrast PctMod: %g should be >= 0.1 (response gain)", respGain)
+	}
+	if respGain < contGain+0.1 {
+		t.Errorf("InL_AtS high contrast PctMod: %g should be larger than InS_AtL: %g", respGain, contGain)
+	}
+	lowMod := 0.0
+	for i := 0; i < dt.Rows; i++ {
+		nm := dt.CellString("TrialName", i)
+		if strings.HasPrefix(nm, "InS_AtL") && !strings.HasSuffix(nm, "C0") {
+			lowMod = dt.CellFloat("PctMod", i) // first contrast with any activity
+			break
+		}
+	}
+	if lowMod <= contGain {
+		t.Errorf("InS_AtL low contrast PctMod: %g should be larger than at high contrast: %g (contrast gain)", lowMod, contGain)
+	}
+}
@@ -1,7 +1,5 @@
 // Code generated by "stringer -type=TestType"; DO NOT EDIT.
 
-//go:build this_is_broken_we_should_fix_or_delete
-
 package main
 
 import (
@@ -95,6 +95,9 @@ type LogConfig struct {
 	// if true, save final weights after each run
 	SaveWts bool `desc:"if true, save final weights after each run"`
 
+	// if true, save PNG images of the V4 receptive field weights from V1 after each run, for viewing the learned filters
+	SaveRFs bool `desc:"if true, save PNG images of the V4 receptive field weights from V1 after each run, for viewing the learned filters"`
+
 	// [def: true] if true, save train epoch log to file, as .epc.tsv typically
 	Epoch bool `def:"true" nest:"+" desc:"if true, save train epoch log to file, as .epc.tsv typically"`
 
@@ -331,6 +331,9 @@ func (ss *Sim) ConfigLoops() {
 	man.GetLoop(etime.Train, etime.Run).OnEnd.Add("SaveWeights", func() {
 		ctrString := ss.Stats.PrintVals([]string{"Run", "Epoch"}, []string{"%03d", "%05d"}, "_")
 		axon.SaveWeightsIfConfigSet(ss.Net, ss.Config.Log.SaveWts, ctrString, ss.Stats.String("RunName"))
+		if ss.Config.Log.SaveRFs {
+			axon.SaveRFImages(ss.Net, []string{"V4:V1"}, ctrString, ss.Stats.String("RunName"))
+		}
 	})
 
 	////////////////////////////////////////////
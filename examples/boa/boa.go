@@ -322,6 +322,14 @@ func (ss *Sim) ConfigNet(net *axon.Network) {
 
 	notMaint.PlaceRightOf(alm, space)
 
+	if ss.Config.Params.LCDRN {
+		ldt := net.AxonLayerByName("LDT")
+		lc := net.AddLCLayer("", nil)
+		drn := net.AddDRNLayer("", nil)
+		lc.PlaceRightOf(ldt, space)
+		drn.PlaceRightOf(lc, space)
+	}
+
 	net.Build(ctx)
 	net.Defaults()
 	net.SetNThreads(ss.Config.Run.NThreads)
@@ -52,6 +52,9 @@ type ParamConfig struct {
 	// network parameters
 	Network map[string]any `desc:"network parameters"`
 
+	// add LC (NE arousal) and DRN (5-HT patience) neuromodulatory layers -- use with the NeuroMod param sheet to have these signals affect the network
+	LCDRN bool `desc:"add LC (NE arousal) and DRN (5-HT patience) neuromodulatory layers -- use with the NeuroMod param sheet to have these signals affect the network"`
+
 	// Extra Param Sheet name(s) to use (space separated if multiple) -- must be valid name as listed in compiled-in params or loaded params
 	Sheet string `desc:"Extra Param Sheet name(s) to use (space separated if multiple) -- must be valid name as listed in compiled-in params or loaded params"`
 
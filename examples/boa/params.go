@@ -208,4 +208,14 @@ var ParamSets = netparams.Sets{
 				"Prjn.PrjnScale.Abs": "4", // 4 good -- 1,2 too weak
 			}},
 	},
+	"NeuroMod": {
+		{Sel: ".PFCLayer", Desc: "NE arousal from LC increases excitability -- requires Params.LCDRN",
+			Params: params.Params{
+				"Layer.Learn.NeuroMod.NEGain": "0.2",
+			}},
+		{Sel: ".MatrixLayer", Desc: "5-HT from DRN inhibits gating while waiting for expected reward -- requires Params.LCDRN",
+			Params: params.Params{
+				"Layer.Learn.NeuroMod.SerInhib": "0.5",
+			}},
+	},
 }
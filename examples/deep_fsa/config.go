@@ -39,6 +39,9 @@ type ParamConfig struct {
 	// network parameters
 	Network map[string]any `desc:"network parameters"`
 
+	// use a Step learning rate schedule: drops to 0.2 at epoch 40, and to the 0.1 minimum at epoch 80 -- see ApplyParams
+	LRateSched bool `desc:"use a Step learning rate schedule: drops to 0.2 at epoch 40, and to the 0.1 minimum at epoch 80 -- see ApplyParams"`
+
 	// Extra Param Sheet name(s) to use (space separated if multiple) -- must be valid name as listed in compiled-in params or loaded params
 	Sheet string `desc:"Extra Param Sheet name(s) to use (space separated if multiple) -- must be valid name as listed in compiled-in params or loaded params"`
 
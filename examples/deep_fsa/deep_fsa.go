@@ -206,6 +206,15 @@ func (ss *Sim) ApplyParams() {
 	if ss.Config.Params.Network != nil {
 		ss.Params.SetNetworkMap(ss.Net, ss.Config.Params.Network)
 	}
+	if ss.Config.Params.LRateSched {
+		ls := axon.LRateSchedParams{}
+		ls.Defaults()
+		ls.Policy = axon.LRateSchedStep
+		ls.Step = 40
+		ls.Factor = 0.2
+		ls.Min = 0.1
+		ss.Net.SetPrjnLRateSched("Prjn", &ls)
+	}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -308,18 +317,11 @@ func (ss *Sim) ConfigLoops() {
 		axon.SaveWeightsIfConfigSet(ss.Net, ss.Config.Log.SaveWts, ctrString, ss.Stats.String("RunName"))
 	})
 
-	// // lrate schedule
-	// man.GetLoop(etime.Train, etime.Epoch).OnEnd.Add("LRateSched", func() {
-	// 	trnEpc := ss.Loops.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
-	// 	switch trnEpc {
-	// 	case 40:
-	// 		// mpi.Printf("learning rate drop at: %d\n", trnEpc)
-	// 		// ss.Net.LRateSched(0.2) // 0.2
-	// 	case 60:
-	// 		// mpi.Printf("learning rate drop at: %d\n", trnEpc)
-	// 		// ss.Net.LRateSched(0.1) // 0.1
-	// 	}
-	// })
+	// lrate schedule, set in ApplyParams -- uses epoch PctErr for Plateau policy
+	axon.LooperLRateSched(man, ss.Net, func() float32 {
+		dt := ss.Logs.Table(etime.Train, etime.Epoch)
+		return float32(dt.CellFloat("PctErr", dt.Rows-1))
+	})
 
 	////////////////////////////////////////////
 	// GUI
@@ -483,6 +485,7 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddCopyFromFloatItems(etime.Train, []etime.Times{etime.Epoch, etime.Run}, etime.Test, etime.Epoch, "Tst", "CorSim", "UnitErr", "PctCor", "PctErr")
 
 	axon.LogAddPulvCorSimItems(&ss.Logs, ss.Net, etime.Train, etime.Run, etime.Epoch, etime.Trial)
+	axon.LogAddLRateSchedItems(&ss.Logs, ss.Net, etime.Train, etime.Epoch)
 
 	ss.Logs.AddPerTrlMSec("PerTrlMSec", etime.Run, etime.Epoch, etime.Trial)
 
@@ -60,6 +60,9 @@ type RunConfig struct {
 
 	// [def: 1] how often to run through all the test patterns, in terms of training epochs -- can use 0 or -1 for no testing
 	TestInterval int `def:"1" desc:"how often to run through all the test patterns, in terms of training epochs -- can use 0 or -1 for no testing"`
+
+	// [def: false] run an offline hippocampal replay epoch after each training epoch, using Hip.Replay parameters
+	Replay bool `def:"false" desc:"run an offline hippocampal replay epoch after each training epoch, using Hip.Replay parameters"`
 }
 
 // LogConfig has config parameters related to logging data
@@ -274,6 +274,10 @@ func (ss *Sim) ConfigLoops() {
 
 	man.AddStack(etime.Test).AddTime(etime.Epoch, 1).AddTimeIncr(etime.Trial, 2*trls, ss.Config.Run.NData).AddTime(etime.Cycle, 200)
 
+	if ss.Config.Run.Replay {
+		axon.AddHipReplayStack(man, &ss.Config.Hip, ss.Config.Run.NData, 200)
+	}
+
 	axon.LooperStdPhases(man, &ss.Context, ss.Net, 150, 199)            // plus phase timing
 	axon.LooperSimCycleAndLearn(man, ss.Net, &ss.Context, &ss.ViewUpdt) // std algo code
 
@@ -281,6 +285,9 @@ func (ss *Sim) ConfigLoops() {
 
 	for m, _ := range man.Stacks {
 		mode := m // For closures
+		if mode == ss.Config.Hip.Replay.Mode {
+			continue // no inputs during replay
+		}
 		stack := man.Stacks[mode]
 		stack.Loops[etime.Trial].OnStart.Add("ApplyInputs", func() {
 			ss.ApplyInputs()
@@ -289,8 +296,15 @@ func (ss *Sim) ConfigLoops() {
 
 	man.GetLoop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
 
-	// Add Testing
 	trainEpoch := man.GetLoop(etime.Train, etime.Epoch)
+	if ss.Config.Run.Replay {
+		trainEpoch.OnEnd.Add("Replay", func() {
+			ss.Loops.ResetAndRun(ss.Config.Hip.Replay.Mode)
+			ss.Loops.Mode = etime.Train // Important to reset Mode back to Train because this is called from within the Train Run.
+		})
+	}
+
+	// Add Testing
 	trainEpoch.OnEnd.Add("TestAtInterval", func() {
 		if (ss.Config.Run.TestInterval > 0) && ((trainEpoch.Counter.Cur+1)%ss.Config.Run.TestInterval == 0) {
 			// Note the +1 so that it doesn't occur at the 0th timestep.
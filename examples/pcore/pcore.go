@@ -9,6 +9,7 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"strconv"
 
@@ -81,6 +82,12 @@ type Sim struct {
 	// axon timing parameters and state
 	Context axon.Context `desc:"axon timing parameters and state"`
 
+	// [view: inline] action selection readout from the BG
+	BGSel axon.BGActionSelector `view:"inline" desc:"action selection readout from the BG"`
+
+	// settling monitor for MtxGo, measuring the reaction time for matrix Go activity to exceed threshold
+	Settle *axon.SettleMonitor `desc:"settling monitor for MtxGo, measuring the reaction time for matrix Go activity to exceed threshold"`
+
 	// [view: inline] netview update parameters
 	ViewUpdt netview.ViewUpdt `view:"inline" desc:"netview update parameters"`
 
@@ -250,6 +257,15 @@ func (ss *Sim) ConfigNet(net *axon.Network) {
 	net.SetNThreads(ss.Config.Run.NThreads)
 	ss.ApplyParams()
 	net.InitWts(ctx)
+
+	ss.BGSel.Init(mtxGo)
+	ss.BGSel.RTLay = pfcVM
+
+	var err error
+	ss.Settle, err = axon.NewSettleMonitor(mtxGo)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
 
 func (ss *Sim) ApplyParams() {
@@ -313,6 +329,7 @@ func (ss *Sim) ConfigLoops() {
 
 	axon.LooperStdPhases(man, &ss.Context, ss.Net, 150, 199)            // plus phase timing
 	axon.LooperSimCycleAndLearn(man, ss.Net, &ss.Context, &ss.ViewUpdt) // std algo code
+	ss.Settle.AddToLooper(man, &ss.Context)
 
 	for m, _ := range man.Stacks {
 		mode := m // For closures
@@ -443,20 +460,25 @@ func (ss *Sim) GatedAction() {
 	mtxly := ss.Net.AxonLayerByName("MtxGo")
 	vmly := ss.Net.AxonLayerByName("PFCVM")
 	nan := mat32.NaN()
+	ss.BGSel.Select(ctx)
 	for di := 0; di < ss.Config.Run.NData; di++ {
 		ev := ss.Envs.ByModeDi(ctx.Mode, di).(*GoNoEnv)
-		didGate := mtxly.AnyGated(uint32(di))
+		_, rt, _, noGate := ss.BGSel.Action(uint32(di))
 		action := "Gated"
-		if !didGate {
+		if noGate {
 			action = "NoGate"
 		}
 		ev.Action(action, nil)
-		rt := vmly.LayerVals(uint32(di)).RT
 		if rt > 0 {
 			ss.Stats.SetFloat32Di("PFCVM_RT", di, rt/200)
 		} else {
 			ss.Stats.SetFloat32Di("PFCVM_RT", di, nan)
 		}
+		if ss.Settle.Settled(di) {
+			ss.Stats.SetFloat32Di("MtxGo_RT", di, ss.Settle.RT[di]/200)
+		} else {
+			ss.Stats.SetFloat32Di("MtxGo_RT", di, nan)
+		}
 		ss.Stats.SetFloat32Di("PFCVM_ActAvg", di, vmly.Pool(0, uint32(di)).AvgMax.SpkMax.Cycle.Avg)
 		ss.Stats.SetFloat32Di("MtxGo_ActAvg", di, mtxly.Pool(0, uint32(di)).AvgMax.SpkMax.Cycle.Avg)
 	}
@@ -491,6 +513,7 @@ func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("Match", 0)
 	ss.Stats.SetFloat("Rew", 0)
 	ss.Stats.SetFloat("PFCVM_RT", 0.0)
+	ss.Stats.SetFloat("MtxGo_RT", 0.0)
 	ss.Stats.SetFloat("PFCVM_ActAvg", 0.0)
 	ss.Stats.SetFloat("MtxGo_ActAvg", 0.0)
 	ss.Stats.SetFloat("ACCPos", 0.0)
@@ -536,6 +559,7 @@ func (ss *Sim) TrialStats(di int) {
 	ss.Stats.SetFloat32("Match", bools.ToFloat32(ev.Match))
 	ss.Stats.SetFloat32("Rew", ev.Rew)
 	ss.Stats.SetFloat32("PFCVM_RT", ss.Stats.Float32Di("PFCVM_RT", di))
+	ss.Stats.SetFloat32("MtxGo_RT", ss.Stats.Float32Di("MtxGo_RT", di))
 	ss.Stats.SetFloat32("PFCVM_ActAvg", ss.Stats.Float32Di("PFCVM_ActAvg", di))
 	ss.Stats.SetFloat32("MtxGo_ActAvg", ss.Stats.Float32Di("MtxGo_ActAvg", di))
 }
@@ -559,6 +583,7 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddStatAggItem("Should", etime.Run, etime.Epoch, etime.Sequence)
 	ss.Logs.AddStatAggItem("Match", etime.Run, etime.Epoch, etime.Sequence)
 	ss.Logs.AddStatAggItem("PFCVM_RT", etime.Run, etime.Epoch, etime.Sequence)
+	ss.Logs.AddStatAggItem("MtxGo_RT", etime.Run, etime.Epoch, etime.Sequence)
 	ss.Logs.AddStatAggItem("PFCVM_ActAvg", etime.Run, etime.Epoch, etime.Sequence)
 	ss.Logs.AddStatAggItem("MtxGo_ActAvg", etime.Run, etime.Epoch, etime.Sequence)
 	li := ss.Logs.AddStatAggItem("Rew", etime.Run, etime.Epoch, etime.Sequence)
@@ -567,7 +592,7 @@ func (ss *Sim) ConfigLogs() {
 
 	// axon.LogAddDiagnosticItems(&ss.Logs, ss.Net, etime.Epoch, etime.Trial)
 
-	ss.Logs.PlotItems("MtxGo_ActAvg", "PFCVM_ActAvg", "PFCVM_RT", "Gated", "Should", "Match", "Rew")
+	ss.Logs.PlotItems("MtxGo_ActAvg", "PFCVM_ActAvg", "PFCVM_RT", "MtxGo_RT", "Gated", "Should", "Match", "Rew")
 
 	ss.Logs.CreateTables()
 
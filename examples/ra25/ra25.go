@@ -10,279 +10,59 @@ package main
 
 import (
 	"log"
-	"os"
 
 	"github.com/emer/axon/axon"
-	"github.com/emer/emergent/econfig"
-	"github.com/emer/emergent/egui"
-	"github.com/emer/emergent/elog"
-	"github.com/emer/emergent/emer"
-	"github.com/emer/emergent/env"
-	"github.com/emer/emergent/erand"
-	"github.com/emer/emergent/estats"
-	"github.com/emer/emergent/etime"
+	"github.com/emer/axon/axon/sim"
 	"github.com/emer/emergent/evec"
-	"github.com/emer/emergent/looper"
-	"github.com/emer/emergent/netview"
 	"github.com/emer/emergent/patgen"
 	"github.com/emer/emergent/prjn"
-	"github.com/emer/empi/mpi"
 	"github.com/emer/etable/etable"
 	"github.com/emer/etable/etensor"
-	"github.com/goki/gi/gi"
-	"github.com/goki/gi/gimain"
-	"github.com/goki/mat32"
 )
 
 func main() {
-	sim := &Sim{}
-	sim.New()
-	sim.ConfigAll()
-	if sim.Config.GUI {
-		gimain.Main(sim.RunGUI)
-	} else {
-		sim.RunNoGUI()
+	ss := NewSim()
+	if err := ss.ConfigAll(); err != nil {
+		log.Fatal(err)
 	}
-}
-
-// see params.go for params
-
-// ParamConfig has config parameters related to sim params
-type ParamConfig struct {
-
-	// network parameters
-	Network map[string]any `desc:"network parameters"`
-
-	// [def: {'X':10,'Y':10}] size of hidden layer -- can use emer.LaySize for 4D layers
-	Hidden1Size evec.Vec2i `def:"{'X':10,'Y':10}" nest:"+" desc:"size of hidden layer -- can use emer.LaySize for 4D layers"`
-
-	// [def: {'X':10,'Y':10}] size of hidden layer -- can use emer.LaySize for 4D layers
-	Hidden2Size evec.Vec2i `def:"{'X':10,'Y':10}" nest:"+" desc:"size of hidden layer -- can use emer.LaySize for 4D layers"`
-
-	// Extra Param Sheet name(s) to use (space separated if multiple) -- must be valid name as listed in compiled-in params or loaded params
-	Sheet string `desc:"Extra Param Sheet name(s) to use (space separated if multiple) -- must be valid name as listed in compiled-in params or loaded params"`
-
-	// extra tag to add to file names and logs saved from this run
-	Tag string `desc:"extra tag to add to file names and logs saved from this run"`
-
-	// user note -- describe the run params etc -- like a git commit message for the run
-	Note string `desc:"user note -- describe the run params etc -- like a git commit message for the run"`
-
-	// Name of the JSON file to input saved parameters from.
-	File string `nest:"+" desc:"Name of the JSON file to input saved parameters from."`
-
-	// Save a snapshot of all current param and config settings in a directory named params_<datestamp> (or _good if Good is true), then quit -- useful for comparing to later changes and seeing multiple views of current params
-	SaveAll bool `nest:"+" desc:"Save a snapshot of all current param and config settings in a directory named params_<datestamp> (or _good if Good is true), then quit -- useful for comparing to later changes and seeing multiple views of current params"`
-
-	// for SaveAll, save to params_good for a known good params state.  This can be done prior to making a new release after all tests are passing -- add results to git to provide a full diff record of all params over time.
-	Good bool `nest:"+" desc:"for SaveAll, save to params_good for a known good params state.  This can be done prior to making a new release after all tests are passing -- add results to git to provide a full diff record of all params over time."`
-}
-
-// RunConfig has config parameters related to running the sim
-type RunConfig struct {
-
-	// [def: true] use the GPU for computation -- generally faster even for small models if NData ~16
-	GPU bool `def:"true" desc:"use the GPU for computation -- generally faster even for small models if NData ~16"`
-
-	// [def: 16] [min: 1] number of data-parallel items to process in parallel per trial -- works (and is significantly faster) for both CPU and GPU.  Results in an effective mini-batch of learning.
-	NData int `def:"16" min:"1" desc:"number of data-parallel items to process in parallel per trial -- works (and is significantly faster) for both CPU and GPU.  Results in an effective mini-batch of learning."`
-
-	// [def: 0] number of parallel threads for CPU computation -- 0 = use default
-	NThreads int `def:"0" desc:"number of parallel threads for CPU computation -- 0 = use default"`
-
-	// [def: 0] starting run number -- determines the random seed -- runs counts from there -- can do all runs in parallel by launching separate jobs with each run, runs = 1
-	Run int `def:"0" desc:"starting run number -- determines the random seed -- runs counts from there -- can do all runs in parallel by launching separate jobs with each run, runs = 1"`
-
-	// [def: 5] [min: 1] total number of runs to do when running Train
-	NRuns int `def:"5" min:"1" desc:"total number of runs to do when running Train"`
-
-	// [def: 100] total number of epochs per run
-	NEpochs int `def:"100" desc:"total number of epochs per run"`
-
-	// [def: 2] stop run after this number of perfect, zero-error epochs
-	NZero int `def:"2" desc:"stop run after this number of perfect, zero-error epochs"`
-
-	// [def: 32] total number of trials per epoch.  Should be an even multiple of NData.
-	NTrials int `def:"32" desc:"total number of trials per epoch.  Should be an even multiple of NData."`
-
-	// [def: 5] how often to run through all the test patterns, in terms of training epochs -- can use 0 or -1 for no testing
-	TestInterval int `def:"5" desc:"how often to run through all the test patterns, in terms of training epochs -- can use 0 or -1 for no testing"`
-
-	// [def: 5] how frequently (in epochs) to compute PCA on hidden representations to measure variance?
-	PCAInterval int `def:"5" desc:"how frequently (in epochs) to compute PCA on hidden representations to measure variance?"`
-
-	// if non-empty, is the name of weights file to load at start of first run -- for testing
-	StartWts string `desc:"if non-empty, is the name of weights file to load at start of first run -- for testing"`
-}
-
-// LogConfig has config parameters related to logging data
-type LogConfig struct {
-
-	// if true, save final weights after each run
-	SaveWts bool `desc:"if true, save final weights after each run"`
-
-	// [def: true] if true, save train epoch log to file, as .epc.tsv typically
-	Epoch bool `def:"true" nest:"+" desc:"if true, save train epoch log to file, as .epc.tsv typically"`
-
-	// [def: true] if true, save run log to file, as .run.tsv typically
-	Run bool `def:"true" nest:"+" desc:"if true, save run log to file, as .run.tsv typically"`
-
-	// [def: false] if true, save train trial log to file, as .trl.tsv typically. May be large.
-	Trial bool `def:"false" nest:"+" desc:"if true, save train trial log to file, as .trl.tsv typically. May be large."`
-
-	// [def: false] if true, save testing epoch log to file, as .tst_epc.tsv typically.  In general it is better to copy testing items over to the training epoch log and record there.
-	TestEpoch bool `def:"false" nest:"+" desc:"if true, save testing epoch log to file, as .tst_epc.tsv typically.  In general it is better to copy testing items over to the training epoch log and record there."`
-
-	// [def: false] if true, save testing trial log to file, as .tst_trl.tsv typically. May be large.
-	TestTrial bool `def:"false" nest:"+" desc:"if true, save testing trial log to file, as .tst_trl.tsv typically. May be large."`
-
-	// if true, save network activation etc data from testing trials, for later viewing in netview
-	NetData bool `desc:"if true, save network activation etc data from testing trials, for later viewing in netview"`
-}
-
-// Config is a standard Sim config -- use as a starting point.
-type Config struct {
-
-	// specify include files here, and after configuration, it contains list of include files added
-	Includes []string `desc:"specify include files here, and after configuration, it contains list of include files added"`
-
-	// [def: true] open the GUI -- does not automatically run -- if false, then runs automatically and quits
-	GUI bool `def:"true" desc:"open the GUI -- does not automatically run -- if false, then runs automatically and quits"`
-
-	// log debugging information
-	Debug bool `desc:"log debugging information"`
-
-	// [view: add-fields] parameter related configuration options
-	Params ParamConfig `view:"add-fields" desc:"parameter related configuration options"`
-
-	// [view: add-fields] sim running related configuration options
-	Run RunConfig `view:"add-fields" desc:"sim running related configuration options"`
-
-	// [view: add-fields] data logging related configuration options
-	Log LogConfig `view:"add-fields" desc:"data logging related configuration options"`
-}
-
-func (cfg *Config) IncludesPtr() *[]string { return &cfg.Includes }
-
-// Sim encapsulates the entire simulation model, and we define all the
-// functionality as methods on this struct.  This structure keeps all relevant
-// state information organized and available without having to pass everything around
-// as arguments to methods, and provides the core GUI interface (note the view tags
-// for the fields which provide hints to how things should be displayed).
-type Sim struct {
-
-	// simulation configuration parameters -- set by .toml config file and / or args
-	Config Config `desc:"simulation configuration parameters -- set by .toml config file and / or args"`
-
-	// [view: no-inline] the network -- click to view / edit parameters for layers, prjns, etc
-	Net *axon.Network `view:"no-inline" desc:"the network -- click to view / edit parameters for layers, prjns, etc"`
-
-	// [view: inline] network parameter management
-	Params emer.NetParams `view:"inline" desc:"network parameter management"`
-
-	// [view: no-inline] contains looper control loops for running sim
-	Loops *looper.Manager `view:"no-inline" desc:"contains looper control loops for running sim"`
-
-	// contains computed statistic values
-	Stats estats.Stats `desc:"contains computed statistic values"`
-
-	// Contains all the logs and information about the logs.'
-	Logs elog.Logs `desc:"Contains all the logs and information about the logs.'"`
-
-	// [view: no-inline] the training patterns to use
-	Pats *etable.Table `view:"no-inline" desc:"the training patterns to use"`
-
-	// [view: no-inline] Environments
-	Envs env.Envs `view:"no-inline" desc:"Environments"`
-
-	// axon timing parameters and state
-	Context axon.Context `desc:"axon timing parameters and state"`
-
-	// [view: inline] netview update parameters
-	ViewUpdt netview.ViewUpdt `view:"inline" desc:"netview update parameters"`
-
-	// [view: -] manages all the gui elements
-	GUI egui.GUI `view:"-" desc:"manages all the gui elements"`
-
-	// [view: -] a list of random seeds to use for each run
-	RndSeeds erand.Seeds `view:"-" desc:"a list of random seeds to use for each run"`
-}
-
-// New creates new blank elements and initializes defaults
-func (ss *Sim) New() {
-	econfig.Config(&ss.Config, "config.toml")
-	ss.Net = &axon.Network{}
-	ss.Params.Config(ParamSets, ss.Config.Params.Sheet, ss.Config.Params.Tag, ss.Net)
-	ss.Stats.Init()
-	ss.Pats = &etable.Table{}
-	ss.RndSeeds.Init(100) // max 100 runs
-	ss.InitRndSeed(0)
-	ss.Context.Defaults()
-}
-
-////////////////////////////////////////////////////////////////////////////////////////////
-// 		Configs
-
-// ConfigAll configures all the elements using the standard functions
-func (ss *Sim) ConfigAll() {
-	// ss.ConfigPats()
-	ss.OpenPats()
-	ss.ConfigEnv()
-	ss.ConfigNet(ss.Net)
-	ss.ConfigLogs()
-	ss.ConfigLoops()
-	if ss.Config.Params.SaveAll {
-		ss.Config.Params.SaveAll = false
-		ss.Net.SaveParamsSnapshot(&ss.Params.Params, &ss.Config, ss.Config.Params.Good)
-		os.Exit(0)
+	if err := ss.Run(); err != nil {
+		log.Fatal(err)
 	}
 }
 
-func (ss *Sim) ConfigEnv() {
-	// Can be called multiple times -- don't re-create
-	var trn, tst *env.FixedTable
-	if len(ss.Envs) == 0 {
-		trn = &env.FixedTable{}
-		tst = &env.FixedTable{}
-	} else {
-		trn = ss.Envs.ByMode(etime.Train).(*env.FixedTable)
-		tst = ss.Envs.ByMode(etime.Test).(*env.FixedTable)
-	}
-
-	// note: names must be standard here!
-	trn.Nm = etime.Train.String()
-	trn.Dsc = "training params and state"
-	trn.Config(etable.NewIdxView(ss.Pats))
-	trn.Validate()
-
-	tst.Nm = etime.Test.String()
-	tst.Dsc = "testing params and state"
-	tst.Config(etable.NewIdxView(ss.Pats))
-	tst.Sequential = true
-	tst.Validate()
+// see params.go for params
 
-	// note: to create a train / test split of pats, do this:
-	// all := etable.NewIdxView(ss.Pats)
+// NewSim returns a new sim.Sim for this model, using the standard
+// loops, stats, logs and GUI, configured from config.toml and args.
+// The network is configured in ConfigNet, and the environments
+// present the patterns in random_5x5_25.tsv.
+// The hidden layer sizes can be set via Params.LayerSizes in the config,
+// e.g., a [Params.LayerSizes.Hidden1] section in a config.toml file.
+func NewSim() *sim.Sim {
+	ss := sim.New("RA25", ParamSets)
+	ss.Title = "Axon Random Associator"
+	ss.About = `This demonstrates a basic Axon model. See <a href="https://github.com/emer/emergent">emergent on GitHub</a>.</p>`
+	ss.ReadMe = "https://github.com/emer/axon/blob/master/examples/ra25/README.md"
+	ss.LoadConfig("config.toml")
+	ss.ConfigNetFunc = ConfigNet
+	// ConfigPats()
+	ss.NewEnv = sim.FixedTableEnvs(OpenPats())
+	// note: to create a train / test split of pats, write a NewEnv function that does this:
+	// all := etable.NewIdxView(pats)
 	// splits, _ := split.Permuted(all, []float64{.8, .2}, []string{"Train", "Test"})
 	// trn.Table = splits.Splits[0]
 	// tst.Table = splits.Splits[1]
-
-	trn.Init(0)
-	tst.Init(0)
-
-	// note: names must be in place when adding
-	ss.Envs.Add(trn, tst)
+	return ss
 }
 
-func (ss *Sim) ConfigNet(net *axon.Network) {
-	ctx := &ss.Context
-	net.InitName(net, "RA25")
-	net.SetMaxData(ctx, ss.Config.Run.NData)
-	net.SetRndSeed(ss.RndSeeds[0]) // init new separate random seed, using run = 0
+// ConfigNet adds the layers and projections to the network.
+func ConfigNet(ss *sim.Sim, net *axon.Network) {
+	hid1Sz := ss.LayerSize("Hidden1", evec.Vec2i{X: 10, Y: 10})
+	hid2Sz := ss.LayerSize("Hidden2", evec.Vec2i{X: 10, Y: 10})
 
 	inp := net.AddLayer2D("Input", 5, 5, axon.InputLayer)
-	hid1 := net.AddLayer2D("Hidden1", ss.Config.Params.Hidden1Size.Y, ss.Config.Params.Hidden1Size.X, axon.SuperLayer)
-	hid2 := net.AddLayer2D("Hidden2", ss.Config.Params.Hidden2Size.Y, ss.Config.Params.Hidden2Size.X, axon.SuperLayer)
+	hid1 := net.AddLayer2D("Hidden1", hid1Sz.Y, hid1Sz.X, axon.SuperLayer)
+	hid2 := net.AddLayer2D("Hidden2", hid2Sz.Y, hid2Sz.X, axon.SuperLayer)
 	out := net.AddLayer2D("Output", 5, 5, axon.TargetLayer)
 
 	// use this to position layers relative to each other
@@ -302,206 +82,15 @@ func (ss *Sim) ConfigNet(net *axon.Network) {
 	// out.SetType(emer.Compare)
 	// that would mean that the output layer doesn't reflect target values in plus phase
 	// and thus removes error-driven learning -- but stats are still computed.
-
-	net.Build(ctx)
-	net.Defaults()
-	net.SetNThreads(ss.Config.Run.NThreads)
-	ss.ApplyParams()
-	net.InitWts(ctx)
-}
-
-func (ss *Sim) ApplyParams() {
-	ss.Params.SetAll()
-	if ss.Config.Params.Network != nil {
-		ss.Params.SetNetworkMap(ss.Net, ss.Config.Params.Network)
-	}
-}
-
-////////////////////////////////////////////////////////////////////////////////
-// 	    Init, utils
-
-// Init restarts the run, and initializes everything, including network weights
-// and resets the epoch log table
-func (ss *Sim) Init() {
-	if ss.Config.GUI {
-		ss.Stats.SetString("RunName", ss.Params.RunName(0)) // in case user interactively changes tag
-	}
-	ss.Loops.ResetCounters()
-	ss.InitRndSeed(0)
-	// ss.ConfigEnv() // re-config env just in case a different set of patterns was
-	// selected or patterns have been modified etc
-	ss.GUI.StopNow = false
-	ss.ApplyParams()
-	ss.Net.GPU.SyncParamsToGPU()
-	ss.NewRun()
-	ss.ViewUpdt.Update()
-	ss.ViewUpdt.RecordSyns()
-}
-
-// InitRndSeed initializes the random seed based on current training run number
-func (ss *Sim) InitRndSeed(run int) {
-	ss.RndSeeds.Set(run)
-	ss.RndSeeds.Set(run, &ss.Net.Rand)
-}
-
-// ConfigLoops configures the control loops: Training, Testing
-func (ss *Sim) ConfigLoops() {
-	man := looper.NewManager()
-
-	trls := int(mat32.IntMultipleGE(float32(ss.Config.Run.NTrials), float32(ss.Config.Run.NData)))
-
-	man.AddStack(etime.Train).
-		AddTime(etime.Run, ss.Config.Run.NRuns).
-		AddTime(etime.Epoch, ss.Config.Run.NEpochs).
-		AddTimeIncr(etime.Trial, trls, ss.Config.Run.NData).
-		AddTime(etime.Cycle, 200)
-
-	man.AddStack(etime.Test).
-		AddTime(etime.Epoch, 1).
-		AddTimeIncr(etime.Trial, trls, ss.Config.Run.NData).
-		AddTime(etime.Cycle, 200)
-
-	axon.LooperStdPhases(man, &ss.Context, ss.Net, 150, 199)            // plus phase timing
-	axon.LooperSimCycleAndLearn(man, ss.Net, &ss.Context, &ss.ViewUpdt) // std algo code
-
-	for m, _ := range man.Stacks {
-		mode := m // For closures
-		stack := man.Stacks[mode]
-		stack.Loops[etime.Trial].OnStart.Add("ApplyInputs", func() {
-			ss.ApplyInputs()
-		})
-	}
-
-	man.GetLoop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
-
-	// Train stop early condition
-	man.GetLoop(etime.Train, etime.Epoch).IsDone["NZeroStop"] = func() bool {
-		// This is calculated in TrialStats
-		stopNz := ss.Config.Run.NZero
-		if stopNz <= 0 {
-			stopNz = 2
-		}
-		curNZero := ss.Stats.Int("NZero")
-		stop := curNZero >= stopNz
-		return stop
-	}
-
-	// Add Testing
-	trainEpoch := man.GetLoop(etime.Train, etime.Epoch)
-	trainEpoch.OnStart.Add("TestAtInterval", func() {
-		if (ss.Config.Run.TestInterval > 0) && ((trainEpoch.Counter.Cur+1)%ss.Config.Run.TestInterval == 0) {
-			// Note the +1 so that it doesn't occur at the 0th timestep.
-			ss.TestAll()
-		}
-	})
-
-	/////////////////////////////////////////////
-	// Logging
-
-	man.GetLoop(etime.Test, etime.Epoch).OnEnd.Add("LogTestErrors", func() {
-		axon.LogTestErrors(&ss.Logs)
-	})
-	man.GetLoop(etime.Train, etime.Epoch).OnEnd.Add("PCAStats", func() {
-		trnEpc := man.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
-		if ss.Config.Run.PCAInterval > 0 && trnEpc%ss.Config.Run.PCAInterval == 0 {
-			axon.PCAStats(ss.Net, &ss.Logs, &ss.Stats)
-			ss.Logs.ResetLog(etime.Analyze, etime.Trial)
-		}
-	})
-
-	man.AddOnEndToAll("Log", ss.Log)
-	axon.LooperResetLogBelow(man, &ss.Logs)
-
-	man.GetLoop(etime.Train, etime.Trial).OnEnd.Add("LogAnalyze", func() {
-		trnEpc := man.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
-		if (ss.Config.Run.PCAInterval > 0) && (trnEpc%ss.Config.Run.PCAInterval == 0) {
-			ss.Log(etime.Analyze, etime.Trial)
-		}
-	})
-
-	man.GetLoop(etime.Train, etime.Run).OnEnd.Add("RunStats", func() {
-		ss.Logs.RunStats("PctCor", "FirstZero", "LastZero")
-	})
-
-	// Save weights to file, to look at later
-	man.GetLoop(etime.Train, etime.Run).OnEnd.Add("SaveWeights", func() {
-		ctrString := ss.Stats.PrintVals([]string{"Run", "Epoch"}, []string{"%03d", "%05d"}, "_")
-		axon.SaveWeightsIfConfigSet(ss.Net, ss.Config.Log.SaveWts, ctrString, ss.Stats.String("RunName"))
-	})
-
-	////////////////////////////////////////////
-	// GUI
-
-	if !ss.Config.GUI {
-		if ss.Config.Log.NetData {
-			man.GetLoop(etime.Test, etime.Trial).Main.Add("NetDataRecord", func() {
-				ss.GUI.NetDataRecord(ss.ViewUpdt.Text)
-			})
-		}
-	} else {
-		axon.LooperUpdtNetView(man, &ss.ViewUpdt, ss.Net, ss.NetViewCounters)
-		axon.LooperUpdtPlots(man, &ss.GUI)
-	}
-
-	if ss.Config.Debug {
-		mpi.Println(man.DocString())
-	}
-	ss.Loops = man
-}
-
-// ApplyInputs applies input patterns from given environment.
-// It is good practice to have this be a separate method with appropriate
-// args so that it can be used for various different contexts
-// (training, testing, etc).
-func (ss *Sim) ApplyInputs() {
-	ctx := &ss.Context
-	net := ss.Net
-	ev := ss.Envs.ByMode(ctx.Mode).(*env.FixedTable)
-	lays := net.LayersByType(axon.InputLayer, axon.TargetLayer)
-	net.InitExt(ctx)
-	for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
-		ev.Step()
-		// note: must save env state for logging / stats due to data parallel re-use of same env
-		ss.Stats.SetStringDi("TrialName", int(di), ev.TrialName.Cur)
-		for _, lnm := range lays {
-			ly := ss.Net.AxonLayerByName(lnm)
-			pats := ev.State(ly.Nm)
-			if pats != nil {
-				ly.ApplyExt(ctx, di, pats)
-			}
-		}
-	}
-	net.ApplyExts(ctx) // now required for GPU mode
-}
-
-// NewRun intializes a new run of the model, using the TrainEnv.Run counter
-// for the new run value
-func (ss *Sim) NewRun() {
-	ctx := &ss.Context
-	ss.InitRndSeed(ss.Loops.GetLoop(etime.Train, etime.Run).Counter.Cur)
-	ss.Envs.ByMode(etime.Train).Init(0)
-	ss.Envs.ByMode(etime.Test).Init(0)
-	ctx.Reset()
-	ctx.Mode = etime.Train
-	ss.Net.InitWts(ctx)
-	ss.InitStats()
-	ss.StatCounters(0)
-	ss.Logs.ResetLog(etime.Train, etime.Epoch)
-	ss.Logs.ResetLog(etime.Test, etime.Epoch)
-}
-
-// TestAll runs through the full set of testing items
-func (ss *Sim) TestAll() {
-	ss.Envs.ByMode(etime.Test).Init(0)
-	ss.Loops.ResetAndRun(etime.Test)
-	ss.Loops.Mode = etime.Train // Important to reset Mode back to Train because this is called from within the Train Run.
 }
 
 /////////////////////////////////////////////////////////////////////////
 //   Pats
 
-func (ss *Sim) ConfigPats() {
-	dt := ss.Pats
+// ConfigPats generates a new set of random patterns, saving them to
+// random_5x5_25_gen.tsv
+func ConfigPats() *etable.Table {
+	dt := &etable.Table{}
 	dt.SetMetaData("name", "TrainPats")
 	dt.SetMetaData("desc", "Training patterns")
 	sch := etable.Schema{
@@ -514,261 +103,17 @@ func (ss *Sim) ConfigPats() {
 	patgen.PermutedBinaryMinDiff(dt.Cols[1].(*etensor.Float32), 6, 1, 0, 3)
 	patgen.PermutedBinaryMinDiff(dt.Cols[2].(*etensor.Float32), 6, 1, 0, 3)
 	dt.SaveCSV("random_5x5_25_gen.tsv", etable.Tab, etable.Headers)
+	return dt
 }
 
-func (ss *Sim) OpenPats() {
-	dt := ss.Pats
+// OpenPats opens the training patterns from random_5x5_25.tsv
+func OpenPats() *etable.Table {
+	dt := &etable.Table{}
 	dt.SetMetaData("name", "TrainPats")
 	dt.SetMetaData("desc", "Training patterns")
 	err := dt.OpenCSV("random_5x5_25.tsv", etable.Tab)
 	if err != nil {
 		log.Println(err)
 	}
-}
-
-////////////////////////////////////////////////////////////////////////////////////////////
-// 		Stats
-
-// InitStats initializes all the statistics.
-// called at start of new run
-func (ss *Sim) InitStats() {
-	ss.Stats.SetFloat("UnitErr", 0.0)
-	ss.Stats.SetFloat("CorSim", 0.0)
-	ss.Stats.SetString("TrialName", "")
-	ss.Logs.InitErrStats() // inits TrlErr, FirstZero, LastZero, NZero
-}
-
-// StatCounters saves current counters to Stats, so they are available for logging etc
-// Also saves a string rep of them for ViewUpdt.Text
-func (ss *Sim) StatCounters(di int) {
-	ctx := &ss.Context
-	mode := ctx.Mode
-	ss.Loops.Stacks[mode].CtrsToStats(&ss.Stats)
-	// always use training epoch..
-	trnEpc := ss.Loops.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
-	ss.Stats.SetInt("Epoch", trnEpc)
-	trl := ss.Stats.Int("Trial")
-	ss.Stats.SetInt("Trial", trl+di)
-	ss.Stats.SetInt("Di", di)
-	ss.Stats.SetInt("Cycle", int(ctx.Cycle))
-	ss.Stats.SetString("TrialName", ss.Stats.StringDi("TrialName", di))
-}
-
-func (ss *Sim) NetViewCounters(tm etime.Times) {
-	if ss.ViewUpdt.View == nil {
-		return
-	}
-	di := ss.ViewUpdt.View.Di
-	if tm == etime.Trial {
-		ss.TrialStats(di) // get trial stats for current di
-	}
-	ss.StatCounters(di)
-	ss.ViewUpdt.Text = ss.Stats.Print([]string{"Run", "Epoch", "Trial", "Di", "TrialName", "Cycle", "UnitErr", "TrlErr", "CorSim"})
-}
-
-// TrialStats computes the trial-level statistics.
-// Aggregation is done directly from log data.
-func (ss *Sim) TrialStats(di int) {
-	out := ss.Net.AxonLayerByName("Output")
-
-	ss.Stats.SetFloat("CorSim", float64(out.Vals[di].CorSim.Cor))
-	ss.Stats.SetFloat("UnitErr", out.PctUnitErr(&ss.Context)[di])
-
-	if ss.Stats.Float("UnitErr") > 0 {
-		ss.Stats.SetFloat("TrlErr", 1)
-	} else {
-		ss.Stats.SetFloat("TrlErr", 0)
-	}
-}
-
-//////////////////////////////////////////////////////////////////////////////
-// 		Logging
-
-func (ss *Sim) ConfigLogs() {
-	ss.Stats.SetString("RunName", ss.Params.RunName(0)) // used for naming logs, stats, etc
-
-	ss.Logs.AddCounterItems(etime.Run, etime.Epoch, etime.Trial, etime.Cycle)
-	ss.Logs.AddStatIntNoAggItem(etime.AllModes, etime.Trial, "Di")
-	ss.Logs.AddStatStringItem(etime.AllModes, etime.AllTimes, "RunName")
-	ss.Logs.AddStatStringItem(etime.AllModes, etime.Trial, "TrialName")
-
-	ss.Logs.AddStatAggItem("CorSim", etime.Run, etime.Epoch, etime.Trial)
-	ss.Logs.AddStatAggItem("UnitErr", etime.Run, etime.Epoch, etime.Trial)
-	ss.Logs.AddErrStatAggItems("TrlErr", etime.Run, etime.Epoch, etime.Trial)
-
-	ss.Logs.AddCopyFromFloatItems(etime.Train, []etime.Times{etime.Epoch, etime.Run}, etime.Test, etime.Epoch, "Tst", "CorSim", "UnitErr", "PctCor", "PctErr")
-
-	ss.Logs.AddPerTrlMSec("PerTrlMSec", etime.Run, etime.Epoch, etime.Trial)
-
-	layers := ss.Net.LayersByType(axon.SuperLayer, axon.CTLayer, axon.TargetLayer)
-	axon.LogAddDiagnosticItems(&ss.Logs, layers, etime.Train, etime.Epoch, etime.Trial)
-	axon.LogInputLayer(&ss.Logs, ss.Net, etime.Train)
-
-	axon.LogAddPCAItems(&ss.Logs, ss.Net, etime.Train, etime.Run, etime.Epoch, etime.Trial)
-
-	ss.Logs.AddLayerTensorItems(ss.Net, "Act", etime.Test, etime.Trial, "InputLayer", "TargetLayer")
-
-	ss.Logs.PlotItems("CorSim", "PctCor", "FirstZero", "LastZero")
-
-	ss.Logs.CreateTables()
-	ss.Logs.SetContext(&ss.Stats, ss.Net)
-	// don't plot certain combinations we don't use
-	ss.Logs.NoPlot(etime.Train, etime.Cycle)
-	ss.Logs.NoPlot(etime.Test, etime.Run)
-	// note: Analyze not plotted by default
-	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
-}
-
-// Log is the main logging function, handles special things for different scopes
-func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
-	ctx := &ss.Context
-	if mode != etime.Analyze {
-		ctx.Mode = mode // Also set specifically in a Loop callback.
-	}
-	dt := ss.Logs.Table(mode, time)
-	if dt == nil {
-		return
-	}
-	row := dt.Rows
-
-	switch {
-	case time == etime.Cycle:
-		return
-	case time == etime.Trial:
-		for di := 0; di < int(ctx.NetIdxs.NData); di++ {
-			ss.TrialStats(di)
-			ss.StatCounters(di)
-			ss.Logs.LogRowDi(mode, time, row, di)
-		}
-		return // don't do reg below
-	}
-
-	ss.Logs.LogRow(mode, time, row) // also logs to file, etc
-}
-
-////////////////////////////////////////////////////////////////////////////////////////////
-// 		Gui
-
-// ConfigGui configures the GoGi gui interface for this simulation,
-func (ss *Sim) ConfigGui() *gi.Window {
-	title := "Axon Random Associator"
-	ss.GUI.MakeWindow(ss, "ra25", title, `This demonstrates a basic Axon model. See <a href="https://github.com/emer/emergent">emergent on GitHub</a>.</p>`)
-	ss.GUI.CycleUpdateInterval = 10
-
-	nv := ss.GUI.AddNetView("NetView")
-	nv.Params.MaxRecs = 300
-	nv.SetNet(ss.Net)
-	ss.ViewUpdt.Config(nv, etime.Phase, etime.Phase)
-	ss.GUI.ViewUpdt = &ss.ViewUpdt
-
-	nv.Scene().Camera.Pose.Pos.Set(0, 1, 2.75) // more "head on" than default which is more "top down"
-	nv.Scene().Camera.LookAt(mat32.Vec3{0, 0, 0}, mat32.Vec3{0, 1, 0})
-
-	ss.GUI.AddPlots(title, &ss.Logs)
-
-	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "Init", Icon: "update",
-		Tooltip: "Initialize everything including network weights, and start over.  Also applies current params.",
-		Active:  egui.ActiveStopped,
-		Func: func() {
-			ss.Init()
-			ss.GUI.UpdateWindow()
-		},
-	})
-
-	ss.GUI.AddLooperCtrl(ss.Loops, []etime.Modes{etime.Train, etime.Test})
-
-	////////////////////////////////////////////////
-	ss.GUI.ToolBar.AddSeparator("log")
-	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "Reset RunLog",
-		Icon:    "reset",
-		Tooltip: "Reset the accumulated log of all Runs, which are tagged with the ParamSet used",
-		Active:  egui.ActiveAlways,
-		Func: func() {
-			ss.Logs.ResetLog(etime.Train, etime.Run)
-			ss.GUI.UpdatePlot(etime.Train, etime.Run)
-		},
-	})
-	////////////////////////////////////////////////
-	ss.GUI.ToolBar.AddSeparator("misc")
-	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "New Seed",
-		Icon:    "new",
-		Tooltip: "Generate a new initial random seed to get different results.  By default, Init re-establishes the same initial seed every time.",
-		Active:  egui.ActiveAlways,
-		Func: func() {
-			ss.RndSeeds.NewSeeds()
-		},
-	})
-	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "README",
-		Icon:    "file-markdown",
-		Tooltip: "Opens your browser on the README file that contains instructions for how to run this model.",
-		Active:  egui.ActiveAlways,
-		Func: func() {
-			gi.OpenURL("https://github.com/emer/axon/blob/master/examples/ra25/README.md")
-		},
-	})
-	ss.GUI.FinalizeGUI(false)
-	if ss.Config.Run.GPU {
-		// vgpu.Debug = ss.Config.Debug // when debugging GPU..
-		ss.Net.ConfigGPUwithGUI(&ss.Context) // must happen after gui or no gui
-		gi.SetQuitCleanFunc(func() {
-			ss.Net.GPU.Destroy()
-		})
-	}
-	return ss.GUI.Win
-}
-
-func (ss *Sim) RunGUI() {
-	ss.Init()
-	win := ss.ConfigGui()
-	win.StartEventLoop()
-}
-
-func (ss *Sim) RunNoGUI() {
-	if ss.Config.Params.Note != "" {
-		mpi.Printf("Note: %s\n", ss.Config.Params.Note)
-	}
-	if ss.Config.Log.SaveWts {
-		mpi.Printf("Saving final weights per run\n")
-	}
-	runName := ss.Params.RunName(ss.Config.Run.Run)
-	ss.Stats.SetString("RunName", runName) // used for naming logs, stats, etc
-	netName := ss.Net.Name()
-
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.Trial, etime.Train, etime.Trial, "trl", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.Epoch, etime.Train, etime.Epoch, "epc", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.Run, etime.Train, etime.Run, "run", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.TestEpoch, etime.Test, etime.Epoch, "tst_epc", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.TestTrial, etime.Test, etime.Trial, "tst_trl", netName, runName)
-
-	netdata := ss.Config.Log.NetData
-	if netdata {
-		mpi.Printf("Saving NetView data from testing\n")
-		ss.GUI.InitNetData(ss.Net, 200)
-	}
-
-	ss.Init()
-
-	mpi.Printf("Running %d Runs starting at %d\n", ss.Config.Run.NRuns, ss.Config.Run.Run)
-	ss.Loops.GetLoop(etime.Train, etime.Run).Counter.SetCurMaxPlusN(ss.Config.Run.Run, ss.Config.Run.NRuns)
-
-	if ss.Config.Run.StartWts != "" { // this is just for testing -- not usually needed
-		ss.Loops.Step(etime.Train, 1, etime.Trial) // get past NewRun
-		ss.Net.OpenWtsJSON(gi.FileName(ss.Config.Run.StartWts))
-		mpi.Printf("Starting with initial weights from: %s\n", ss.Config.Run.StartWts)
-	}
-
-	if ss.Config.Run.GPU {
-		ss.Net.ConfigGPUnoGUI(&ss.Context)
-	}
-	mpi.Printf("Set NThreads to: %d\n", ss.Net.NThreads)
-
-	ss.Loops.Run(etime.Train)
-
-	ss.Logs.CloseLogFiles()
-
-	if netdata {
-		ss.GUI.SaveNetData(ss.Stats.String("RunName"))
-	}
-
-	ss.Net.GPU.Destroy() // safe even if no GPU
+	return dt
 }
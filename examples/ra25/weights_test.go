@@ -16,16 +16,16 @@ func TestWeightsSave(t *testing.T) {
 	if os.Getenv("TEST_LONG") != "true" {
 		t.Skip("Set TEST_LONG=true env var to run longer-running tests")
 	}
-	sim := &Sim{}
-
-	sim.New()
+	sim := NewSim()
 
 	sim.Config.Log.Epoch = false
 	sim.Config.Log.Run = false
 	sim.Config.Run.GPU = false
 	sim.Config.Run.NRuns = 1
 
-	sim.ConfigAll()
+	if err := sim.ConfigAll(); err != nil {
+		t.Fatal(err)
+	}
 	sim.RunNoGUI()
 
 	sim.Net.SaveWtsJSON(gi.FileName("wtstest.wts.gz"))
@@ -36,10 +36,10 @@ func TestWeightsLoad(t *testing.T) {
 	if os.Getenv("TEST_LONG") != "true" {
 		t.Skip("Set TEST_LONG=true env var to run longer-running tests")
 	}
-	sim := &Sim{}
-
-	sim.New()
-	sim.ConfigAll()
+	sim := NewSim()
+	if err := sim.ConfigAll(); err != nil {
+		t.Fatal(err)
+	}
 
 	sim.Init()
 
@@ -60,10 +60,10 @@ func TestWeightsLoadGPU(t *testing.T) {
 	if os.Getenv("TEST_LONG") != "true" {
 		t.Skip("Set TEST_LONG=true env var to run longer-running tests")
 	}
-	sim := &Sim{}
-
-	sim.New()
-	sim.ConfigAll()
+	sim := NewSim()
+	if err := sim.ConfigAll(); err != nil {
+		t.Fatal(err)
+	}
 
 	sim.Init()
 
@@ -86,9 +86,7 @@ func TestWeightsTrain(t *testing.T) {
 	if os.Getenv("TEST_LONG") != "true" {
 		t.Skip("Set TEST_LONG=true env var to run longer-running tests")
 	}
-	sim := &Sim{}
-
-	sim.New()
+	sim := NewSim()
 
 	sim.Config.GUI = false
 	sim.Config.Log.Epoch = false
@@ -98,7 +96,9 @@ func TestWeightsTrain(t *testing.T) {
 	sim.Config.Run.NRuns = 1
 	sim.Config.Run.StartWts = "wtstest.wts.gz"
 
-	sim.ConfigAll()
+	if err := sim.ConfigAll(); err != nil {
+		t.Fatal(err)
+	}
 
 	sim.RunNoGUI()
 
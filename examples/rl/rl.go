@@ -266,8 +266,12 @@ func (ss *Sim) ApplyInputs() {
 	ev := ss.Envs.ByMode(ctx.Mode).(*CondEnv)
 	lays := []string{"Input"}
 	ss.Net.InitExt(ctx)
+	var newEps []uint32
 	for di := uint32(0); di < ctx.NetIdxs.NData; di++ {
 		ev.Step()
+		if ev.Event.Cur == 0 { // new episode: reset TD(lambda) trace
+			newEps = append(newEps, di)
+		}
 		for _, lnm := range lays {
 			ly := ss.Net.AxonLayerByName(lnm)
 			pats := ev.State(ly.Nm)
@@ -278,6 +282,9 @@ func (ss *Sim) ApplyInputs() {
 		}
 		axon.GlobalSetRew(ctx, di, float32(ev.Reward.Values[0]), ev.HasRew)
 	}
+	if len(newEps) > 0 {
+		ss.Net.InitTDPredTrace(ctx, newEps...)
+	}
 	ss.Net.ApplyExts(ctx)
 }
 
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+)
+
+// runTD runs the TD model on the default CondEnv for given number of epochs,
+// with given TD(lambda) trace decay, and returns the learned weight from
+// the CS onset input to the positive RewPred unit.
+func runTD(lambda float32, nepochs int) float32 {
+	sim := &Sim{}
+
+	sim.New()
+
+	sim.Config.GUI = false
+	sim.Config.Run.GPU = false // for CI
+	sim.Config.Run.NRuns = 1
+	sim.Config.Run.NEpochs = nepochs
+	sim.Config.Log.Epoch = false
+	sim.Config.Log.Run = false
+
+	sim.ConfigAll()
+	rp := sim.Net.AxonLayerByName("RewPred")
+	pj := rp.RcvPrjns[0]
+	pj.Params.RLPred.Lambda = lambda
+	sim.RunNoGUI()
+
+	ev := sim.Envs.ByMode(sim.Context.Mode).(*CondEnv)
+	return pj.SynVal("Wt", ev.CSA.On, 0)
+}
+
+// TestTDLambda tests that the TD(lambda) eligibility trace propagates
+// value back from the US to the CS onset faster than TD(0).
+func TestTDLambda(t *testing.T) {
+	nepochs := 3
+	td0 := runTD(0, nepochs)
+	tdl := runTD(0.9, nepochs)
+	if tdl <= td0 {
+		t.Errorf("TD(lambda) CS onset weight: %g is not greater than TD(0): %g\n", tdl, td0)
+	}
+	if tdl < 0.02 {
+		t.Errorf("TD(lambda) CS onset weight: %g is below threshold of .02\n", tdl)
+	}
+}